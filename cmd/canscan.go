@@ -0,0 +1,71 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	canScanLongHelp = `
+Check whether rakkess can produce complete results
+
+Rakkess needs to create SelfSubjectAccessReviews for the access matrix, and to
+list (Cluster)Roles plus their bindings for the resource subcommand. This
+command reviews these permissions upfront, so that missing permissions do not
+go unnoticed as partial results later.
+
+The command exits with a non-zero exit code if any permission is missing.
+`
+
+	canScanExamples = `
+  Check permissions for cluster-scoped reviews
+   $ rakkess can-scan
+
+  Check permissions for reviews in the default namespace
+   $ rakkess can-scan --namespace default
+
+  Check permissions of another user
+   $ rakkess can-scan --as other-user
+`
+)
+
+var canScanCmd = &cobra.Command{
+	Use:     "can-scan",
+	Short:   "Check whether rakkess can produce complete results",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(canScanLongHelp),
+	Example: constants.HelpTextMapName(canScanExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.CanScan(ctx, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(canScanCmd)
+
+	canScanCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	opts.ConfigFlags.AddFlags(canScanCmd.Flags())
+}
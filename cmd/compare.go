@@ -0,0 +1,76 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	compareNamespacesLongHelp = `
+Show the differences in subject access between two namespaces
+
+Determines the subjects with access to the given resource in each namespace,
+the same way as the resource subcommand does, and only shows the grants which
+differ. Access which is only granted in the second namespace is marked as
+allowed, access which is only granted in the first namespace is marked as
+denied. This is useful to detect configuration drift between tenants.
+`
+
+	compareNamespacesExamples = `
+  Compare who can access secrets in two tenant namespaces
+   $ rakkess compare-namespaces tenant-a tenant-b --resource secrets
+
+  Compare access to deployments for all verbs
+   $ rakkess compare-namespaces tenant-a tenant-b --resource deployments.apps --verbs all
+`
+)
+
+var compareResource string
+
+var compareNamespacesCmd = &cobra.Command{
+	Use:     "compare-namespaces <namespace-a> <namespace-b>",
+	Short:   "Show the differences in subject access between two namespaces",
+	Args:    cobra.ExactArgs(2),
+	Long:    constants.HelpTextMapName(compareNamespacesLongHelp),
+	Example: constants.HelpTextMapName(compareNamespacesExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.CompareNamespaces(ctx, opts, compareResource, args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareNamespacesCmd)
+
+	compareNamespacesCmd.Flags().StringVar(&compareResource, constants.FlagResource, "", "the resource to compare access for, optionally qualified with its API group")
+	_ = compareNamespacesCmd.MarkFlagRequired(constants.FlagResource)
+	compareNamespacesCmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list", "create", "update", "delete"}, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidVerbs, ", ")))
+	compareNamespacesCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	compareNamespacesCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	compareNamespacesCmd.Flags().StringSliceVar(&opts.SubjectNormalizer, constants.FlagSubjectNormalizer, []string{constants.NormalizeNone}, fmt.Sprintf("merge subjects which are equal after normalizing user and group names, out of (%s). Several normalizers are applied in order, e.g. lowercase,strip-domain.", strings.Join(constants.SubjectNormalizers, ", ")))
+	compareNamespacesCmd.Flags().BoolVar(&opts.IgnoreMasters, constants.FlagIgnoreMasters, false, "exclude grants via the group system:masters and the ClusterRole cluster-admin")
+	compareNamespacesCmd.Flags().StringVar(&opts.ResourceVersion, constants.FlagResourceVersion, "", "list all RBAC objects at this exact resourceVersion to get a consistent snapshot. Fails if the resourceVersion is already compacted by the API server.")
+	opts.ConfigFlags.AddFlags(compareNamespacesCmd.Flags())
+}
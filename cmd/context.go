@@ -0,0 +1,49 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/pkg/errors"
+)
+
+// newContext returns the context of a command. It is cancelled on Ctrl-C, and
+// when the given timeout expires, unless the timeout is zero.
+func newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	catchCtrlC(cancel)
+	return ctx, cancel
+}
+
+// timeoutErr replaces errors caused by an expired --timeout by a clear
+// message. Other errors are returned unchanged.
+func timeoutErr(err error, timeout time.Duration) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("timed out after %s, the API server may be slow or unavailable (use --%s to wait longer)", timeout, constants.FlagTimeout)
+}
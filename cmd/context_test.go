@@ -0,0 +1,49 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContext(t *testing.T) {
+	ctx, cancel := newContext(time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+
+	ctx, cancel = newContext(0)
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	cancel()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestTimeoutErr(t *testing.T) {
+	assert.NoError(t, timeoutErr(nil, time.Second))
+
+	other := errors.New("forbidden")
+	assert.Equal(t, other, timeoutErr(other, time.Second))
+
+	err := timeoutErr(errors.Wrap(context.DeadlineExceeded, "review access"), 30*time.Second)
+	assert.EqualError(t, err, "timed out after 30s, the API server may be slow or unavailable (use --timeout to wait longer)")
+}
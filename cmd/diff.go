@@ -0,0 +1,86 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffLongHelp = `
+Show the differences in access between two identities
+
+Builds the access matrix for the current user (or the one given by --as and
+--as-group) and for the identity given by --as-other and --as-other-group, and
+only shows the resources where their access differs. A '+' marks a verb which
+only the other identity is allowed, a '-' marks a verb which only the first
+identity is allowed. Other verbs show the access which both have in common.
+
+Both identities are impersonated, so this needs the impersonate verb on users
+and groups.
+`
+
+	diffExamples = `
+  Find out why alice can do something which bob cannot
+   $ rakkess diff --as alice --as-other bob
+
+  Compare a service-account with a group in the namespace 'prod'
+   $ rakkess diff --as system:serviceaccount:prod:deployer --as-other-group developers -n prod
+
+  Also show the resources where both have the same access
+   $ rakkess diff --as alice --as-other bob --show-equal
+`
+)
+
+var (
+	asOther      string
+	asOtherGroup []string
+	showEqual    bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:     "diff",
+	Short:   "Show the differences in access between two identities",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(diffLongHelp),
+	Example: constants.HelpTextMapName(diffExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.CompareIdentities(ctx, opts, asOther, asOtherGroup, showEqual)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&asOther, constants.FlagAsOther, "", "username of the identity to compare with")
+	diffCmd.Flags().StringSliceVar(&asOtherGroup, constants.FlagAsOtherGroup, nil, "groups of the identity to compare with. Can be repeated.")
+	diffCmd.Flags().BoolVar(&showEqual, constants.FlagShowEqual, false, "also show the resources where both identities have the same access")
+	diffCmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list", "create", "update", "delete"}, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidVerbs, ", ")))
+	diffCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	diffCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	diffCmd.Flags().IntVar(&opts.MaxConcurrency, constants.FlagMaxConcurrency, constants.DefaultMaxConcurrency, "send at most this many access reviews concurrently. Zero sends all reviews at once.")
+	diffCmd.Flags().IntVar(&opts.MaxRetries, constants.FlagMaxRetries, constants.DefaultMaxRetries, "retry an access review this many times with exponential backoff when the API server is overloaded or fails, e.g. with 429 or 5xx. Denials and authentication errors are never retried.")
+	opts.ConfigFlags.AddFlags(diffCmd.Flags())
+}
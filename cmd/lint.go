@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	lintLongHelp = `
+Report overly broad wildcard grants
+
+Scans all (Cluster)Roles for rules which use '*' for verbs, resources, or
+apiGroups, and reports the bindings which grant them to non-system subjects.
+Subjects with the 'system:' prefix are maintained by kubernetes and skipped.
+Findings are ranked by breadth, i.e. by the number of wildcard fields and
+whether the binding applies cluster-wide.
+
+With --report-orphan-subjects, bindings to service-accounts which do not
+exist anymore are reported as well. Such bindings are usually left over after
+a service-account was deleted.
+
+This is a static analysis of RBAC objects, so no access reviews are needed.
+`
+
+	lintExamples = `
+  Report wildcard grants via ClusterRoleBindings
+   $ rakkess lint
+
+  Also consider Roles and RoleBindings in the default namespace
+   $ rakkess lint --namespace default
+
+  Also report bindings to deleted service-accounts
+   $ rakkess lint --report-orphan-subjects
+`
+)
+
+var lintCmd = &cobra.Command{
+	Use:     "lint",
+	Short:   "Report overly broad wildcard grants",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(lintLongHelp),
+	Example: constants.HelpTextMapName(lintExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.Lint(ctx, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	lintCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	lintCmd.Flags().BoolVar(&opts.ReportOrphanSubjects, constants.FlagReportOrphanSubjects, false, "also report bindings to service-accounts which do not exist. RoleBindings are checked in the given namespace, or in all namespaces.")
+	lintCmd.Flags().StringVar(&opts.ResourceVersion, constants.FlagResourceVersion, "", "list all RBAC objects at this exact resourceVersion to get a consistent snapshot. Fails if the resourceVersion is already compacted by the API server.")
+	opts.ConfigFlags.AddFlags(lintCmd.Flags())
+}
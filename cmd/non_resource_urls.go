@@ -0,0 +1,88 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	nonResourceURLsLongHelp = `
+Show the access to non-resource URLs such as /healthz or /metrics
+
+Sends one SelfSubjectAccessReview per path and verb, so the result reflects
+all authorizers, just like the access matrix of resources. RBAC grants access
+to non-resource URLs via nonResourceURLs rules in ClusterRoles.
+
+Use this to audit whether a token can scrape metrics or reach the health
+endpoints of the API server.
+`
+
+	nonResourceURLsExamples = `
+  Review access to the default paths
+   $ rakkess non-resource-urls
+
+  Review access to /metrics for a service-account
+   $ rakkess non-resource-urls --paths /metrics --as system:serviceaccount:monitoring:prometheus
+
+  Also check the HTTP verbs put and delete
+   $ rakkess non-resource-urls --verbs get,post,put,delete
+`
+)
+
+var (
+	nonResourcePaths []string
+
+	// the verbs flag is shared with other commands, so its default is set here
+	nonResourceDefaultVerbs = []string{"get", "post"}
+)
+
+var nonResourceURLsCmd = &cobra.Command{
+	Use:     "non-resource-urls",
+	Aliases: []string{"nru"},
+	Short:   "Show the access to non-resource URLs such as /healthz or /metrics",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(nonResourceURLsLongHelp),
+	Example: constants.HelpTextMapName(nonResourceURLsExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		if !cmd.Flags().Changed(constants.FlagVerbs) {
+			opts.Verbs = nonResourceDefaultVerbs
+		}
+		return rakkess.NonResource(ctx, opts, nonResourcePaths)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nonResourceURLsCmd)
+
+	nonResourceURLsCmd.Flags().StringSliceVar(&nonResourcePaths, constants.FlagPaths, constants.DefaultNonResourceURLs, "check access to these non-resource URL paths")
+	nonResourceURLsCmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, nonResourceDefaultVerbs, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.NonResourceVerbs, ", ")))
+	nonResourceURLsCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	nonResourceURLsCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	nonResourceURLsCmd.Flags().IntVar(&opts.MinVerbs, constants.FlagMinVerbs, 0, "only show paths with at least this many allowed verbs out of --verbs")
+	nonResourceURLsCmd.Flags().IntVar(&opts.MaxConcurrency, constants.FlagMaxConcurrency, constants.DefaultMaxConcurrency, "send at most this many access reviews concurrently. Zero sends all reviews at once.")
+	nonResourceURLsCmd.Flags().IntVar(&opts.MaxRetries, constants.FlagMaxRetries, constants.DefaultMaxRetries, "retry an access review this many times with exponential backoff when the API server is overloaded or fails, e.g. with 429 or 5xx. Denials and authentication errors are never retried.")
+	opts.ConfigFlags.AddFlags(nonResourceURLsCmd.Flags())
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	planLongHelp = `
+Preview how RBAC manifests change access
+
+Reads all Roles, ClusterRoles, and their bindings from the manifests in the
+given directory, and applies them on top of the RBAC objects in the cluster.
+The result lists every grant of a verb on a resource to a subject, which is
+added or removed by the proposed manifests. Nothing is changed in the cluster.
+
+With --baseline, the manifests are compared with the manifests in the baseline
+directory instead, for example the state of the target branch of a pull
+request. The output format github-comment renders the changes as Markdown for a
+pull request comment.
+
+The command exits with a non-zero exit code if the manifests add grants with
+wildcards or escalating verbs (bind, escalate, impersonate, ...).
+`
+
+	planExamples = `
+  Preview the effect of the manifests in ./proposed
+   $ rakkess plan --from-manifests ./proposed
+
+  Put namespaced manifests without namespace into 'staging'
+   $ rakkess plan --from-manifests ./proposed --namespace staging
+
+  Comment the RBAC impact of a pull request, without cluster access
+   $ rakkess plan --from-manifests ./head --baseline ./base -o github-comment
+`
+)
+
+var fromManifests, baseline string
+
+var planCmd = &cobra.Command{
+	Use:     "plan",
+	Short:   "Preview how RBAC manifests change access",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(planLongHelp),
+	Example: constants.HelpTextMapName(planExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.Plan(ctx, opts, fromManifests, baseline)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	planCmd.Flags().StringVar(&fromManifests, constants.FlagFromManifests, "", "directory with the proposed RBAC manifests")
+	_ = planCmd.MarkFlagRequired(constants.FlagFromManifests)
+	planCmd.Flags().StringVar(&baseline, constants.FlagBaseline, "", "directory with the current RBAC manifests to compare with, instead of the RBAC objects in the cluster")
+	planCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable, constants.OutputGitHubComment}, ", ")))
+	planCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	opts.ConfigFlags.AddFlags(planCmd.Flags())
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	previewSubjectLongHelp = `
+Preview the access of a subject before binding it to roles
+
+Evaluates the rules of the given roles directly, and shows the access which a
+new subject would have if it were bound to these roles and to nothing else.
+Nothing is created in the cluster. With --namespace, the roles are bound by
+RoleBindings in that namespace. Otherwise, they are bound by
+ClusterRoleBindings.
+`
+
+	previewSubjectExamples = `
+  Preview the access to secrets when binding the ClusterRole edit in team-a
+   $ rakkess preview-subject --bind-to clusterrole/edit --namespace team-a --resource secrets
+
+  Preview the access to all resources of a Role and a ClusterRole
+   $ rakkess preview-subject --bind-to role/deployer,clusterrole/view --namespace team-a
+`
+)
+
+var (
+	bindTo          []string
+	previewResource string
+)
+
+var previewSubjectCmd = &cobra.Command{
+	Use:     "preview-subject",
+	Short:   "Preview the access of a subject before binding it to roles",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(previewSubjectLongHelp),
+	Example: constants.HelpTextMapName(previewSubjectExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.PreviewSubject(ctx, opts, bindTo, previewResource)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(previewSubjectCmd)
+
+	previewSubjectCmd.Flags().StringSliceVar(&bindTo, constants.FlagBindTo, nil, "the roles to bind the subject to, in the form clusterrole/<name> or role/<name>")
+	_ = previewSubjectCmd.MarkFlagRequired(constants.FlagBindTo)
+	previewSubjectCmd.Flags().StringVar(&previewResource, constants.FlagResource, "", "only show the given resource, optionally qualified with its API group")
+	previewSubjectCmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list", "create", "update", "delete"}, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidVerbs, ", ")))
+	previewSubjectCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	previewSubjectCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	opts.ConfigFlags.AddFlags(previewSubjectCmd.Flags())
+}
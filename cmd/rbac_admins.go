@@ -0,0 +1,77 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rbacAdminsLongHelp = `
+Show all subjects which can modify RBAC itself
+
+Determines the subjects with write access to ClusterRoles, ClusterRoleBindings,
+Roles, and RoleBindings from all (Cluster)Roles and their bindings in all
+namespaces. For roles, the verbs bind and escalate are shown as well, because
+they allow to grant permissions which the subject does not hold.
+
+Every row shows the namespace in which the access is granted, '*' stands for
+cluster-wide access via ClusterRoleBindings. Subjects other than system subjects
+and service-accounts in kube-system are highlighted and reported in a warning,
+because they control the authorization of the whole cluster.
+`
+
+	rbacAdminsExamples = `
+  Review who can modify RBAC anywhere in the cluster
+   $ rakkess rbac-admins
+
+  Only consider grants in the namespace 'prod' and cluster-wide grants
+   $ rakkess rbac-admins --namespace prod
+
+  Hide the subjects which are admins by design
+   $ rakkess rbac-admins --ignore-masters
+`
+)
+
+var rbacAdminsCmd = &cobra.Command{
+	Use:     "rbac-admins",
+	Short:   "Show all subjects which can modify RBAC itself",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(rbacAdminsLongHelp),
+	Example: constants.HelpTextMapName(rbacAdminsExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.RBACAdmins(ctx, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rbacAdminsCmd)
+
+	rbacAdminsCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	rbacAdminsCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	rbacAdminsCmd.Flags().BoolVar(&opts.IgnoreMasters, constants.FlagIgnoreMasters, false, "exclude grants via the group system:masters and the ClusterRole cluster-admin")
+	rbacAdminsCmd.Flags().StringVar(&opts.ResourceVersion, constants.FlagResourceVersion, "", "list all RBAC objects at this exact resourceVersion to get a consistent snapshot. Fails if the resourceVersion is already compacted by the API server.")
+	opts.ConfigFlags.AddFlags(rbacAdminsCmd.Flags())
+}
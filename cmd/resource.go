@@ -17,7 +17,8 @@ limitations under the License.
 package cmd
 
 import (
-	"context"
+	"fmt"
+	"strings"
 
 	rakkess "github.com/corneliusweig/rakkess/internal"
 	"github.com/corneliusweig/rakkess/internal/constants"
@@ -56,34 +57,148 @@ More on https://github.com/corneliusweig/rakkess/blob/v0.5.0/doc/USAGE.md#usage
 
   Review access to a config-map with a specific name
    $ rakkess for cm config-map-name --verbs=all
+
+  Review access to secrets and config-maps, one table per resource
+   $ rakkess for secrets,configmaps --separate-tables
+
+  Review access to deployments, secrets, and config-maps in a merged matrix
+   $ rakkess for deployments secrets configmaps
+
+  Review what is granted to the group developers on secrets
+   $ rakkess resource secrets --subject=group:developers
+
+  Keep watching who can delete deployments in the namespace 'prod'
+   $ rakkess resource deployments.apps --verbs delete -n prod --watch
+
+  Review who can access the metrics endpoint
+   $ rakkess resource --non-resource-urls /metrics
+
+  Review who can create SubjectAccessReviews or impersonate other subjects
+   $ rakkess resource --reviewer
+
+  Review what the default service-account of every namespace can access
+   $ rakkess resource --preset default-sa
 `
 )
 
+var (
+	reviewer        bool
+	nonResourceURLs []string
+	preset          string
+)
+
 // resourceCmd represents the resource command
 var resourceCmd = &cobra.Command{
-	Use:     "for <resource> [name]",
+	Use:     "for <resource>... [name]",
 	Aliases: []string{"resource", "r"},
 	Short:   "Show all subjects with access to a given resource",
-	Args:    cobra.RangeArgs(1, 2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if reviewer || len(nonResourceURLs) > 0 || preset != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	Long:    constants.HelpTextMapName(resourceLongHelp),
 	Example: constants.HelpTextMapName(resourceExamples),
 	Run: func(cmd *cobra.Command, args []string) {
-		ctx, cancel := context.WithCancel(context.Background())
-		catchCtrlC(cancel)
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		if reviewer {
+			if err := rakkess.Reviewers(ctx, opts); err != nil {
+				klog.Error(timeoutErr(err, opts.Timeout))
+			}
+			return
+		}
+
+		if preset != "" {
+			if preset != constants.PresetDefaultSA {
+				klog.Errorf("unknown preset %q, valid presets are (%s)", preset, constants.PresetDefaultSA)
+				return
+			}
+			if err := rakkess.DefaultServiceAccounts(ctx, opts); err != nil {
+				klog.Error(timeoutErr(err, opts.Timeout))
+			}
+			return
+		}
+
+		if len(nonResourceURLs) > 0 {
+			if !cmd.Flags().Changed(constants.FlagVerbs) {
+				opts.Verbs = constants.NonResourceVerbs
+			}
+			if err := rakkess.NonResourceSubject(ctx, opts, nonResourceURLs); err != nil {
+				klog.Error(timeoutErr(err, opts.Timeout))
+			}
+			return
+		}
+
+		resources, resourceName := resourceArgs(args, func(arg string) bool { return rakkess.IsResource(opts, arg) })
+		if len(resources) > 1 {
+			if resourceName != "" {
+				klog.Errorf("a resource name cannot be combined with several resources")
+				return
+			}
+			if err := rakkess.Subjects(ctx, opts, resources); err != nil {
+				klog.Error(timeoutErr(err, opts.Timeout))
+			}
+			return
+		}
 
-		resource := args[0]
-		var resourceName string
-		if len(args) == 2 {
-			resourceName = args[1]
+		resource := resources[0]
+		if opts.Watch {
+			if err := rakkess.WatchSubject(ctx, opts, resource, resourceName); err != nil {
+				klog.Error(timeoutErr(err, opts.Timeout))
+			}
+			return
 		}
 		if err := rakkess.Subject(ctx, opts, resource, resourceName); err != nil {
-			klog.Error(err)
+			klog.Error(timeoutErr(err, opts.Timeout))
 		}
 	},
 }
 
+// resourceArgs splits the arguments into resources and an optional resource
+// name. Like for kubectl get, a second argument is the name of the resource,
+// unless isResource tells that it is a resource type itself. With more than
+// two arguments, every argument is a resource. Any argument may list several
+// comma-separated resources.
+func resourceArgs(args []string, isResource func(string) bool) (resources []string, name string) {
+	if len(args) == 2 && !strings.Contains(args[1], ",") && !isResource(args[1]) {
+		name = args[1]
+		args = args[:1]
+	}
+	for _, arg := range args {
+		resources = append(resources, strings.Split(arg, ",")...)
+	}
+	return resources, name
+}
+
 func init() {
 	rootCmd.AddCommand(resourceCmd)
 
 	AddRakkessFlags(resourceCmd)
+	resourceCmd.Flags().StringSliceVar(&opts.Subject, constants.FlagSubject, nil, "only show the given subjects, in the form [user:|group:|sa:]<name>. Service-accounts may be given as sa:<namespace>:<name>. Can be repeated.")
+	resourceCmd.Flags().StringSliceVar(&opts.SubjectKinds, constants.FlagSubjectKind, nil, "only show subjects of these kinds, out of (User, Group, ServiceAccount). Can be repeated.")
+	resourceCmd.Flags().BoolVar(&opts.Intersect, constants.FlagIntersect, false, "show only the verbs which every subject given by --subject is granted")
+	resourceCmd.Flags().BoolVar(&opts.SeparateTables, constants.FlagSeparateTables, false, "print one table per resource when several resources are given, instead of a merged matrix")
+	resourceCmd.Flags().BoolVar(&opts.Union, constants.FlagUnion, false, "show the verbs of every subject given by --subject in its own row (default)")
+	resourceCmd.Flags().StringVar(&opts.GroupMembersFile, constants.FlagGroupMembers, "", "YAML file which maps group names to member names. Members are shown with the access of their groups, and -o wide shows which groups contributed which verbs.")
+	resourceCmd.Flags().BoolVar(&opts.EffectiveIdentity, constants.FlagEffectiveIdentity, false, "collapse the groups from --group-members into one effective row per member, with the union of its grants and the SOURCES they come from")
+	resourceCmd.Flags().DurationVar(&opts.ChangedSince, constants.FlagChangedSince, 0, "only consider (Cluster)RoleBindings which were created or updated within this duration, e.g. 2h")
+	resourceCmd.Flags().StringVar(&opts.BindingLabelSelector, constants.FlagBindingLabelSelector, "", "only consider (Cluster)RoleBindings with labels matching this selector, e.g. team=platform")
+	resourceCmd.Flags().StringVar(&opts.SubjectLabelSelector, constants.FlagSubjectLabels, "", "only show the service-accounts with labels matching this selector, e.g. team=platform. Users and groups have no labels and are not shown.")
+	resourceCmd.Flags().BoolVarP(&opts.AllNamespaces, constants.FlagAllNamespaces, "A", false, "consider the RoleBindings of all namespaces. The SCOPE column shows the namespaces in which each subject has access.")
+	resourceCmd.Flags().BoolVar(&opts.ShowRoles, constants.FlagShowRoles, false, "add the ROLES column, which lists the (Cluster)Roles granting each subject its verbs")
+	resourceCmd.Flags().StringSliceVar(&opts.VerbsAllOf, constants.FlagVerbsAllOf, nil, "only show the subjects which are granted every one of these verbs, e.g. get,delete. The verbs must be part of --verbs.")
+	resourceCmd.Flags().StringSliceVar(&opts.VerbsAnyOf, constants.FlagVerbsAnyOf, nil, "only show the subjects which are granted at least one of these verbs. The verbs must be part of --verbs.")
+	resourceCmd.Flags().StringVar(&opts.Exceeds, constants.FlagExceeds, "", "only show the subjects which are granted verbs beyond a baseline role, given as clusterrole/<name> or role/<name>. The EXCEEDS column lists the extra verbs.")
+	resourceCmd.Flags().StringVar(&opts.SubjectPrefix, constants.FlagSubjectPrefix, constants.SubjectPrefixColumn, fmt.Sprintf("how to show the kind of subjects, out of (%s). The abbreviations U:, G:, and SA:, or the emoji replace the KIND column to save width.", strings.Join(constants.SubjectPrefixes, ", ")))
+	resourceCmd.Flags().StringSliceVar(&opts.SubjectNormalizer, constants.FlagSubjectNormalizer, []string{constants.NormalizeNone}, fmt.Sprintf("merge subjects which are equal after normalizing user and group names, out of (%s). Several normalizers are applied in order, e.g. lowercase,strip-domain.", strings.Join(constants.SubjectNormalizers, ", ")))
+	resourceCmd.Flags().StringVar(&opts.SortBy, constants.FlagSortBy, "", fmt.Sprintf("order the subjects of the matrix, out of (%s). access puts the subjects with the most denied verbs first. (default %s)", strings.Join(constants.SubjectSortOrders, ", "), constants.SortByName))
+	resourceCmd.Flags().BoolVar(&opts.IgnoreMasters, constants.FlagIgnoreMasters, false, "exclude grants via the group system:masters and the ClusterRole cluster-admin")
+	resourceCmd.Flags().StringVar(&opts.ResourceVersion, constants.FlagResourceVersion, "", "list all RBAC objects at this exact resourceVersion to get a consistent snapshot. Fails if the resourceVersion is already compacted by the API server.")
+	resourceCmd.Flags().BoolVarP(&opts.Watch, constants.FlagWatch, "w", false, "keep watching the RBAC objects and print the matrix again when it changes. Only the bindings affected by a change are evaluated again.")
+	resourceCmd.Flags().StringSliceVar(&nonResourceURLs, constants.FlagNonResourceURLs, nil, "show subjects with access to these non-resource URLs instead of a resource, e.g. /metrics. A trailing * in roles matches all URLs with that prefix. Verbs default to the HTTP verbs get, head, post, put, patch, and delete.")
+	resourceCmd.Flags().BoolVar(&reviewer, constants.FlagReviewer, false, "show subjects which can create (local) SubjectAccessReviews or impersonate users, groups, or service-accounts")
+	resourceCmd.Flags().StringVar(&preset, constants.FlagPreset, "", fmt.Sprintf("run a predefined security check instead of showing a single resource, out of (%s). default-sa shows the access of the default service-account of every namespace to sensitive resources.", constants.PresetDefaultSA))
 }
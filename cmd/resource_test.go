@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantResources []string
+		wantName      string
+	}{
+		{
+			name:          "single resource",
+			args:          []string{"deployments"},
+			wantResources: []string{"deployments"},
+		},
+		{
+			name:          "resource with name",
+			args:          []string{"cm", "config-map-name"},
+			wantResources: []string{"cm"},
+			wantName:      "config-map-name",
+		},
+		{
+			name:          "two resources",
+			args:          []string{"deployments", "secrets"},
+			wantResources: []string{"deployments", "secrets"},
+		},
+		{
+			name:          "resource with comma-separated resources",
+			args:          []string{"deployments", "secrets,configmaps"},
+			wantResources: []string{"deployments", "secrets", "configmaps"},
+		},
+		{
+			name:          "comma-separated resources",
+			args:          []string{"secrets,configmaps"},
+			wantResources: []string{"secrets", "configmaps"},
+		},
+		{
+			name:          "several arguments",
+			args:          []string{"deployments", "secrets", "configmaps"},
+			wantResources: []string{"deployments", "secrets", "configmaps"},
+		},
+		{
+			name:          "several arguments with commas",
+			args:          []string{"deployments,pods", "secrets", "configmaps"},
+			wantResources: []string{"deployments", "pods", "secrets", "configmaps"},
+		},
+	}
+
+	isResource := func(arg string) bool {
+		return arg == "secrets" || arg == "configmaps"
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resources, name := resourceArgs(test.args, isResource)
+			assert.Equal(t, test.wantResources, resources)
+			assert.Equal(t, test.wantName, name)
+		})
+	}
+}
@@ -17,15 +17,18 @@ limitations under the License.
 package cmd
 
 import (
-	"context"
 	"flag"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	rakkess "github.com/corneliusweig/rakkess/internal"
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/diff"
 	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/corneliusweig/rakkess/internal/protobuf"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 )
@@ -33,6 +36,8 @@ import (
 var (
 	opts     = options.NewRakkessOptions()
 	diffWith []string
+	// scanStart is the start time for --stats and --report-summary
+	scanStart time.Time
 )
 
 const (
@@ -69,33 +74,196 @@ More on https://github.com/corneliusweig/rakkess/blob/v0.5.0/doc/USAGE.md#usage
   Review access as a service-account
    $ rakkess --sa kube-system:namespace-controller
 
+  Review the access of the node identity of 'worker-1'
+   $ rakkess --as-node worker-1 --namespace default
+
+  Review access to namespaced resources in all namespaces
+   $ rakkess --all-namespaces
+
   Review access for different verbs
    $ rakkess --verbs get,watch,patch
 
+  Fail unless secrets can be listed, and write a JUnit report
+   $ rakkess --verbs list --require-allowed list:secrets -o junit --output-file results.xml
+
+  Review access with a mounted service-account token
+   $ rakkess --token-file /var/run/secrets/tokens/auditee
+
   Review access rights diff with another service account
    $ rakkess --diff-with sa=kube-system:namespace-controller
+
+  Review access to the secret 'my-secret' in 'default'
+   $ rakkess --name my-secret secrets --namespace default
+
+  Review where access differs between the contexts staging and prod
+   $ rakkess --contexts staging,prod
 `
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:     constants.CommandName,
+	Use:     constants.CommandName + " [--name NAME RESOURCE...]",
 	Short:   "Review access - show an access matrix for all resources",
 	Long:    constants.HelpTextMapName(rakkessLongDescription),
 	Example: constants.HelpTextMapName(rakkessExamples),
-	Args:    cobra.NoArgs,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// resources can only be given for named objects, so that misspelled
+		// subcommands are still reported
+		if opts.ResourceName == "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx, cancel := context.WithCancel(context.Background())
-		catchCtrlC(cancel)
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+		if len(args) > 0 {
+			// resources given as arguments take precedence over the spec
+			opts.Resources = args
+		}
+		if opts.GroupByAPI {
+			if opts.SortBy != "" && opts.SortBy != constants.SortByGroup {
+				return fmt.Errorf("--%s cannot be combined with --%s %s", constants.FlagGroupByAPI, constants.FlagSortBy, opts.SortBy)
+			}
+			opts.SortBy = constants.SortByGroup
+		}
+		if opts.ResourceName != "" && len(opts.Resources) == 0 {
+			return fmt.Errorf("--%s requires the resources to check, e.g. %s --%s my-secret secrets", constants.FlagName, constants.CommandName, constants.FlagName)
+		}
+
+		if opts.ReportSummary && (len(opts.Contexts) > 0 || opts.AllNamespaces || opts.RBACOnly) {
+			return fmt.Errorf("--%s cannot be combined with --%s, --%s, or --%s", constants.FlagReportSummary, constants.FlagContexts, constants.FlagAllNamespaces, constants.FlagRBACOnly)
+		}
+
+		if len(opts.Contexts) > 0 {
+			if diffWith != nil || opts.AllNamespaces || opts.RBACOnly || opts.MinVerbs > 0 || opts.Only != "" {
+				return fmt.Errorf("--%s cannot be combined with --%s, --%s, --%s, --%s, or --%s", constants.FlagContexts, constants.FlagDiffWith, constants.FlagAllNamespaces, constants.FlagRBACOnly, constants.FlagMinVerbs, constants.FlagOnly)
+			}
+			if len(opts.RequireAllowed) > 0 || len(opts.FailIfAllowed) > 0 || opts.ExitCode || opts.ExplainDeny {
+				return fmt.Errorf("--%s cannot be combined with --%s, --%s, --%s, or --%s", constants.FlagContexts, constants.FlagRequireAllowed, constants.FlagFailIfAllowed, constants.FlagExitCode, constants.FlagExplainDeny)
+			}
+			return rakkess.Contexts(ctx, opts)
+		}
+
+		if opts.MyNamespaces && !opts.AllNamespaces {
+			return fmt.Errorf("--%s requires --%s", constants.FlagMyNamespaces, constants.FlagAllNamespaces)
+		}
+		if opts.AllNamespaces {
+			if diffWith != nil || len(opts.RequireAllowed) > 0 || len(opts.FailIfAllowed) > 0 {
+				return fmt.Errorf("--%s cannot be combined with --%s, --%s, or --%s", constants.FlagAllNamespaces, constants.FlagDiffWith, constants.FlagRequireAllowed, constants.FlagFailIfAllowed)
+			}
+			if opts.Summary {
+				return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagSummary, constants.FlagAllNamespaces)
+			}
+			res, err := rakkess.ResourceAllNamespaces(ctx, opts)
+			if err != nil {
+				return err
+			}
+			restricted := res.Restricted(opts.Verbs)
+			res.RetainMinVerbs(opts.Verbs, opts.MinVerbs)
+			if a, ok := rakkess.OnlyAccess(opts); ok {
+				res.RetainOnly(opts.Verbs, a)
+			}
+			if opts.HideNotApplicable {
+				res.RetainApplicable(opts.Verbs)
+				opts.Verbs = res.ApplicableVerbs(opts.Verbs)
+			}
+			if err := rakkess.Render(opts, res.Table(opts.Verbs, opts.NamespaceColumnPosition)); err != nil {
+				return err
+			}
+			return rakkess.CheckRestricted(opts, restricted)
+		}
+
+		if opts.RBACOnly {
+			if diffWith != nil || opts.AllNamespaces || opts.ExitCode {
+				return fmt.Errorf("--%s cannot be combined with --%s, --%s, or --%s", constants.FlagRBACOnly, constants.FlagDiffWith, constants.FlagAllNamespaces, constants.FlagExitCode)
+			}
+			res, err := rakkess.CompareAuthorizers(ctx, opts)
+			if err != nil {
+				return err
+			}
+			return rakkess.Render(opts, res.Table(opts.Verbs, opts.OutputFormat == constants.OutputWide))
+		}
 
 		res, err := rakkess.Resource(ctx, opts)
 		if err != nil {
 			return err
 		}
+		if opts.ReportSummary {
+			// count before any rows are filtered, and print after the output
+			counts := res.Counts(opts.Verbs)
+			defer func() { rakkess.PrintReportSummary(opts, counts, time.Since(scanStart)) }()
+		}
 		if diffWith == nil {
-			t := res.Table(opts.Verbs)
-			t.Render(opts.Streams.Out, opts.OutputFormat)
-			return nil
+			assertErr := rakkess.Assert(opts, res)
+			restricted := res.Restricted(opts.Verbs)
+			res.RetainMinVerbs(opts.Verbs, opts.MinVerbs)
+			if a, ok := rakkess.OnlyAccess(opts); ok {
+				res.RetainOnly(opts.Verbs, a)
+			}
+			if opts.HideNotApplicable {
+				res.RetainApplicable(opts.Verbs)
+				opts.Verbs = res.ApplicableVerbs(opts.Verbs)
+			}
+			switch opts.OutputFormat {
+			case constants.OutputJSON, constants.OutputYAML:
+				rows, rowsErr := rakkess.ResourceRows(opts, res)
+				if rowsErr != nil {
+					return rowsErr
+				}
+				err = rakkess.RenderStructured(opts, rows)
+			case constants.OutputProtobuf:
+				rows, rowsErr := rakkess.ResourceRows(opts, res)
+				if rowsErr != nil {
+					return rowsErr
+				}
+				err = rakkess.RenderProtobuf(opts, func(w io.Writer) error { return protobuf.WriteResourceRows(w, rows, opts.Verbs) })
+			case constants.OutputTree:
+				err = rakkess.RenderTree(opts, res.Tree(opts.Verbs))
+			case constants.OutputCSV:
+				err = rakkess.RenderResourceCSV(opts, res)
+			case constants.OutputDigest:
+				err = rakkess.RenderDigest(opts, res)
+			case constants.OutputList:
+				err = rakkess.RenderLines(opts, res.AllowedLines(opts.Verbs))
+			case constants.OutputJUnit:
+				// the report goes to the output file, the matrix stays on stdout
+				table, tableErr := rakkess.ResourceTable(opts, res)
+				if tableErr != nil {
+					return tableErr
+				}
+				err = rakkess.RenderJUnitMatrix(opts, table)
+			default:
+				table, tableErr := rakkess.ResourceTable(opts, res)
+				if tableErr != nil {
+					return tableErr
+				}
+				if opts.ResourceName != "" {
+					table.Title = fmt.Sprintf("Access to the objects named %q", opts.ResourceName)
+				}
+				tables := []*printer.Table{table}
+				if opts.ExplainDeny {
+					explanations, err := rakkess.ExplainDenied(ctx, opts, res)
+					if err != nil {
+						return err
+					}
+					tables = append(tables, explanations.Table())
+				}
+				err = rakkess.Render(opts, tables...)
+			}
+			if err != nil {
+				return err
+			}
+			if assertErr != nil {
+				return assertErr
+			}
+			return rakkess.CheckRestricted(opts, restricted)
+		}
+		if opts.MinVerbs > 0 || opts.Only != "" {
+			return fmt.Errorf("--%s and --%s cannot be combined with --%s", constants.FlagMinVerbs, constants.FlagOnly, constants.FlagDiffWith)
+		}
+		if len(opts.RequireAllowed) > 0 || len(opts.FailIfAllowed) > 0 || opts.ExitCode {
+			return fmt.Errorf("--%s, --%s, and --%s cannot be combined with --%s", constants.FlagRequireAllowed, constants.FlagFailIfAllowed, constants.FlagExitCode, constants.FlagDiffWith)
 		}
 
 		orig := res
@@ -125,13 +293,21 @@ var rootCmd = &cobra.Command{
 			return fmt.Errorf("with modified flags: %v", err)
 		}
 
-		t := diff.Diff(orig, mod, opts.Verbs)
-		t.Render(opts.Streams.Out, opts.OutputFormat)
-		return nil
+		return rakkess.Render(opts, diff.Diff(orig, mod, opts.Verbs))
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
-		if n := opts.ConfigFlags.Namespace; n == nil || *n == "" {
-			fmt.Fprintf(opts.Streams.Out, "No namespace given, this implies cluster scope (try -n if this is not intended)\n")
+		out := opts.Streams.Out
+		if opts.OutputFormat == constants.OutputJSON || opts.OutputFormat == constants.OutputYAML || opts.OutputFormat == constants.OutputDigest || opts.OutputFormat == constants.OutputProtobuf || opts.OutputFormat == constants.OutputCSV || opts.OutputFormat == constants.OutputMarkdown || opts.OutputFormat == constants.OutputHTML || opts.OutputFormat == constants.OutputList {
+			out = opts.Streams.ErrOut // keep the output parseable
+		}
+		if n := opts.ConfigFlags.Namespace; !opts.AllNamespaces && (n == nil || *n == "") {
+			fmt.Fprintf(out, "No namespace given, this implies cluster scope (try -n if this is not intended)\n")
+		}
+		if opts.ResourceName != "" {
+			fmt.Fprintf(out, "Access was only reviewed for the objects named %q, not for all objects of the resources.\n", opts.ResourceName)
+		}
+		if opts.AsNode != "" {
+			fmt.Fprintf(out, "The Node authorizer only grants access to objects related to the node, such as the secrets of its pods. This is not reflected for whole resources.\n")
 		}
 	},
 }
@@ -140,7 +316,7 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
 	rootCmd.SetOutput(opts.Streams.Out)
-	return rootCmd.Execute()
+	return timeoutErr(rootCmd.Execute(), opts.Timeout)
 }
 
 func init() {
@@ -149,12 +325,79 @@ func init() {
 
 	AddRakkessFlags(rootCmd)
 	rootCmd.Flags().StringVar(&opts.AsServiceAccount, constants.FlagServiceAccount, "", "similar to --as, but impersonate as service-account. The argument must be qualified <namespace>:<sa-name> or be combined with the --namespace option. Takes precedence over --as.")
+	rootCmd.Flags().StringVar(&opts.ImpersonateUID, constants.FlagAsUID, "", "UID to impersonate for the operation, together with --as or --sa")
+	rootCmd.Flags().StringVar(&opts.ResourceName, constants.FlagName, "", "review the access to the objects with this name, which matters for RBAC rules with resourceNames. The resources must be given as arguments, e.g. secrets or deployments.apps.")
+	rootCmd.Flags().StringVar(&opts.AsNode, constants.FlagAsNode, "", "impersonate the node identity of the given node (system:node:<name> in group system:nodes), and only check the resources which nodes read or write")
+	rootCmd.Flags().StringSliceVar(&opts.APIGroups, constants.FlagAPIGroup, nil, "only check the resources of these API groups, e.g. apps,networking.k8s.io. The core group is given as the empty string or as core. Can be repeated.")
+	rootCmd.Flags().StringSliceVar(&opts.Subresources, constants.FlagSubresource, nil, "only check these subresources, e.g. log,exec for pods/log and pods/exec. All other subresources are left out, main resources are always checked. Can be repeated.")
+	rootCmd.Flags().StringSliceVar(&opts.ResourcePatterns, constants.FlagResource, nil, "only check the resources matching these glob patterns, e.g. 'network*' or '*.networking.k8s.io'. Patterns match the resource name and the name qualified with the API group. Can be repeated.")
+	rootCmd.Flags().StringVar(&opts.ResourceAnnotationSelector, constants.FlagResourceAnnotationSelector, "", "only check custom resources whose CustomResourceDefinition has annotations matching this selector, e.g. sensitivity=high. Built-in resources are skipped.")
+	rootCmd.Flags().DurationVar(&opts.ChangedSince, constants.FlagChangedSince, 0, "only check custom resources whose CustomResourceDefinition was created or updated within this duration, e.g. 2h. Built-in resources are skipped.")
+	rootCmd.Flags().StringSliceVar(&opts.RequireAllowed, constants.FlagRequireAllowed, nil, "fail unless access to <verb>:<resource> is allowed, e.g. list:pods or delete:deployments.apps. Can be repeated.")
+	rootCmd.Flags().StringSliceVar(&opts.FailIfAllowed, constants.FlagFailIfAllowed, nil, "fail if access to <verb>:<resource> is allowed, e.g. get:secrets. Can be repeated.")
+	rootCmd.Flags().BoolVar(&opts.ExitCode, constants.FlagExitCode, false, "exit with exit code 1 if any of the checked verbs is denied on any resource, or if an access review fails. The matrix is printed nonetheless.")
+	rootCmd.Flags().BoolVar(&opts.NoSAR, constants.FlagNoSAR, false, "derive the access from a single SelfSubjectRulesReview instead of one SelfSubjectAccessReview per resource and verb. Only reflects RBAC and similar rule-based authorizers.")
+	rootCmd.Flags().StringVar(&opts.RiskTagsFile, constants.FlagRiskTags, "", "YAML file which maps resources to risk levels, e.g. secrets: critical. The level is added to table and json output, and critical rows are highlighted.")
+	rootCmd.Flags().BoolVar(&opts.AssumeVerbsSupported, constants.FlagAssumeVerbsSupported, false, "check every verb on every resource, even if discovery does not list the verb for the resource. This needs more access reviews.")
+	rootCmd.Flags().IntVar(&opts.MaxConcurrency, constants.FlagParallelism, constants.DefaultMaxConcurrency, "")
+	_ = rootCmd.Flags().MarkDeprecated(constants.FlagParallelism, fmt.Sprintf("use --%s instead", constants.FlagMaxConcurrency))
+	rootCmd.Flags().IntVar(&opts.MaxConcurrency, constants.FlagMaxConcurrency, constants.DefaultMaxConcurrency, "send at most this many access reviews concurrently. Zero sends all reviews at once.")
+	rootCmd.Flags().IntVar(&opts.MaxRetries, constants.FlagMaxRetries, constants.DefaultMaxRetries, "retry an access review this many times with exponential backoff when the API server is overloaded or fails, e.g. with 429 or 5xx. Denials and authentication errors are never retried.")
+	rootCmd.Flags().BoolVar(&opts.NoProgress, constants.FlagNoProgress, false, "do not show the number of checked resources on stderr. The progress is only shown if stdout and stderr are terminals, and not for -o json.")
+	rootCmd.Flags().BoolVar(&opts.AutoParallelism, constants.FlagAutoParallelism, false, fmt.Sprintf("calibrate the parallelism with a few access reviews at increasing concurrency, and use the highest value below which the API server does not throttle, at most %d", constants.MaxAutoParallelism))
+	rootCmd.Flags().BoolVar(&opts.RBACOnly, constants.FlagRBACOnly, false, "show the access granted by RBAC rules alone, and flag where other authorizers such as webhooks change the outcome of access reviews. Use -o wide to show both results.")
+	rootCmd.Flags().BoolVar(&opts.ExplainDeny, constants.FlagExplainDeny, false, "explain every denied verb in a second table, with the reason of the access review and whether RBAC rules grant the verb")
+	rootCmd.Flags().StringVar(&opts.SortBy, constants.FlagSortBy, "", fmt.Sprintf("order the rows of the matrix, out of (%s). Ordering by name or access lists the resources with their API group in a single section, access puts the resources with the most denied verbs first. (default %s)", strings.Join(constants.ResourceSortOrders, ", "), constants.SortByGroup))
+	rootCmd.Flags().BoolVar(&opts.GroupByAPI, constants.FlagGroupByAPI, false, fmt.Sprintf("group the rows of the matrix by API group, with a bold heading per group on a terminal. Same as --%s %s.", constants.FlagSortBy, constants.SortByGroup))
+	rootCmd.Flags().BoolVarP(&opts.AllNamespaces, constants.FlagAllNamespaces, "A", false, "show access to namespaced resources in every namespace, with one row per namespace and resource")
+	rootCmd.Flags().BoolVar(&opts.MyNamespaces, constants.FlagMyNamespaces, false, "with --all-namespaces, only show the namespaces which the caller may get, instead of every namespace")
+	rootCmd.Flags().StringVar(&opts.NamespaceColumnPosition, constants.FlagNamespaceColumnPosition, constants.NamespaceColumnFirst, fmt.Sprintf("position of the namespace column for --%s, out of (%s, %s)", constants.FlagAllNamespaces, constants.NamespaceColumnFirst, constants.NamespaceColumnLast))
+	rootCmd.Flags().BoolVar(&opts.HideNotApplicable, constants.FlagHideNotApplicable, false, "hide resources which support none of the checked verbs according to API discovery, and the verb columns which no resource supports. Denied verbs are never hidden.")
+	rootCmd.Flags().BoolVar(&opts.Summary, constants.FlagSummary, false, "add an ALLOWED column which counts the allowed verbs of every resource, and a TOTAL row which counts the resources allowing each verb, e.g. 42/130 out of the applicable ones. Not shown in structured output formats.")
+	rootCmd.Flags().BoolVar(&opts.ReportSummary, constants.FlagReportSummary, false, "print the number of checked resources and verbs, the allowed, denied, and failed access reviews, and the elapsed time as a single JSON line to stderr after the output")
+	rootCmd.Flags().StringSliceVar(&opts.Contexts, constants.FlagContexts, nil, "review the access in each of these kubeconfig contexts, e.g. staging,prod. Prints one matrix per context and the verbs whose access differs between them. Unreachable contexts are reported and skipped. Can be repeated.")
+	rootCmd.Flags().BoolVar(&opts.PreferredOnly, constants.FlagPreferredOnly, true, "only check the preferred version of each resource. If false, resources served in several versions are listed once per version.")
+	rootCmd.Flags().BoolVar(&opts.AllowedOnly, constants.FlagAllowedOnly, false, "only keep the allowed verbs in the json, yaml, or protobuf output, and leave out the resources without any allowed verb")
 
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	rootCmd.PersistentFlags().BoolVar(&opts.Stats, constants.FlagStats, false, "print the scan duration and the number of API calls to stderr")
+	rootCmd.PersistentFlags().StringVar(&opts.SpecFile, constants.FlagSpec, "", "read the audit query (verbs, namespace, subject, output, ...) from this YAML file. Command-line flags take precedence over the spec.")
+	rootCmd.PersistentFlags().StringVar(&opts.SpecFile, constants.FlagConfig, "", fmt.Sprintf("same as --%s", constants.FlagSpec))
+	rootCmd.PersistentFlags().DurationVar(&opts.Timeout, constants.FlagTimeout, 0, "abort the command when it takes longer than this duration, e.g. 2m. Zero means no timeout.")
+	rootCmd.PersistentFlags().BoolVar(&opts.ForceColors, constants.FlagForceColors, false, "print ANSI colors even if the output is not a terminal, e.g. with --output-file or when piping the output")
+	rootCmd.PersistentFlags().BoolVar(&opts.NoPager, constants.FlagNoPager, false, "do not show output which is taller than the terminal through the pager from $PAGER (default less -R)")
+	rootCmd.PersistentFlags().BoolVar(&opts.Pager, constants.FlagPager, false, "always show the output through the pager, also if it fits on the screen or stdout is not a terminal")
+	rootCmd.PersistentFlags().BoolVar(&opts.NoCache, constants.FlagNoCache, false, "ignore the cached API discovery under --cache-dir and fetch it afresh from the API server")
+	rootCmd.PersistentFlags().StringVar(&opts.TokenFile, constants.FlagTokenFile, "", "authenticate with the bearer token in this file instead of the kubeconfig credentials, e.g. a projected service-account token. The file is re-read when the token rotates.")
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		scanStart = time.Now()
+		if cmd.Flags().Changed(constants.FlagSpec) && cmd.Flags().Changed(constants.FlagConfig) {
+			return fmt.Errorf("--%s and --%s cannot be combined", constants.FlagSpec, constants.FlagConfig)
+		}
+		if err := opts.ExpandSpec(cmd.Flags()); err != nil {
+			return err
+		}
 		opts.ExpandVerbs()
+		opts.ExpandTokenFile()
+		printer.ForceColors = opts.ForceColors
+		return nil
+	}
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		if opts.Stats {
+			rakkess.PrintStats(opts, time.Since(scanStart))
+		}
 	}
 	rootCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
-		return opts.ExpandServiceAccount()
+		if opts.AutoParallelism && (cmd.Flags().Changed(constants.FlagMaxConcurrency) || cmd.Flags().Changed(constants.FlagParallelism)) {
+			return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagMaxConcurrency, constants.FlagAutoParallelism)
+		}
+		if err := opts.ExpandNode(); err != nil {
+			return err
+		}
+		if err := opts.ExpandServiceAccount(); err != nil {
+			return err
+		}
+		return opts.ValidateImpersonateUID()
 	}
 }
 
@@ -163,6 +406,13 @@ func AddRakkessFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list", "create", "update", "delete"}, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidVerbs, ", ")))
 	cmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join(constants.ValidOutputFormats, ", ")))
 	cmd.Flags().StringSliceVar(&diffWith, constants.FlagDiffWith, nil, "Show diff for modified call. For example --diff-with=namespace=kube-system.")
+	cmd.Flags().BoolVar(&opts.AllowCustomVerbs, constants.FlagAllowCustomVerbs, false, "accept verbs in --verbs which are not listed above, such as custom verbs of CRDs, and review them on every resource. The access review denies verbs which the cluster does not know.")
+	cmd.Flags().IntVar(&opts.MinVerbs, constants.FlagMinVerbs, 0, "only show rows with at least this many allowed verbs out of --verbs")
+	cmd.Flags().StringVar(&opts.Only, constants.FlagOnly, "", fmt.Sprintf("only show rows where at least one verb out of --verbs is %s or %s. The rows are shown with all their verbs.", constants.OnlyAllowed, constants.OnlyDenied))
+	cmd.Flags().BoolVar(&opts.Describe, constants.FlagDescribe, false, "describe the allowed verbs in words, such as read-only or full control, followed by the verbs")
+	cmd.Flags().StringVar(&opts.DescribeFile, constants.FlagDescribeFile, "", "YAML file with a list of descriptions and their verbs, which replace the built-in descriptions of --describe")
+	cmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout (required for sqlite output)")
+	cmd.Flags().StringVar(&opts.Compress, constants.FlagCompress, "", "compress the output, only gzip is supported. Implied when --output-file ends in .gz")
 
 	opts.ConfigFlags.AddFlags(cmd.Flags())
 }
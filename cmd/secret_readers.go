@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	secretReadersLongHelp = `
+Show all subjects which can read secrets in any namespace
+
+Determines the subjects with the verbs get or list on secrets from all
+(Cluster)Roles and their bindings in all namespaces. Every row shows the
+namespace in which the access is granted, '*' stands for cluster-wide access
+via ClusterRoleBindings. With --name, only roles which apply to the secret
+with that name are considered, including roles restricted by resourceNames.
+
+Readers other than system subjects and service-accounts in kube-system are
+reported in a warning, because secrets are the most valuable target.
+`
+
+	secretReadersExamples = `
+  Review who can read secrets anywhere in the cluster
+   $ rakkess secret-readers
+
+  Review who can read the secret called 'db-credentials'
+   $ rakkess secret-readers --name db-credentials
+
+  Only consider grants in the namespace 'prod' and cluster-wide grants
+   $ rakkess secret-readers --namespace prod
+`
+)
+
+var secretName string
+
+var secretReadersCmd = &cobra.Command{
+	Use:     "secret-readers",
+	Short:   "Show all subjects which can read secrets in any namespace",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(secretReadersLongHelp),
+	Example: constants.HelpTextMapName(secretReadersExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.SecretReaders(ctx, opts, secretName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(secretReadersCmd)
+
+	secretReadersCmd.Flags().StringVar(&secretName, constants.FlagName, "", "only consider access to the secret with this name")
+	secretReadersCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join([]string{constants.OutputIconTable, constants.OutputASCIITable}, ", ")))
+	secretReadersCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	secretReadersCmd.Flags().BoolVar(&opts.IgnoreMasters, constants.FlagIgnoreMasters, false, "exclude grants via the group system:masters and the ClusterRole cluster-admin")
+	secretReadersCmd.Flags().StringVar(&opts.ResourceVersion, constants.FlagResourceVersion, "", "list all RBAC objects at this exact resourceVersion to get a consistent snapshot. Fails if the resourceVersion is already compacted by the API server.")
+	opts.ConfigFlags.AddFlags(secretReadersCmd.Flags())
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	topSubjectsLongHelp = `
+Show the most privileged subjects
+
+Ranks all subjects by a privilege score and shows the top ones, which are the
+first to review. The score adds up the weights of the verbs which a subject
+is granted on every resource:
+  %s
+
+The resources are given by --resource. By default, the sensitive resources
+secrets, configmaps, pods, pods/exec, serviceaccounts, and deployments are
+ranked, together with users and groups for impersonate, and the RBAC
+resources roles, rolebindings, clusterroles, and clusterrolebindings for bind
+and escalate. The (Cluster)Roles and their bindings in all
+namespaces are considered, or only those of the namespace given by
+--namespace and the ClusterRoleBindings.
+`
+
+	topSubjectsExamples = `
+  Show the ten subjects with the most access to secrets
+   $ rakkess top-subjects --resource secrets --top 10
+
+  Rank the subjects by their access to the sensitive resources in 'prod'
+   $ rakkess top-subjects --namespace prod
+
+  Feed the leaderboard of deployments and secrets into a dashboard
+   $ rakkess top-subjects --resource deployments.apps,secrets -o json
+`
+)
+
+var (
+	topResources []string
+	top          int
+)
+
+var topSubjectsCmd = &cobra.Command{
+	Use:     "top-subjects",
+	Short:   "Show the most privileged subjects",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(fmt.Sprintf(topSubjectsLongHelp, verbWeightsHelp())),
+	Example: constants.HelpTextMapName(topSubjectsExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.TopSubjects(ctx, opts, topResources, top)
+	},
+}
+
+// verbWeightsHelp lists the verb weights in the order of their weight.
+func verbWeightsHelp() string {
+	verbs := make([]string, 0, len(result.VerbWeights))
+	for v := range result.VerbWeights {
+		verbs = append(verbs, v)
+	}
+	sort.Slice(verbs, func(i, j int) bool {
+		if wi, wj := result.VerbWeights[verbs[i]], result.VerbWeights[verbs[j]]; wi != wj {
+			return wi < wj
+		}
+		return verbs[i] < verbs[j]
+	})
+	weights := make([]string, 0, len(verbs))
+	for _, v := range verbs {
+		weights = append(weights, fmt.Sprintf("%s=%d", v, result.VerbWeights[v]))
+	}
+	return strings.Join(weights, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(topSubjectsCmd)
+
+	formats := []string{constants.OutputIconTable, constants.OutputASCIITable, constants.OutputMarkdown, constants.OutputHTML, constants.OutputJSON, constants.OutputYAML, constants.OutputCSV}
+	topSubjectsCmd.Flags().StringSliceVar(&topResources, constants.FlagResource, nil, "rank the access to these resources, e.g. secrets,deployments.apps (default sensitive resources)")
+	topSubjectsCmd.Flags().IntVar(&top, constants.FlagTop, 10, "show this many subjects, 0 shows all")
+	topSubjectsCmd.Flags().StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", fmt.Sprintf("output format out of (%s)", strings.Join(formats, ", ")))
+	topSubjectsCmd.Flags().StringVar(&opts.OutputFile, constants.FlagOutputFile, "", "write the result to the given file instead of stdout")
+	topSubjectsCmd.Flags().BoolVar(&opts.IgnoreMasters, constants.FlagIgnoreMasters, false, "exclude grants via the group system:masters and the ClusterRole cluster-admin")
+	topSubjectsCmd.Flags().StringVar(&opts.ResourceVersion, constants.FlagResourceVersion, "", "list all RBAC objects at this exact resourceVersion to get a consistent snapshot. Fails if the resourceVersion is already compacted by the API server.")
+	opts.ConfigFlags.AddFlags(topSubjectsCmd.Flags())
+}
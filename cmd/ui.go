@@ -0,0 +1,81 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rakkess "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+const (
+	uiLongHelp = `
+Explore the access matrix in an interactive terminal UI
+
+Shows the access matrix of the current (or impersonated) user full-screen.
+Select a resource and press enter to see the subjects with access to it, and
+select a subject to see the bindings and roles which grant its access.
+
+Keys:
+  up/down, j/k       move the selection (page up/down, home/end jump)
+  left/right, h/l    scroll horizontally
+  /                  filter the rows while typing, enter keeps the filter
+  enter              open the selected row
+  esc, backspace     go back, or clear the filter
+  q, ctrl-c          quit
+
+The subjects are computed from RBAC objects, so this needs to list Roles,
+ClusterRoles, and their bindings. For scripts, use the other commands.
+`
+
+	uiExamples = `
+  Explore the access matrix
+   $ rakkess ui
+
+  Explore the access to namespaced resources in namespace 'default'
+   $ rakkess ui -n default --verbs get,list,create,delete
+`
+)
+
+var uiCmd = &cobra.Command{
+	Use:     "ui",
+	Short:   "Explore the access matrix in an interactive terminal UI",
+	Args:    cobra.NoArgs,
+	Long:    constants.HelpTextMapName(uiLongHelp),
+	Example: constants.HelpTextMapName(uiExamples),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := newContext(opts.Timeout)
+		defer cancel()
+
+		return rakkess.UI(ctx, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+
+	uiCmd.Flags().StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list", "create", "update", "delete"}, fmt.Sprintf("show access for verbs out of (%s)", strings.Join(constants.ValidVerbs, ", ")))
+	uiCmd.Flags().StringSliceVar(&opts.SubjectKinds, constants.FlagSubjectKind, nil, "only show subjects of these kinds, out of (User, Group, ServiceAccount). Can be repeated.")
+	uiCmd.Flags().BoolVar(&opts.IgnoreMasters, constants.FlagIgnoreMasters, false, "exclude grants via the group system:masters and the ClusterRole cluster-admin")
+	uiCmd.Flags().StringVar(&opts.GroupMembersFile, constants.FlagGroupMembers, "", "YAML file which maps group names to member names. Members are shown with the access of their groups.")
+	uiCmd.Flags().IntVar(&opts.MaxConcurrency, constants.FlagMaxConcurrency, constants.DefaultMaxConcurrency, "send at most this many access reviews concurrently. Zero sends all reviews at once.")
+	uiCmd.Flags().IntVar(&opts.MaxRetries, constants.FlagMaxRetries, constants.DefaultMaxRetries, "retry an access review this many times with exponential backoff when the API server is overloaded or fails, e.g. with 429 or 5xx. Denials and authentication errors are never retried.")
+	opts.ConfigFlags.AddFlags(uiCmd.Flags())
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	v1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/klog/v2"
+)
+
+// Capability is a permission which rakkess itself needs to produce complete results.
+type Capability struct {
+	Verb     string
+	Resource schema.GroupResource
+	// Namespaced capabilities are only needed when a namespace is given.
+	Namespaced bool
+	// NeededBy names the command which requires this capability.
+	NeededBy string
+}
+
+// CapabilityAccess is the outcome of reviewing a single Capability.
+type CapabilityAccess struct {
+	Capability
+	Access result.Access
+	// Reason is the explanation given by the authorizer, if any.
+	Reason string
+}
+
+var capabilities = []Capability{
+	{Verb: "create", Resource: schema.GroupResource{Group: "authorization.k8s.io", Resource: "selfsubjectaccessreviews"}, NeededBy: "rakkess"},
+	{Verb: "list", Resource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}, NeededBy: "rakkess resource"},
+	{Verb: "list", Resource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"}, NeededBy: "rakkess resource"},
+	{Verb: "list", Resource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "roles"}, Namespaced: true, NeededBy: "rakkess resource --namespace"},
+	{Verb: "list", Resource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}, Namespaced: true, NeededBy: "rakkess resource --namespace"},
+}
+
+// CheckCapabilities reviews whether the current (or impersonated) user has the
+// permissions rakkess needs for its commands. Namespaced capabilities are
+// skipped if namespace is empty, because they are not needed at cluster scope.
+func CheckCapabilities(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, namespace string) []CapabilityAccess {
+	var res []CapabilityAccess
+	for _, c := range capabilities {
+		if c.Namespaced && namespace == "" {
+			continue
+		}
+
+		attributes := &v1.ResourceAttributes{
+			Verb:     c.Verb,
+			Group:    c.Resource.Group,
+			Resource: c.Resource.Resource,
+		}
+		if c.Namespaced {
+			attributes.Namespace = namespace
+		}
+		req := v1.SelfSubjectAccessReview{
+			Spec: v1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+		}
+
+		ca := CapabilityAccess{Capability: c}
+		countAccessReview()
+		resp, err := sar.Create(ctx, &req, metav1.CreateOptions{})
+		switch {
+		case err != nil:
+			klog.V(2).Infof("Review for %s %s failed: %s", c.Verb, c.Resource, err)
+			ca.Access = result.RequestErr
+			ca.Reason = err.Error()
+		case resp.Status.Allowed:
+			ca.Access = result.Allowed
+		default:
+			ca.Reason = resp.Status.Reason
+		}
+		res = append(res, ca)
+	}
+	return res
+}
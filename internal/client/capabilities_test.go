@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestCheckCapabilities(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		expected  []string
+	}{
+		{
+			name: "cluster scope",
+			expected: []string{
+				"create selfsubjectaccessreviews.authorization.k8s.io -> ok",
+				"list clusterroles.rbac.authorization.k8s.io -> no",
+				"list clusterrolebindings.rbac.authorization.k8s.io -> err",
+			},
+		},
+		{
+			name:      "namespaced",
+			namespace: "some-ns",
+			expected: []string{
+				"create selfsubjectaccessreviews.authorization.k8s.io -> ok",
+				"list clusterroles.rbac.authorization.k8s.io -> no",
+				"list clusterrolebindings.rbac.authorization.k8s.io -> err",
+				"list roles.rbac.authorization.k8s.io in some-ns -> ok",
+				"list rolebindings.rbac.authorization.k8s.io in some-ns -> ok",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+			fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+				func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+					sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+					switch sar.Spec.ResourceAttributes.Resource {
+					case "clusterroles":
+						sar.Status.Reason = "no RBAC policy matched"
+					case "clusterrolebindings":
+						return true, nil, fmt.Errorf("server unavailable")
+					default:
+						sar.Status.Allowed = true
+					}
+					return true, sar, nil
+				})
+
+			var got []string
+			for _, c := range CheckCapabilities(context.Background(), fakeReviews, test.namespace) {
+				outcome := map[result.Access]string{result.Allowed: "ok", result.Denied: "no", result.RequestErr: "err"}[c.Access]
+				entry := fmt.Sprintf("%s %s", c.Verb, c.Resource)
+				if c.Namespaced {
+					entry += " in " + test.namespace
+				}
+				got = append(got, entry+" -> "+outcome)
+				if c.Access != result.Allowed {
+					assert.NotEmpty(t, c.Reason)
+				}
+			}
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
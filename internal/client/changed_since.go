@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// for testing
+	getCRDChanges = getCRDChangesImpl
+	now           = time.Now
+)
+
+// changedSince returns the point in time after which objects count as
+// changed. It is zero if the scan is not restricted to recent changes.
+func changedSince(opts *options.RakkessOptions) time.Time {
+	if opts.ChangedSince <= 0 {
+		return time.Time{}
+	}
+	return now().Add(-opts.ChangedSince)
+}
+
+// lastChanged is the latest time at which the object was created or updated,
+// according to its creationTimestamp and managedFields.
+func lastChanged(obj metav1.Object) time.Time {
+	last := obj.GetCreationTimestamp().Time
+	for _, f := range obj.GetManagedFields() {
+		if f.Time != nil && f.Time.After(last) {
+			last = f.Time.Time
+		}
+	}
+	return last
+}
+
+// changedAfter checks if the object changed after the given time. Every
+// object counts as changed if the time is zero.
+func changedAfter(obj metav1.Object, since time.Time) bool {
+	return since.IsZero() || lastChanged(obj).After(since)
+}
+
+// filterByCRDChanges retains the resources whose CustomResourceDefinition
+// changed after the given time. Built-in resources have no
+// CustomResourceDefinition and are dropped.
+func filterByCRDChanges(opts *options.RakkessOptions, grs []GroupResource, since time.Time) ([]GroupResource, error) {
+	changes, err := getCRDChanges(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "get CustomResourceDefinitions")
+	}
+
+	var filtered []GroupResource
+	for _, gr := range grs {
+		// subresources such as widgets/status belong to the CRD of the main resource
+		resource := strings.SplitN(gr.APIResource.Name, "/", 2)[0]
+		changed, ok := changes[schema.GroupResource{Group: gr.APIGroup, Resource: resource}]
+		if !ok {
+			klog.V(2).Infof("Skipping %s without CustomResourceDefinition", gr.fullName())
+			continue
+		}
+		if changed.After(since) {
+			filtered = append(filtered, gr)
+		}
+	}
+	return filtered, nil
+}
+
+// getCRDChangesImpl lists all CustomResourceDefinitions and returns the time
+// of their last change by the GroupResource they define.
+func getCRDChangesImpl(opts *options.RakkessOptions) (map[schema.GroupResource]time.Time, error) {
+	crds, err := listCRDs(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[schema.GroupResource]time.Time, len(crds))
+	for i := range crds {
+		ret[definedGroupResource(crds[i])] = lastChanged(&crds[i])
+	}
+	return ret, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	cutoff       = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	beforeCutoff = metav1.NewTime(cutoff.Add(-time.Hour))
+	afterCutoff  = metav1.NewTime(cutoff.Add(time.Hour))
+)
+
+func TestChangedSince(t *testing.T) {
+	now = func() time.Time { return cutoff.Add(2 * time.Hour) }
+	defer func() { now = time.Now }()
+
+	assert.True(t, changedSince(&options.RakkessOptions{}).IsZero())
+	assert.Equal(t, cutoff, changedSince(&options.RakkessOptions{ChangedSince: 2 * time.Hour}))
+}
+
+func TestChangedAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     metav1.ObjectMeta
+		since    time.Time
+		expected bool
+	}{
+		{
+			name:     "old",
+			meta:     metav1.ObjectMeta{CreationTimestamp: beforeCutoff},
+			since:    cutoff,
+			expected: false,
+		},
+		{
+			name:     "created recently",
+			meta:     metav1.ObjectMeta{CreationTimestamp: afterCutoff},
+			since:    cutoff,
+			expected: true,
+		},
+		{
+			name: "updated recently",
+			meta: metav1.ObjectMeta{
+				CreationTimestamp: beforeCutoff,
+				ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kubectl"}, {Manager: "helm", Time: &afterCutoff}},
+			},
+			since:    cutoff,
+			expected: true,
+		},
+		{
+			name:     "no restriction",
+			meta:     metav1.ObjectMeta{CreationTimestamp: beforeCutoff},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, changedAfter(&test.meta, test.since))
+		})
+	}
+}
+
+func TestFilterByCRDChanges(t *testing.T) {
+	pods := GroupResource{APIResource: metav1.APIResource{Name: "pods"}}
+	secrets := GroupResource{APIGroup: "vault.example.com", APIResource: metav1.APIResource{Name: "secrets"}}
+	secretsStatus := GroupResource{APIGroup: "vault.example.com", APIResource: metav1.APIResource{Name: "secrets/status"}}
+	widgets := GroupResource{APIGroup: "example.com", APIResource: metav1.APIResource{Name: "widgets"}}
+
+	getCRDChanges = func(*options.RakkessOptions) (map[schema.GroupResource]time.Time, error) {
+		return map[schema.GroupResource]time.Time{
+			{Group: "vault.example.com", Resource: "secrets"}: afterCutoff.Time,
+			{Group: "example.com", Resource: "widgets"}:       beforeCutoff.Time,
+		}, nil
+	}
+	defer func() { getCRDChanges = getCRDChangesImpl }()
+
+	actual, err := filterByCRDChanges(&options.RakkessOptions{}, []GroupResource{pods, secrets, secretsStatus, widgets}, cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, []GroupResource{secrets, secretsStatus}, actual)
+}
+
+func TestResolveBindingsChangedSince(t *testing.T) {
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleBindingList{Items: []v1.ClusterRoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "old", CreationTimestamp: beforeCutoff},
+					RoleRef:    v1.RoleRef{Kind: clusterRoleName, Name: testClusterRoleName},
+					Subjects:   []v1.Subject{{Kind: subjectKind, Name: "alice"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "new", CreationTimestamp: afterCutoff},
+					RoleRef:    v1.RoleRef{Kind: clusterRoleName, Name: testClusterRoleName},
+					Subjects:   []v1.Subject{{Kind: subjectKind, Name: "bob"}},
+				},
+			}}, nil
+		})
+
+	sa := result.NewSubjectAccess(schema.GroupResource{Resource: "pods"}, "")
+	sa.MatchRules(result.RoleRef{Name: testClusterRoleName, Kind: clusterRoleName}, clusterRoles("", "pods", "get")[0].Rules[0])
+
+	err := resolveClusterRoleBindings(context.Background(), fakeRbacClient, sa, metav1.ListOptions{}, cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, map[result.SubjectRef]sets.String{{Name: "bob", Kind: subjectKind}: sets.NewString("get")}, sa.Get())
+}
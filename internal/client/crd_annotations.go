@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// for testing
+	getCRDAnnotations = getCRDAnnotationsImpl
+
+	crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+)
+
+// filterByCRDAnnotations retains the resources whose CustomResourceDefinition
+// carries annotations matching the selector. Built-in resources have no
+// CustomResourceDefinition and are dropped.
+func filterByCRDAnnotations(opts *options.RakkessOptions, grs []GroupResource) ([]GroupResource, error) {
+	selector, err := labels.Parse(opts.ResourceAnnotationSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse resource annotation selector")
+	}
+
+	annotations, err := getCRDAnnotations(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "get CustomResourceDefinitions")
+	}
+
+	var filtered []GroupResource
+	for _, gr := range grs {
+		// subresources such as widgets/status belong to the CRD of the main resource
+		resource := strings.SplitN(gr.APIResource.Name, "/", 2)[0]
+		a, ok := annotations[schema.GroupResource{Group: gr.APIGroup, Resource: resource}]
+		if !ok {
+			klog.V(2).Infof("Skipping %s without CustomResourceDefinition", gr.fullName())
+			continue
+		}
+		if selector.Matches(labels.Set(a)) {
+			filtered = append(filtered, gr)
+		}
+	}
+	return filtered, nil
+}
+
+// getCRDAnnotationsImpl lists all CustomResourceDefinitions and returns their
+// annotations by the GroupResource they define.
+func getCRDAnnotationsImpl(opts *options.RakkessOptions) (map[schema.GroupResource]map[string]string, error) {
+	crds, err := listCRDs(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[schema.GroupResource]map[string]string, len(crds))
+	for _, crd := range crds {
+		ret[definedGroupResource(crd)] = crd.GetAnnotations()
+	}
+	return ret, nil
+}
+
+// listCRDs lists all CustomResourceDefinitions.
+func listCRDs(opts *options.RakkessOptions) ([]unstructured.Unstructured, error) {
+	restConfig, err := opts.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	countList()
+	crds, err := client.Resource(crdResource).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return crds.Items, nil
+}
+
+// definedGroupResource returns the GroupResource which is defined by the
+// given CustomResourceDefinition.
+func definedGroupResource(crd unstructured.Unstructured) schema.GroupResource {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	return schema.GroupResource{Group: group, Resource: plural}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestFilterByCRDAnnotations(t *testing.T) {
+	pods := GroupResource{APIResource: metav1.APIResource{Name: "pods"}}
+	secrets := GroupResource{APIGroup: "vault.example.com", APIResource: metav1.APIResource{Name: "secrets"}}
+	secretsStatus := GroupResource{APIGroup: "vault.example.com", APIResource: metav1.APIResource{Name: "secrets/status"}}
+	widgets := GroupResource{APIGroup: "example.com", APIResource: metav1.APIResource{Name: "widgets"}}
+	grs := []GroupResource{pods, secrets, secretsStatus, widgets}
+
+	getCRDAnnotations = func(*options.RakkessOptions) (map[schema.GroupResource]map[string]string, error) {
+		return map[schema.GroupResource]map[string]string{
+			{Group: "vault.example.com", Resource: "secrets"}: {"sensitivity": "high"},
+			{Group: "example.com", Resource: "widgets"}:       {"sensitivity": "low"},
+		}, nil
+	}
+	defer func() { getCRDAnnotations = getCRDAnnotationsImpl }()
+
+	tests := []struct {
+		name     string
+		selector string
+		expected []GroupResource
+		err      string
+	}{
+		{
+			name:     "equality",
+			selector: "sensitivity=high",
+			expected: []GroupResource{secrets, secretsStatus},
+		},
+		{
+			name:     "existence",
+			selector: "sensitivity",
+			expected: []GroupResource{secrets, secretsStatus, widgets},
+		},
+		{
+			name:     "no match",
+			selector: "owner=security",
+		},
+		{
+			name:     "invalid selector",
+			selector: "sensitivity==in(",
+			err:      "parse resource annotation selector",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &options.RakkessOptions{ResourceAnnotationSelector: test.selector}
+			actual, err := filterByCRDAnnotations(opts, grs)
+			if test.err != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// LoadAccessDescriptions returns the descriptions of verb combinations for
+// --describe. They are read from a YAML or JSON file, if one is given:
+//
+//	# descriptions.yaml
+//	- description: can deploy
+//	  verbs: [get, create, update, patch]
+//	- description: read-only
+//	  verbs: [get, list, watch]
+//
+// Otherwise, the built-in descriptions are used.
+func LoadAccessDescriptions(opts *options.RakkessOptions) ([]constants.AccessDescription, error) {
+	if opts.DescribeFile == "" {
+		return constants.AccessDescriptions, nil
+	}
+	data, err := ioutil.ReadFile(opts.DescribeFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "read access descriptions")
+	}
+	var descriptions []constants.AccessDescription
+	if err := yaml.UnmarshalStrict(data, &descriptions); err != nil {
+		return nil, errors.Wrapf(err, "parse access descriptions from %s", opts.DescribeFile)
+	}
+	return descriptions, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAccessDescriptions(t *testing.T) {
+	opts := &options.RakkessOptions{}
+	actual, err := LoadAccessDescriptions(opts)
+	require.NoError(t, err)
+	assert.Equal(t, constants.AccessDescriptions, actual)
+
+	opts.DescribeFile = filepath.Join(t.TempDir(), "descriptions.yaml")
+	require.NoError(t, ioutil.WriteFile(opts.DescribeFile, []byte("- description: can deploy\n  verbs: [get, create]\n"), 0o600))
+	actual, err = LoadAccessDescriptions(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []constants.AccessDescription{{Description: "can deploy", Verbs: []string{"get", "create"}}}, actual)
+
+	require.NoError(t, ioutil.WriteFile(opts.DescribeFile, []byte("- name: can deploy\n"), 0o600))
+	_, err = LoadAccessDescriptions(opts)
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	v1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// ExplainDenied explains every denied verb in the access result. One
+// SelfSubjectRulesReview tells whether the RBAC rules of the user grant the
+// verb, and the denied access reviews are repeated to obtain their reason.
+func ExplainDenied(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, ssrr authv1.SelfSubjectRulesReviewInterface, grs []GroupResource, ra result.ResourceAccess, namespace *string, resourceName string) (result.DenyExplanations, error) {
+	rules, err := reviewRules(ctx, ssrr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var ns string
+	if namespace != nil {
+		ns = *namespace
+	}
+
+	var explanations result.DenyExplanations
+	for _, gr := range grs {
+		access, ok := ra[gr.fullName()]
+		if !ok {
+			continue
+		}
+		grNamespace := ns
+		if !gr.APIResource.Namespaced {
+			grNamespace = ""
+		}
+		for v, a := range access {
+			if a != result.Denied {
+				continue
+			}
+			req := v1.SelfSubjectAccessReview{
+				Spec: v1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: gr.resourceAttributes(v, grNamespace, resourceName),
+				},
+			}
+			countAccessReview()
+			resp, err := sar.Create(ctx, &req, metav1.CreateOptions{})
+			if err != nil {
+				return nil, err
+			}
+			reason := resp.Status.Reason
+			if reason == "" {
+				reason = resp.Status.EvaluationError
+			}
+			explanations = append(explanations, result.DenyExplanation{
+				Resource:   gr.fullName(),
+				Verb:       v,
+				RulesGrant: rulesAllow(rules, gr.APIGroup, gr.APIResource.Name, resourceName, v),
+				Reason:     reason,
+			})
+		}
+	}
+	explanations.Sort()
+	return explanations, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestExplainDenied(t *testing.T) {
+	ctx := context.Background()
+	namespace := "prod"
+
+	fakeAuthClient := &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}
+	fakeAuthClient.Fake.AddReactor("create", "selfsubjectrulesreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			review := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectRulesReview)
+			review.Status.ResourceRules = []v1.ResourceRule{
+				{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			}
+			return true, review, nil
+		})
+	fakeAuthClient.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			if sar.Spec.ResourceAttributes.Verb == "delete" {
+				sar.Status.Reason = "denied by policy webhook"
+			}
+			return true, sar, nil
+		})
+
+	grs := []GroupResource{
+		toGroupResource("", "pods", "list", "delete"),
+		toGroupResource("", "configmaps", "list"),
+	}
+	ra := result.ResourceAccess{
+		"pods":       {"list": result.Denied, "delete": result.Denied},
+		"configmaps": {"list": result.Allowed},
+	}
+	explanations, err := ExplainDenied(ctx, fakeAuthClient.SelfSubjectAccessReviews(), fakeAuthClient.SelfSubjectRulesReviews(), grs, ra, &namespace, "")
+	require.NoError(t, err)
+	assert.Equal(t, result.DenyExplanations{
+		{Resource: "pods", Verb: "delete", RulesGrant: true, Reason: "denied by policy webhook"},
+		{Resource: "pods", Verb: "list"},
+	}, explanations)
+
+	table := explanations.Table()
+	assert.Equal(t, []string{"pods", "delete", "denied by policy webhook (granted by RBAC rules)"}, table.Rows[0].Intro)
+	assert.Equal(t, []string{"pods", "list", "no RBAC rule grants this verb"}, table.Rows[1].Intro)
+}
@@ -18,11 +18,17 @@ package client
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 
+	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/options"
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
 )
@@ -34,18 +40,45 @@ var (
 
 // GroupResource contains the APIGroup and APIResource
 type GroupResource struct {
-	APIGroup    string
+	APIGroup string
+	// APIVersion is only set when resources are fetched for all versions, not only the preferred ones.
+	APIVersion  string
 	APIResource metav1.APIResource
 }
 
-// Extracts the full name including APIGroup, e.g. 'deployment.apps'
+// Extracts the full name including APIGroup, e.g. 'deployment.apps'.
+// If the APIVersion is set, the full name includes the group version, e.g. 'deployment.apps/v1'.
 func (g GroupResource) fullName() string {
+	if g.APIVersion != "" {
+		gv := schema.GroupVersion{Group: g.APIGroup, Version: g.APIVersion}
+		return fmt.Sprintf("%s.%s", g.APIResource.Name, gv)
+	}
 	if g.APIGroup == "" {
 		return g.APIResource.Name
 	}
 	return fmt.Sprintf("%s.%s", g.APIResource.Name, g.APIGroup)
 }
 
+// resourceAttributes returns the attributes for an access review of the verb.
+// Subresources such as pods/log are split into the resource and the
+// subresource, because authorizers other than RBAC do not match the joined
+// name. A non-empty name narrows the review to that single object.
+func (g GroupResource) resourceAttributes(verb, namespace, name string) *v1.ResourceAttributes {
+	resource := strings.SplitN(g.APIResource.Name, "/", 2)
+	attributes := &v1.ResourceAttributes{
+		Verb:      verb,
+		Resource:  resource[0],
+		Group:     g.APIGroup,
+		Version:   g.APIVersion,
+		Namespace: namespace,
+		Name:      name,
+	}
+	if len(resource) == 2 {
+		attributes.Subresource = resource[1]
+	}
+	return attributes
+}
+
 // FetchAvailableGroupResources fetches a list of known APIResources on the server.
 func FetchAvailableGroupResources(opts *options.RakkessOptions) ([]GroupResource, error) {
 	client, err := getDiscoveryClient(opts)
@@ -53,33 +86,65 @@ func FetchAvailableGroupResources(opts *options.RakkessOptions) ([]GroupResource
 		return nil, errors.Wrap(err, "discovery client")
 	}
 
-	client.Invalidate()
+	// The discovery cache under --cache-dir is shared with kubectl and expires
+	// after the same TTL, so repeated runs can skip most discovery requests.
+	if opts.NoCache {
+		client.Invalidate()
+	}
+
+	namespaced := opts.ConfigFlags.Namespace != nil && *opts.ConfigFlags.Namespace != ""
 
 	var resourcesFetcher func() ([]*metav1.APIResourceList, error)
-	if opts.ConfigFlags.Namespace == nil || *opts.ConfigFlags.Namespace == "" {
+	switch {
+	case opts.PreferredOnly && !namespaced:
 		resourcesFetcher = client.ServerPreferredResources
-	} else {
+	case opts.PreferredOnly:
 		resourcesFetcher = client.ServerPreferredNamespacedResources
+	default:
+		resourcesFetcher = func() ([]*metav1.APIResourceList, error) {
+			return serverResourcesForAllVersions(client, namespaced)
+		}
 	}
 
 	resources, err := resourcesFetcher()
-	if err != nil {
-		if resources == nil {
-			return nil, errors.Wrap(err, "get preferred resources")
-		}
+	if !client.Fresh() {
+		// every resource list from the cache spares a discovery request
+		countCacheHits(len(resources))
+	}
+	failed := failedGroupVersions(err)
+	switch {
+	case err == nil:
+	case resources == nil:
+		return nil, errors.Wrap(err, "get server resources")
+	case len(failed) > 0:
+		// e.g. an unhealthy APIService such as a dead metrics-server
+		klog.Warningf("Skipping API groups whose discovery failed, result will be incomplete: %s", describeGroupVersions(failed))
+	default:
 		klog.Warningf("Could not fetch full list of resources, result will be incomplete: %s", err)
 	}
 
+	wantGroups := apiGroups(opts.APIGroups)
+	servedGroups := sets.NewString()
+	for gv := range failed {
+		// already reported as failed, not as unserved
+		servedGroups.Insert(gv.Group)
+	}
+
 	var grs []GroupResource
 	for _, list := range resources {
-		if len(list.APIResources) == 0 {
-			continue
-		}
 		gv, err := schema.ParseGroupVersion(list.GroupVersion)
 		if err != nil {
 			klog.Warningf("Cannot parse groupVersion: %s", err)
 			continue
 		}
+		servedGroups.Insert(gv.Group)
+		if len(list.APIResources) == 0 {
+			continue
+		}
+		if wantGroups != nil && !wantGroups.Has(gv.Group) {
+			// skip before fetching the subresources, which needs another request
+			continue
+		}
 		resourceListWithSubresources, err := client.ServerResourcesForGroupVersion(list.GroupVersion)
 		if err != nil {
 			klog.Warningf("Cannot parse get all resources for gv: %s %s", list.GroupVersion, err)
@@ -89,17 +154,235 @@ func FetchAvailableGroupResources(opts *options.RakkessOptions) ([]GroupResource
 			if len(r.Verbs) == 0 {
 				continue
 			}
+			if opts.AssumeVerbsSupported {
+				// some servers under-report the verbs of custom resources
+				r.Verbs = sets.NewString(r.Verbs...).Insert(opts.Verbs...).List()
+			} else if opts.AllowCustomVerbs {
+				// discovery does not list custom verbs, so they are reviewed on every resource
+				custom := sets.NewString(opts.Verbs...).Difference(sets.NewString(constants.ValidVerbs...))
+				r.Verbs = sets.NewString(r.Verbs...).Union(custom).List()
+			}
 
-			grs = append(grs, GroupResource{
+			gr := GroupResource{
 				APIGroup:    gv.Group,
 				APIResource: r,
-			})
+			}
+			if !opts.PreferredOnly {
+				gr.APIVersion = gv.Version
+			}
+			grs = append(grs, gr)
+		}
+	}
+
+	if missing := wantGroups.Difference(servedGroups); missing.Len() > 0 {
+		names := missing.List()
+		if names[0] == "" {
+			names[0] = "core"
 		}
+		return nil, fmt.Errorf("API groups not served by the cluster: %s", strings.Join(names, ", "))
 	}
 
+	if opts.ResourceAnnotationSelector != "" {
+		if grs, err = filterByCRDAnnotations(opts, grs); err != nil {
+			return nil, err
+		}
+	}
+	if opts.AsNode != "" {
+		grs = filterNodeResources(grs)
+	}
+	if len(opts.Resources) > 0 {
+		if grs, err = filterResources(grs, opts.Resources); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.ResourcePatterns) > 0 {
+		if grs, err = filterResourcePatterns(grs, opts.ResourcePatterns); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Subresources) > 0 {
+		if grs, err = filterSubresources(grs, opts.Subresources); err != nil {
+			return nil, err
+		}
+	}
+	if since := changedSince(opts); !since.IsZero() {
+		return filterByCRDChanges(opts, grs, since)
+	}
 	return grs, nil
 }
 
+// apiGroups returns the set of the given API groups, where "core" stands for
+// the core group "". It returns nil if no groups are given, so that all
+// groups are kept.
+func apiGroups(groups []string) sets.String {
+	if len(groups) == 0 {
+		return nil
+	}
+	set := sets.NewString()
+	for _, g := range groups {
+		if g == "core" {
+			g = ""
+		}
+		set.Insert(g)
+	}
+	return set
+}
+
+// filterNodeResources retains the resources which nodes read or write,
+// including their subresources such as pods/status.
+func filterNodeResources(grs []GroupResource) []GroupResource {
+	nodeResources := sets.NewString(constants.NodeResources...)
+	var filtered []GroupResource
+	for _, gr := range grs {
+		main := GroupResource{APIGroup: gr.APIGroup, APIResource: metav1.APIResource{Name: strings.SplitN(gr.APIResource.Name, "/", 2)[0]}}
+		if nodeResources.Has(main.fullName()) {
+			filtered = append(filtered, gr)
+		}
+	}
+	return filtered
+}
+
+// filterResources retains the given resources. A resource is given by its name,
+// singular name, or short name, optionally qualified with its API group, e.g.
+// deployments.apps. All given resources must be found.
+func filterResources(grs []GroupResource, resources []string) ([]GroupResource, error) {
+	wanted := sets.NewString(resources...)
+	found := sets.NewString()
+	var filtered []GroupResource
+	for _, gr := range grs {
+		r := gr.APIResource
+		names := append([]string{r.Name, r.SingularName}, r.ShortNames...)
+		matched := false
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			qualified := GroupResource{APIGroup: gr.APIGroup, APIResource: metav1.APIResource{Name: name}}.fullName()
+			for _, candidate := range []string{name, qualified} {
+				if wanted.Has(candidate) {
+					found.Insert(candidate)
+					matched = true
+				}
+			}
+		}
+		if matched {
+			filtered = append(filtered, gr)
+		}
+	}
+	if missing := wanted.Difference(found); missing.Len() > 0 {
+		return nil, fmt.Errorf("resources not served by the cluster: %s", strings.Join(missing.List(), ", "))
+	}
+	return filtered, nil
+}
+
+// filterResourcePatterns retains the resources which match any of the given
+// glob patterns, such as network* or *.networking.k8s.io. A pattern matches
+// the resource name and the name qualified with its API group. Every pattern
+// must match at least one resource, so that typos are caught.
+func filterResourcePatterns(grs []GroupResource, patterns []string) ([]GroupResource, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid resource pattern %q: %v", p, err)
+		}
+	}
+
+	matched := sets.NewString()
+	var filtered []GroupResource
+	for _, gr := range grs {
+		qualified := GroupResource{APIGroup: gr.APIGroup, APIResource: gr.APIResource}.fullName()
+		keep := false
+		for _, p := range patterns {
+			for _, name := range []string{gr.APIResource.Name, qualified} {
+				if ok, _ := path.Match(p, name); ok {
+					matched.Insert(p)
+					keep = true
+				}
+			}
+		}
+		if keep {
+			filtered = append(filtered, gr)
+		}
+	}
+	if unmatched := sets.NewString(patterns...).Difference(matched); unmatched.Len() > 0 {
+		return nil, fmt.Errorf("resource patterns match no resources: %s", strings.Join(unmatched.List(), ", "))
+	}
+	return filtered, nil
+}
+
+// filterSubresources retains the main resources and the given subresources,
+// such as log or exec for pods/log and pods/exec. All other subresources are
+// dropped. Every given subresource must be served by some resource.
+func filterSubresources(grs []GroupResource, subresources []string) ([]GroupResource, error) {
+	wanted := sets.NewString(subresources...)
+	found := sets.NewString()
+	var filtered []GroupResource
+	for _, gr := range grs {
+		parts := strings.SplitN(gr.APIResource.Name, "/", 2)
+		if len(parts) == 2 {
+			if !wanted.Has(parts[1]) {
+				continue
+			}
+			found.Insert(parts[1])
+		}
+		filtered = append(filtered, gr)
+	}
+	if missing := wanted.Difference(found); missing.Len() > 0 {
+		return nil, fmt.Errorf("subresources not served by the cluster: %s", strings.Join(missing.List(), ", "))
+	}
+	return filtered, nil
+}
+
+// failedGroupVersions returns the group versions whose discovery failed, if err
+// is a discovery.ErrGroupDiscoveryFailed. The resources of all other groups
+// are still discovered in that case.
+func failedGroupVersions(err error) map[schema.GroupVersion]error {
+	var failed *discovery.ErrGroupDiscoveryFailed
+	if !errors.As(err, &failed) {
+		return nil
+	}
+	return failed.Groups
+}
+
+// describeGroupVersions lists the group versions with their errors in order,
+// e.g. "metrics.k8s.io/v1beta1 (the server is currently unable to handle the request)".
+func describeGroupVersions(failed map[schema.GroupVersion]error) string {
+	descriptions := make([]string, 0, len(failed))
+	for gv, err := range failed {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%v)", gv, err))
+	}
+	sort.Strings(descriptions)
+	return strings.Join(descriptions, ", ")
+}
+
+// serverResourcesForAllVersions lists the resources of every served group version,
+// not only the preferred one.
+func serverResourcesForAllVersions(client discovery.DiscoveryInterface, namespaced bool) ([]*metav1.APIResourceList, error) {
+	_, resources, err := client.ServerGroupsAndResources()
+	if resources == nil || !namespaced {
+		return resources, err
+	}
+	return discovery.FilteredBy(discovery.ResourcePredicateFunc(func(_ string, r *metav1.APIResource) bool {
+		return r.Namespaced
+	}), resources), err
+}
+
+// SupportedVerbs returns the union of the verbs which the resources support.
+// The standard verbs come first in canonical order, followed by the custom
+// verbs in alphabetical order.
+func SupportedVerbs(grs []GroupResource) []string {
+	supported := sets.NewString()
+	for _, gr := range grs {
+		supported.Insert(gr.APIResource.Verbs...)
+	}
+	var verbs []string
+	for _, v := range constants.ValidVerbs {
+		if supported.Has(v) {
+			verbs = append(verbs, v)
+		}
+	}
+	return append(verbs, supported.Difference(sets.NewString(constants.ValidVerbs...)).List()...)
+}
+
 func getDiscoveryClientImpl(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
 	return opts.DiscoveryClient()
 }
@@ -24,6 +24,7 @@ import (
 	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
@@ -33,8 +34,10 @@ import (
 type fakeCachedDiscoveryInterface struct {
 	invalidateCalls int
 	next            metav1.APIResourceList
-	err             error
-	fresh           bool
+	// allVersions is returned by ServerGroupsAndResources; next is the preferred version
+	allVersions []*metav1.APIResourceList
+	err         error
+	fresh       bool
 }
 
 var _ discovery.CachedDiscoveryInterface = &fakeCachedDiscoveryInterface{}
@@ -57,10 +60,18 @@ func (c *fakeCachedDiscoveryInterface) ServerGroups() (*metav1.APIGroupList, err
 }
 
 func (c *fakeCachedDiscoveryInterface) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
-	panic("not implemented")
+	if c.allVersions == nil {
+		return nil, []*metav1.APIResourceList{&c.next}, c.err
+	}
+	return nil, c.allVersions, c.err
 }
 
 func (c *fakeCachedDiscoveryInterface) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	for _, list := range c.allVersions {
+		if list.GroupVersion == groupVersion {
+			return list, nil
+		}
+	}
 	return &c.next, nil
 }
 
@@ -109,16 +120,33 @@ var (
 		Namespaced: true,
 		Verbs:      []string{"list"},
 	}
+	cWidget = metav1.APIResource{
+		Name:       "widgets",
+		Kind:       "Widget",
+		Namespaced: true,
+		Verbs:      []string{"list"},
+	}
+	cCluster = metav1.APIResource{
+		Name:       "clusterwidgets",
+		Kind:       "ClusterWidget",
+		Namespaced: false,
+		Verbs:      []string{"list"},
+	}
+	multiVersion = []*metav1.APIResourceList{
+		{GroupVersion: "c/v1", APIResources: []metav1.APIResource{cWidget, cCluster}},
+		{GroupVersion: "c/v1beta1", APIResources: []metav1.APIResource{cWidget}},
+	}
 )
 
 func TestFetchAvailableGroupResources(t *testing.T) {
 	tests := []struct {
-		name      string
-		namespace string
-		verbs     []string
-		resources metav1.APIResourceList
-		err       error
-		expected  interface{}
+		name        string
+		namespace   string
+		verbs       []string
+		resources   metav1.APIResourceList
+		allVersions []*metav1.APIResourceList
+		err         error
+		expected    interface{}
 	}{
 		{
 			name:  "cluster resources",
@@ -170,13 +198,28 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 			},
 			expected: []GroupResource(nil),
 		},
+		{
+			name:  "multi-version resources only at preferred version",
+			verbs: []string{"list"},
+			resources: metav1.APIResourceList{
+				GroupVersion: "c/v1",
+				APIResources: []metav1.APIResource{cWidget},
+			},
+			allVersions: multiVersion,
+			expected: []GroupResource{
+				{APIGroup: "c", APIResource: cWidget},
+				{APIGroup: "c", APIResource: cCluster},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			fakeRbacClient := &fakeCachedDiscoveryInterface{
-				next: test.resources,
-				err:  test.err,
+				next:        test.resources,
+				allVersions: test.allVersions,
+				err:         test.err,
+				fresh:       true,
 			}
 
 			getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
@@ -188,6 +231,7 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 				ConfigFlags: &genericclioptions.ConfigFlags{
 					Namespace: &test.namespace,
 				},
+				PreferredOnly: true,
 			}
 			grs, err := FetchAvailableGroupResources(opts)
 			assert.NoError(t, err)
@@ -196,6 +240,293 @@ func TestFetchAvailableGroupResources(t *testing.T) {
 	}
 }
 
+func TestFetchAvailableGroupResources_cacheHits(t *testing.T) {
+	lists := []*metav1.APIResourceList{
+		{GroupVersion: "a/v1", APIResources: []metav1.APIResource{aFoo}},
+		{GroupVersion: "c/v1", APIResources: []metav1.APIResource{cWidget}},
+	}
+	defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+	for _, fresh := range []bool{false, true} {
+		getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+			return &fakeCachedDiscoveryInterface{allVersions: lists, fresh: fresh}, nil
+		}
+
+		namespace := ""
+		opts := &options.RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{Namespace: &namespace}}
+		before := Stats().CacheHits
+		_, err := FetchAvailableGroupResources(opts)
+		assert.NoError(t, err)
+
+		expected := int64(2)
+		if fresh {
+			expected = 0
+		}
+		assert.Equal(t, expected, Stats().CacheHits-before, "fresh discovery: %t", fresh)
+	}
+}
+
+func TestFetchAvailableGroupResources_failedGroups(t *testing.T) {
+	metrics := schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	fakeClient := &fakeCachedDiscoveryInterface{
+		next: metav1.APIResourceList{GroupVersion: "a/v1", APIResources: []metav1.APIResource{aFoo}},
+		err: &discovery.ErrGroupDiscoveryFailed{Groups: map[schema.GroupVersion]error{
+			metrics: fmt.Errorf("the server is currently unable to handle the request"),
+		}},
+		fresh: true,
+	}
+	getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+		return fakeClient, nil
+	}
+	defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+
+	namespace := ""
+	opts := &options.RakkessOptions{
+		ConfigFlags:   &genericclioptions.ConfigFlags{Namespace: &namespace},
+		PreferredOnly: true,
+	}
+	grs, err := FetchAvailableGroupResources(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []GroupResource{{APIGroup: "a", APIResource: aFoo}}, grs, "the healthy groups are still discovered")
+
+	opts.APIGroups = []string{"a", "metrics.k8s.io"}
+	_, err = FetchAvailableGroupResources(opts)
+	assert.NoError(t, err, "failed groups are not reported as unserved")
+
+	assert.Equal(t, "apps/v1 (timeout), metrics.k8s.io/v1beta1 (the server is currently unable to handle the request)", describeGroupVersions(map[schema.GroupVersion]error{
+		metrics:                        fmt.Errorf("the server is currently unable to handle the request"),
+		{Group: "apps", Version: "v1"}: fmt.Errorf("timeout"),
+	}))
+	assert.Nil(t, failedGroupVersions(fmt.Errorf("list is incomplete")))
+}
+
+func TestFetchAvailableGroupResources_allVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		expected  []GroupResource
+	}{
+		{
+			name: "cluster scope",
+			expected: []GroupResource{
+				{APIGroup: "c", APIVersion: "v1", APIResource: cWidget},
+				{APIGroup: "c", APIVersion: "v1", APIResource: cCluster},
+				{APIGroup: "c", APIVersion: "v1beta1", APIResource: cWidget},
+			},
+		},
+		{
+			name:      "namespaced",
+			namespace: "any-namespace",
+			expected: []GroupResource{
+				{APIGroup: "c", APIVersion: "v1", APIResource: cWidget},
+				{APIGroup: "c", APIVersion: "v1", APIResource: cCluster},
+				{APIGroup: "c", APIVersion: "v1beta1", APIResource: cWidget},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := &fakeCachedDiscoveryInterface{allVersions: multiVersion}
+			getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+				return fakeClient, nil
+			}
+			defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+
+			opts := &options.RakkessOptions{
+				ConfigFlags: &genericclioptions.ConfigFlags{
+					Namespace: &test.namespace,
+				},
+			}
+			grs, err := FetchAvailableGroupResources(opts)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, grs)
+		})
+	}
+}
+
+func TestFetchAvailableGroupResources_apiGroups(t *testing.T) {
+	fakeClient := &fakeCachedDiscoveryInterface{allVersions: multiVersion}
+	getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+		return fakeClient, nil
+	}
+	defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+
+	namespace := ""
+	opts := &options.RakkessOptions{
+		ConfigFlags: &genericclioptions.ConfigFlags{Namespace: &namespace},
+		APIGroups:   []string{"c"},
+	}
+	grs, err := FetchAvailableGroupResources(opts)
+	assert.NoError(t, err)
+	assert.Len(t, grs, 3)
+
+	opts.APIGroups = []string{"c", "core", "x"}
+	_, err = FetchAvailableGroupResources(opts)
+	assert.EqualError(t, err, "API groups not served by the cluster: core, x")
+}
+
+func TestFetchAvailableGroupResources_assumeVerbsSupported(t *testing.T) {
+	fakeClient := &fakeCachedDiscoveryInterface{
+		next: metav1.APIResourceList{
+			GroupVersion: "a/v1",
+			APIResources: []metav1.APIResource{aFoo, aNoVerbs},
+		},
+		fresh: true,
+	}
+	getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+		return fakeClient, nil
+	}
+	defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+
+	namespace := ""
+	opts := &options.RakkessOptions{
+		ConfigFlags:          &genericclioptions.ConfigFlags{Namespace: &namespace},
+		PreferredOnly:        true,
+		Verbs:                []string{"get", "list", "delete"},
+		AssumeVerbsSupported: true,
+	}
+	grs, err := FetchAvailableGroupResources(opts)
+	assert.NoError(t, err)
+
+	foo := aFoo
+	foo.Verbs = metav1.Verbs{"delete", "get", "list"}
+	assert.Equal(t, []GroupResource{{APIGroup: "a", APIResource: foo}}, grs)
+	assert.Equal(t, metav1.Verbs{"list"}, aFoo.Verbs, "discovery result must not be modified")
+}
+
+func TestFetchAvailableGroupResources_allowCustomVerbs(t *testing.T) {
+	fakeClient := &fakeCachedDiscoveryInterface{
+		next: metav1.APIResourceList{
+			GroupVersion: "a/v1",
+			APIResources: []metav1.APIResource{aFoo},
+		},
+		fresh: true,
+	}
+	getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+		return fakeClient, nil
+	}
+	defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+
+	namespace := ""
+	opts := &options.RakkessOptions{
+		ConfigFlags:      &genericclioptions.ConfigFlags{Namespace: &namespace},
+		PreferredOnly:    true,
+		Verbs:            []string{"get", "list", "approve"},
+		AllowCustomVerbs: true,
+	}
+	grs, err := FetchAvailableGroupResources(opts)
+	assert.NoError(t, err)
+
+	foo := aFoo
+	foo.Verbs = metav1.Verbs{"approve", "list"}
+	assert.Equal(t, []GroupResource{{APIGroup: "a", APIResource: foo}}, grs)
+}
+
+func TestFetchAvailableGroupResources_noCache(t *testing.T) {
+	fakeClient := &fakeCachedDiscoveryInterface{
+		next: metav1.APIResourceList{
+			GroupVersion: "a/v1",
+			APIResources: []metav1.APIResource{aFoo},
+		},
+	}
+	getDiscoveryClient = func(opts *options.RakkessOptions) (discovery.CachedDiscoveryInterface, error) {
+		return fakeClient, nil
+	}
+	defer func() { getDiscoveryClient = getDiscoveryClientImpl }()
+
+	namespace := ""
+	opts := &options.RakkessOptions{
+		ConfigFlags:   &genericclioptions.ConfigFlags{Namespace: &namespace},
+		PreferredOnly: true,
+	}
+	_, err := FetchAvailableGroupResources(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, fakeClient.invalidateCalls, "the cache must be used")
+
+	opts.NoCache = true
+	grs, err := FetchAvailableGroupResources(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fakeClient.invalidateCalls)
+	assert.Equal(t, []GroupResource{{APIGroup: "a", APIResource: aFoo}}, grs)
+}
+
+func TestFilterResources(t *testing.T) {
+	pods := GroupResource{APIResource: metav1.APIResource{Name: "pods", ShortNames: []string{"po"}}}
+	podLogs := GroupResource{APIResource: metav1.APIResource{Name: "pods/log"}}
+	deployments := GroupResource{APIGroup: "apps", APIResource: metav1.APIResource{Name: "deployments", SingularName: "deployment", ShortNames: []string{"deploy"}}}
+	grs := []GroupResource{pods, podLogs, deployments}
+
+	tests := []struct {
+		name      string
+		resources []string
+		expected  []GroupResource
+		err       string
+	}{
+		{name: "by name", resources: []string{"pods"}, expected: []GroupResource{pods}},
+		{name: "subresource", resources: []string{"pods/log"}, expected: []GroupResource{podLogs}},
+		{name: "qualified with group", resources: []string{"deployments.apps"}, expected: []GroupResource{deployments}},
+		{name: "by short and singular name", resources: []string{"po", "deployment"}, expected: []GroupResource{pods, deployments}},
+		{name: "qualified short name", resources: []string{"deploy.apps"}, expected: []GroupResource{deployments}},
+		{name: "unknown", resources: []string{"pods", "widgets", "deployments.extensions"}, err: "resources not served by the cluster: deployments.extensions, widgets"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := filterResources(grs, test.resources)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
+func TestFilterResourcePatterns(t *testing.T) {
+	pods := GroupResource{APIResource: metav1.APIResource{Name: "pods"}}
+	podLogs := GroupResource{APIResource: metav1.APIResource{Name: "pods/log"}}
+	netpols := GroupResource{APIGroup: "networking.k8s.io", APIVersion: "v1", APIResource: metav1.APIResource{Name: "networkpolicies"}}
+	ingresses := GroupResource{APIGroup: "networking.k8s.io", APIVersion: "v1", APIResource: metav1.APIResource{Name: "ingresses"}}
+	grs := []GroupResource{pods, podLogs, netpols, ingresses}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []GroupResource
+		err      string
+	}{
+		{name: "by name", patterns: []string{"network*"}, expected: []GroupResource{netpols}},
+		{name: "qualified with group", patterns: []string{"*.networking.k8s.io"}, expected: []GroupResource{netpols, ingresses}},
+		{name: "subresources", patterns: []string{"pods/*"}, expected: []GroupResource{podLogs}},
+		{name: "several patterns", patterns: []string{"pods", "ingress?s"}, expected: []GroupResource{pods, ingresses}},
+		{name: "no match", patterns: []string{"pods", "netwrok*"}, err: "resource patterns match no resources: netwrok*"},
+		{name: "invalid", patterns: []string{"pods["}, err: `invalid resource pattern "pods[": syntax error in pattern`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := filterResourcePatterns(grs, test.patterns)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
+func TestSupportedVerbs(t *testing.T) {
+	grs := []GroupResource{
+		{APIResource: metav1.APIResource{Name: "pods", Verbs: []string{"list", "get", "create"}}},
+		{APIResource: metav1.APIResource{Name: "widgets", Verbs: []string{"get", "scale", "approve"}}},
+		{APIResource: metav1.APIResource{Name: "baz"}},
+	}
+	assert.Equal(t, []string{"create", "get", "list", "approve", "scale"}, SupportedVerbs(grs))
+	assert.Empty(t, SupportedVerbs(nil))
+}
+
 func TestGroupResource_fullName(t *testing.T) {
 	grNoGroup := &GroupResource{
 		APIGroup: "",
@@ -212,4 +543,48 @@ func TestGroupResource_fullName(t *testing.T) {
 		},
 	}
 	assert.Equal(t, "foo.v1", grGroup.fullName())
+
+	grVersion := &GroupResource{
+		APIGroup:   "apps",
+		APIVersion: "v1",
+		APIResource: metav1.APIResource{
+			Name: "foo",
+		},
+	}
+	assert.Equal(t, "foo.apps/v1", grVersion.fullName())
+
+	grCoreVersion := &GroupResource{
+		APIVersion: "v1",
+		APIResource: metav1.APIResource{
+			Name: "foo",
+		},
+	}
+	assert.Equal(t, "foo.v1", grCoreVersion.fullName())
+}
+
+func TestFilterNodeResources(t *testing.T) {
+	pods := GroupResource{APIResource: metav1.APIResource{Name: "pods"}}
+	podsStatus := GroupResource{APIResource: metav1.APIResource{Name: "pods/status"}}
+	leases := GroupResource{APIGroup: "coordination.k8s.io", APIVersion: "v1", APIResource: metav1.APIResource{Name: "leases"}}
+	deployments := GroupResource{APIGroup: "apps", APIResource: metav1.APIResource{Name: "deployments"}}
+	otherLeases := GroupResource{APIGroup: "example.com", APIResource: metav1.APIResource{Name: "leases"}}
+
+	actual := filterNodeResources([]GroupResource{pods, podsStatus, leases, deployments, otherLeases})
+	assert.Equal(t, []GroupResource{pods, podsStatus, leases}, actual)
+}
+
+func TestFilterSubresources(t *testing.T) {
+	pods := GroupResource{APIResource: metav1.APIResource{Name: "pods"}}
+	podLogs := GroupResource{APIResource: metav1.APIResource{Name: "pods/log"}}
+	podExec := GroupResource{APIResource: metav1.APIResource{Name: "pods/exec"}}
+	podStatus := GroupResource{APIResource: metav1.APIResource{Name: "pods/status"}}
+	scale := GroupResource{APIGroup: "apps", APIVersion: "v1", APIResource: metav1.APIResource{Name: "deployments/scale"}}
+	grs := []GroupResource{pods, podLogs, podExec, podStatus, scale}
+
+	got, err := filterSubresources(grs, []string{"log", "exec"})
+	assert.NoError(t, err)
+	assert.Equal(t, []GroupResource{pods, podLogs, podExec}, got)
+
+	_, err = filterSubresources(grs, []string{"log", "attach"})
+	assert.EqualError(t, err, "subresources not served by the cluster: attach")
 }
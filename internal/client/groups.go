@@ -0,0 +1,46 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// LoadGroupMembers reads group memberships from a YAML or JSON file, which
+// maps group names to lists of member names:
+//
+//	developers:
+//	- alice
+//	- system:serviceaccount:ci:deployer
+//
+// Kubernetes does not store group memberships, so they must come from the
+// identity provider.
+func LoadGroupMembers(path string) (result.GroupMembers, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read group members")
+	}
+	var members result.GroupMembers
+	if err := yaml.UnmarshalStrict(data, &members); err != nil {
+		return nil, errors.Wrapf(err, "parse group members from %s", path)
+	}
+	return members, nil
+}
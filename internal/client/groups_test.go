@@ -0,0 +1,67 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGroupMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected result.GroupMembers
+		err      bool
+	}{
+		{
+			name:     "yaml",
+			content:  "developers:\n- alice\n- bob\nops: [carol]\n",
+			expected: result.GroupMembers{"developers": {"alice", "bob"}, "ops": {"carol"}},
+		},
+		{
+			name:     "json",
+			content:  `{"developers": ["alice"]}`,
+			expected: result.GroupMembers{"developers": {"alice"}},
+		},
+		{
+			name:    "invalid",
+			content: "developers: alice\n",
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "groups.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(test.content), 0o600))
+
+			actual, err := LoadGroupMembers(path)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
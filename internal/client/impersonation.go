@@ -0,0 +1,48 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	authzv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// CheckImpersonation sends a single access review to find out whether the
+// current user may impersonate the configured identity. Without this check, a
+// forbidden impersonation would only show up as an error in every cell of the
+// access matrix.
+func CheckImpersonation(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, identity string) error {
+	req := authzv1.SelfSubjectAccessReview{
+		Spec: authzv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Verb:     "get",
+				Resource: "namespaces",
+			},
+		},
+	}
+	countAccessReview()
+	_, err := sar.Create(ctx, &req, metav1.CreateOptions{})
+	if apierrors.IsForbidden(err) {
+		return fmt.Errorf("impersonating %s is forbidden, the current user needs the impersonate verb on users, groups, and uids: %v", identity, err)
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestCheckImpersonation(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, action.(authTesting.CreateAction).GetObject(), nil
+		})
+	require.NoError(t, CheckImpersonation(context.Background(), fakeReviews, "alice"))
+
+	fakeReviews = &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "users"}, "alice", nil)
+		})
+	err := CheckImpersonation(context.Background(), fakeReviews, "alice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "impersonating alice is forbidden")
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/klog/v2"
+)
+
+// GetWildcardFindings reports all wildcard rules which are bound to non-system
+// subjects. Roles and RoleBindings are only considered, if a namespace is given.
+func GetWildcardFindings(ctx context.Context, opts *options.RakkessOptions) (result.WildcardFindings, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := listOptions(opts)
+
+	// wildcards holds the fields with wildcards of the broadest rule per role
+	wildcards := make(map[result.RoleRef][]string)
+
+	klog.V(2).Infof("fetching clusterRoles")
+	countList()
+	clusterRoles, err := rbacClient.ClusterRoles().List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range clusterRoles.Items {
+		recordWildcards(wildcards, result.RoleRef{Name: role.Name, Kind: clusterRoleName}, role.Rules)
+	}
+
+	var findings result.WildcardFindings
+
+	klog.V(2).Infof("fetching ClusterRoleBindings")
+	countList()
+	clusterRoleBindings, err := rbacClient.ClusterRoleBindings().List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, crb := range clusterRoleBindings.Items {
+		b := result.BindingRef{Name: crb.Name, Kind: clusterRoleBindingName}
+		findings = appendFindings(findings, wildcards, crb.RoleRef, b, crb.Subjects)
+	}
+
+	namespace := opts.ConfigFlags.Namespace
+	if namespace == nil || *namespace == "" {
+		klog.V(2).Infof("Skipping roles and rolebindings because namespace is missing")
+		findings.Sort()
+		return findings, nil
+	}
+
+	klog.V(2).Infof("fetching roles for namespace %s", *namespace)
+	countList()
+	roles, err := rbacClient.Roles(*namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles.Items {
+		recordWildcards(wildcards, result.RoleRef{Name: role.Name, Kind: roleName}, role.Rules)
+	}
+
+	klog.V(2).Infof("fetching RoleBindings for namespace %s", *namespace)
+	countList()
+	roleBindings, err := rbacClient.RoleBindings(*namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range roleBindings.Items {
+		b := result.BindingRef{Name: rb.Name, Kind: roleBindingName, Namespace: *namespace}
+		findings = appendFindings(findings, wildcards, rb.RoleRef, b, rb.Subjects)
+	}
+
+	findings.Sort()
+	return findings, nil
+}
+
+func recordWildcards(wildcards map[result.RoleRef][]string, r result.RoleRef, rules []v1.PolicyRule) {
+	for _, rule := range rules {
+		if w := result.RuleWildcards(rule); len(w) > len(wildcards[r]) {
+			wildcards[r] = w
+		}
+	}
+}
+
+func appendFindings(findings result.WildcardFindings, wildcards map[result.RoleRef][]string, roleRef v1.RoleRef, b result.BindingRef, subjects []v1.Subject) result.WildcardFindings {
+	r := result.RoleRef{Name: roleRef.Name, Kind: roleRef.Kind}
+	w, ok := wildcards[r]
+	if !ok {
+		return findings
+	}
+	for _, subject := range subjects {
+		s := result.SubjectRef{Name: subject.Name, Kind: subject.Kind, Namespace: subject.Namespace}
+		if result.IsSystemSubject(s) {
+			continue
+		}
+		findings = append(findings, result.WildcardFinding{Role: r, Binding: b, Subject: s, Wildcards: w})
+	}
+	return findings
+}
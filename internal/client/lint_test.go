@@ -0,0 +1,143 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetWildcardFindings(t *testing.T) {
+	allVerbs := v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"*"}}
+	everything := v1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}
+	specific := v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}
+
+	clusterRoles := []v1.ClusterRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "admin-ish"}, Rules: []v1.PolicyRule{specific, everything}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-manager"}, Rules: []v1.PolicyRule{allVerbs}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "reader"}, Rules: []v1.PolicyRule{specific}},
+	}
+	clusterRoleBindings := []v1.ClusterRoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+			RoleRef:    v1.RoleRef{Name: "admin-ish", Kind: clusterRoleName},
+			Subjects:   []v1.Subject{{Kind: "Group", Name: "system:masters"}, {Kind: "User", Name: "alice"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "readers"},
+			RoleRef:    v1.RoleRef{Name: "reader", Kind: clusterRoleName},
+			Subjects:   []v1.Subject{{Kind: "User", Name: "bob"}},
+		},
+	}
+	roles := []v1.Role{
+		{ObjectMeta: metav1.ObjectMeta{Name: "local-admin", Namespace: roleNamespace}, Rules: []v1.PolicyRule{everything}},
+	}
+	roleBindings := []v1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pods", Namespace: roleNamespace},
+			RoleRef:    v1.RoleRef{Name: "pod-manager", Kind: clusterRoleName},
+			Subjects:   []v1.Subject{{Kind: "ServiceAccount", Name: "ci", Namespace: roleNamespace}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: roleNamespace},
+			RoleRef:    v1.RoleRef{Name: "local-admin", Kind: roleName},
+			Subjects:   []v1.Subject{{Kind: "User", Name: "carol"}},
+		},
+	}
+
+	alice := result.WildcardFinding{
+		Role:      result.RoleRef{Name: "admin-ish", Kind: clusterRoleName},
+		Binding:   result.BindingRef{Name: "admins", Kind: clusterRoleBindingName},
+		Subject:   result.SubjectRef{Name: "alice", Kind: "User"},
+		Wildcards: []string{"verbs", "resources", "apiGroups"},
+	}
+	carol := result.WildcardFinding{
+		Role:      result.RoleRef{Name: "local-admin", Kind: roleName},
+		Binding:   result.BindingRef{Name: "local", Kind: roleBindingName, Namespace: roleNamespace},
+		Subject:   result.SubjectRef{Name: "carol", Kind: "User"},
+		Wildcards: []string{"verbs", "resources", "apiGroups"},
+	}
+	ci := result.WildcardFinding{
+		Role:      result.RoleRef{Name: "pod-manager", Kind: clusterRoleName},
+		Binding:   result.BindingRef{Name: "pods", Kind: roleBindingName, Namespace: roleNamespace},
+		Subject:   result.SubjectRef{Name: "ci", Kind: "ServiceAccount", Namespace: roleNamespace},
+		Wildcards: []string{"verbs"},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		expected  result.WildcardFindings
+	}{
+		{
+			name:     "cluster-wide",
+			expected: result.WildcardFindings{alice},
+		},
+		{
+			name:      "with namespace",
+			namespace: roleNamespace,
+			expected:  result.WildcardFindings{alice, carol, ci},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+			fakeRbacClient.Fake.AddReactor("list", "roles",
+				func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &v1.RoleList{Items: roles}, nil
+				})
+			fakeRbacClient.Fake.AddReactor("list", "rolebindings",
+				func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &v1.RoleBindingList{Items: roleBindings}, nil
+				})
+			fakeRbacClient.Fake.AddReactor("list", "clusterroles",
+				func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &v1.ClusterRoleList{Items: clusterRoles}, nil
+				})
+			fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+				func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &v1.ClusterRoleBindingList{Items: clusterRoleBindings}, nil
+				})
+
+			getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+				return fakeRbacClient, nil
+			}
+			defer func() { getRbacClient = getRbacClientImpl }()
+
+			opts := &options.RakkessOptions{
+				ConfigFlags: &genericclioptions.ConfigFlags{
+					Namespace: &test.namespace,
+				},
+			}
+			findings, err := GetWildcardFindings(context.Background(), opts)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, findings)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+)
+
+// FetchRBAC lists all (Cluster)Roles and their bindings in all namespaces.
+func FetchRBAC(ctx context.Context, opts *options.RakkessOptions) (*result.RBAC, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := listOptions(opts)
+	ret := &result.RBAC{}
+
+	countList()
+	clusterRoles, err := rbacClient.ClusterRoles().List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list ClusterRoles")
+	}
+	ret.ClusterRoles = clusterRoles.Items
+
+	countList()
+	clusterRoleBindings, err := rbacClient.ClusterRoleBindings().List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list ClusterRoleBindings")
+	}
+	ret.ClusterRoleBindings = clusterRoleBindings.Items
+
+	countList()
+	roles, err := rbacClient.Roles(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list Roles")
+	}
+	ret.Roles = roles.Items
+
+	countList()
+	roleBindings, err := rbacClient.RoleBindings(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list RoleBindings")
+	}
+	ret.RoleBindings = roleBindings.Items
+
+	return ret, nil
+}
+
+// LoadRBACManifests reads all RBAC objects from the YAML or JSON files in the
+// given directory. Files may contain several documents, and objects of other
+// kinds are skipped. Namespaced objects without namespace are put into
+// defaultNamespace.
+func LoadRBACManifests(dir, defaultNamespace string) (*result.RBAC, error) {
+	ret := &result.RBAC{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return errors.Wrapf(decodeRBAC(data, defaultNamespace, ret), "decode %s", path)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "load manifests")
+	}
+	return ret, nil
+}
+
+func decodeRBAC(data []byte, defaultNamespace string, into *result.RBAC) error {
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	decoder := scheme.Codecs.UniversalDeserializer()
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, gvk, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			klog.Warningf("Skipping manifest which is not a known kubernetes object: %s", err)
+			continue
+		}
+		switch o := obj.(type) {
+		case *v1.ClusterRole:
+			into.ClusterRoles = append(into.ClusterRoles, *o)
+		case *v1.ClusterRoleBinding:
+			into.ClusterRoleBindings = append(into.ClusterRoleBindings, *o)
+		case *v1.Role:
+			if o.Namespace == "" {
+				o.Namespace = defaultNamespace
+			}
+			into.Roles = append(into.Roles, *o)
+		case *v1.RoleBinding:
+			if o.Namespace == "" {
+				o.Namespace = defaultNamespace
+			}
+			into.RoleBindings = append(into.RoleBindings, *o)
+		default:
+			klog.V(2).Infof("Skipping %s, because it is no rbac.authorization.k8s.io/v1 object", gvk)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const proposedManifests = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: deployer
+rules:
+- apiGroups: ["apps"]
+  resources: ["deployments"]
+  verbs: ["update"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: deployer
+  namespace: prod
+roleRef:
+  kind: Role
+  name: deployer
+subjects:
+- kind: ServiceAccount
+  name: ci
+  namespace: prod
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+`
+
+func TestLoadRBACManifests(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "rbac.yaml"), []byte(proposedManifests), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# not a manifest"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "clusterrole.json"), []byte(`{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRole", "metadata": {"name": "viewer"}}`), 0o600))
+
+	rbac, err := LoadRBACManifests(dir, "staging")
+	require.NoError(t, err)
+
+	require.Len(t, rbac.ClusterRoles, 1)
+	assert.Equal(t, "viewer", rbac.ClusterRoles[0].Name)
+	require.Len(t, rbac.Roles, 1)
+	assert.Equal(t, "staging", rbac.Roles[0].Namespace)
+	assert.Equal(t, []string{"update"}, rbac.Roles[0].Rules[0].Verbs)
+	require.Len(t, rbac.RoleBindings, 1)
+	assert.Equal(t, "prod", rbac.RoleBindings[0].Namespace)
+	assert.Empty(t, rbac.ClusterRoleBindings)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/options"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// for testing
+	getNamespacesClient = getNamespacesClientImpl
+)
+
+// ListNamespaces returns the names of all namespaces.
+func ListNamespaces(ctx context.Context, opts *options.RakkessOptions) ([]string, error) {
+	nsClient, err := getNamespacesClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(2).Infof("fetching namespaces")
+	countList()
+	namespaces, err := nsClient.Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+// AccessibleNamespaces returns the namespaces which the caller may get. A
+// RoleBinding of a role such as view or edit in a namespace also grants get on
+// that namespace, so this selects the namespaces in which the caller operates.
+func AccessibleNamespaces(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, namespaces []string) ([]string, error) {
+	var accessible []string
+	for _, ns := range namespaces {
+		req := authzv1.SelfSubjectAccessReview{
+			Spec: authzv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authzv1.ResourceAttributes{
+					Verb:      "get",
+					Resource:  "namespaces",
+					Name:      ns,
+					Namespace: ns,
+				},
+			},
+		}
+		countAccessReview()
+		resp, err := sar.Create(ctx, &req, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if resp.Status.Allowed {
+			accessible = append(accessible, ns)
+		}
+	}
+	return accessible, nil
+}
+
+// NamespacedOnly returns the namespaced resources.
+func NamespacedOnly(grs []GroupResource) []GroupResource {
+	var namespaced []GroupResource
+	for _, gr := range grs {
+		if gr.APIResource.Namespaced {
+			namespaced = append(namespaced, gr)
+		}
+	}
+	return namespaced
+}
+
+func getNamespacesClientImpl(o *options.RakkessOptions) (corev1.NamespacesGetter, error) {
+	restConfig, err := o.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return corev1.NewForConfigOrDie(restConfig), nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestAccessibleNamespaces(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			attrs := sar.Spec.ResourceAttributes
+			switch {
+			case attrs.Name == "broken":
+				return true, nil, fmt.Errorf("server unavailable")
+			case attrs.Verb == "get" && attrs.Resource == "namespaces" && attrs.Name == attrs.Namespace:
+				sar.Status.Allowed = attrs.Name == "team-a" || attrs.Name == "team-b"
+			}
+			return true, sar, nil
+		})
+
+	got, err := AccessibleNamespaces(context.Background(), fakeReviews, []string{"default", "team-a", "kube-system", "team-b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, got)
+
+	_, err = AccessibleNamespaces(context.Background(), fakeReviews, []string{"team-a", "broken"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// for testing
+	getServiceAccountsClient = getServiceAccountsClientImpl
+)
+
+// GetOrphanSubjects reports all ServiceAccount subjects of bindings, where the
+// ServiceAccount does not exist. RoleBindings are considered in the given
+// namespace, or in all namespaces if no namespace is given.
+func GetOrphanSubjects(ctx context.Context, opts *options.RakkessOptions) (result.OrphanSubjects, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	saClient, err := getServiceAccountsClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := listOptions(opts)
+
+	klog.V(2).Infof("fetching ServiceAccounts in all namespaces")
+	countList()
+	serviceAccounts, err := saClient.ServiceAccounts(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[result.SubjectRef]bool, len(serviceAccounts.Items))
+	for _, sa := range serviceAccounts.Items {
+		existing[result.SubjectRef{Name: sa.Name, Kind: v1.ServiceAccountKind, Namespace: sa.Namespace}] = true
+	}
+
+	var orphans result.OrphanSubjects
+	appendOrphans := func(b result.BindingRef, subjects []v1.Subject) {
+		for _, subject := range subjects {
+			if subject.Kind != v1.ServiceAccountKind {
+				continue
+			}
+			s := result.SubjectRef{Name: subject.Name, Kind: subject.Kind, Namespace: subject.Namespace}
+			if s.Namespace == "" {
+				s.Namespace = b.Namespace
+			}
+			if !existing[s] {
+				orphans = append(orphans, result.OrphanSubject{Binding: b, Subject: s})
+			}
+		}
+	}
+
+	klog.V(2).Infof("fetching ClusterRoleBindings")
+	countList()
+	clusterRoleBindings, err := rbacClient.ClusterRoleBindings().List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, crb := range clusterRoleBindings.Items {
+		appendOrphans(result.BindingRef{Name: crb.Name, Kind: clusterRoleBindingName}, crb.Subjects)
+	}
+
+	namespace := metav1.NamespaceAll
+	if opts.ConfigFlags.Namespace != nil {
+		namespace = *opts.ConfigFlags.Namespace
+	}
+	klog.V(2).Infof("fetching RoleBindings for namespace %q", namespace)
+	countList()
+	roleBindings, err := rbacClient.RoleBindings(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range roleBindings.Items {
+		appendOrphans(result.BindingRef{Name: rb.Name, Kind: roleBindingName, Namespace: rb.Namespace}, rb.Subjects)
+	}
+
+	orphans.Sort()
+	return orphans, nil
+}
+
+func getServiceAccountsClientImpl(o *options.RakkessOptions) (corev1.ServiceAccountsGetter, error) {
+	restConfig, err := o.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return corev1.NewForConfigOrDie(restConfig), nil
+}
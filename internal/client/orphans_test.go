@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corefake "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetOrphanSubjects(t *testing.T) {
+	fakeCoreClient := &corefake.FakeCoreV1{Fake: &k8stesting.Fake{}}
+	fakeCoreClient.Fake.AddReactor("list", "serviceaccounts",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &corev1.ServiceAccountList{Items: []corev1.ServiceAccount{
+				{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "build"}},
+			}}, nil
+		})
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleBindingList{Items: []v1.ClusterRoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "deployers"},
+					Subjects: []v1.Subject{
+						{Kind: "ServiceAccount", Name: "ci", Namespace: "build"},
+						{Kind: "ServiceAccount", Name: "old-ci", Namespace: "build"},
+						{Kind: "User", Name: "alice"},
+					},
+				},
+			}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "rolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			assert.Equal(t, metav1.NamespaceAll, action.GetNamespace())
+			return true, &v1.RoleBindingList{Items: []v1.RoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "prod"},
+					Subjects:   []v1.Subject{{Kind: "ServiceAccount", Name: "ci"}},
+				},
+			}}, nil
+		})
+
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	getServiceAccountsClient = func(*options.RakkessOptions) (typedcorev1.ServiceAccountsGetter, error) {
+		return fakeCoreClient, nil
+	}
+	defer func() {
+		getRbacClient = getRbacClientImpl
+		getServiceAccountsClient = getServiceAccountsClientImpl
+	}()
+
+	opts := &options.RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{}}
+	orphans, err := GetOrphanSubjects(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, result.OrphanSubjects{
+		{
+			Binding: result.BindingRef{Name: "deployers", Kind: "ClusterRoleBinding"},
+			Subject: result.SubjectRef{Name: "old-ci", Kind: "ServiceAccount", Namespace: "build"},
+		},
+		{
+			Binding: result.BindingRef{Name: "ci", Kind: "RoleBinding", Namespace: "prod"},
+			Subject: result.SubjectRef{Name: "ci", Kind: "ServiceAccount", Namespace: "prod"},
+		},
+	}, orphans)
+	assert.Equal(t, []string{"RoleBinding/prod/ci", "prod/ci"}, orphans.Table().Rows[1].Intro)
+}
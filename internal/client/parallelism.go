@@ -0,0 +1,113 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	v1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/klog/v2"
+)
+
+// A round is throttled if its latency is more than throttleFactor times the
+// latency of a single request, and at least minThrottleDelay slower.
+const (
+	throttleFactor   = 2
+	minThrottleDelay = 20 * time.Millisecond
+)
+
+// calibrationRound is the outcome of sending parallelism requests at once.
+type calibrationRound struct {
+	parallelism int
+	latency     time.Duration
+	failed      bool
+}
+
+// CalibrateParallelism sends a few rounds of access reviews at doubling
+// concurrency, until the API server starts throttling or constants.MaxAutoParallelism is
+// reached. It returns the highest concurrency below the throttling point.
+func CalibrateParallelism(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface) int {
+	var rounds []calibrationRound
+	for parallelism := 1; parallelism <= constants.MaxAutoParallelism; parallelism *= 2 {
+		round := calibrate(ctx, sar, parallelism)
+		klog.V(2).Infof("Calibration with parallelism %d took %s per review (failed: %t)", parallelism, round.latency, round.failed)
+		rounds = append(rounds, round)
+		if throttled(rounds[0], round) {
+			break
+		}
+	}
+	chosen := chooseParallelism(rounds)
+	recordParallelism(chosen)
+	return chosen
+}
+
+// calibrate sends parallelism access reviews at once and measures their
+// average latency.
+func calibrate(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, parallelism int) calibrationRound {
+	round := calibrationRound{parallelism: parallelism}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var total time.Duration
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := v1.SelfSubjectAccessReview{
+				Spec: v1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &v1.ResourceAttributes{Verb: "get", Resource: "namespaces"},
+				},
+			}
+			start := now()
+			countAccessReview()
+			_, err := sar.Create(ctx, &req, metav1.CreateOptions{})
+			elapsed := now().Sub(start)
+
+			mu.Lock()
+			defer mu.Unlock()
+			total += elapsed
+			round.failed = round.failed || err != nil
+		}()
+	}
+	wg.Wait()
+	round.latency = total / time.Duration(parallelism)
+	return round
+}
+
+func throttled(baseline, round calibrationRound) bool {
+	if round.failed {
+		return true
+	}
+	return round.latency > throttleFactor*baseline.latency && round.latency-baseline.latency >= minThrottleDelay
+}
+
+// chooseParallelism returns the parallelism of the last round before the
+// first throttled round, and at least 1.
+func chooseParallelism(rounds []calibrationRound) int {
+	chosen := 1
+	for _, round := range rounds {
+		if throttled(rounds[0], round) {
+			break
+		}
+		chosen = round.parallelism
+	}
+	return chosen
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestChooseParallelism(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		rounds   []calibrationRound
+		expected int
+	}{
+		{
+			name:     "no throttling",
+			rounds:   []calibrationRound{{1, 10 * ms, false}, {2, 10 * ms, false}, {4, 12 * ms, false}},
+			expected: 4,
+		},
+		{
+			name:     "throttled by latency",
+			rounds:   []calibrationRound{{1, 10 * ms, false}, {2, 11 * ms, false}, {4, 15 * ms, false}, {8, 60 * ms, false}},
+			expected: 4,
+		},
+		{
+			name:     "slow but below the minimal delay",
+			rounds:   []calibrationRound{{1, ms, false}, {2, 5 * ms, false}},
+			expected: 2,
+		},
+		{
+			name:     "failed requests",
+			rounds:   []calibrationRound{{1, 10 * ms, false}, {2, 10 * ms, true}},
+			expected: 1,
+		},
+		{
+			name:     "failure on first round",
+			rounds:   []calibrationRound{{1, 10 * ms, true}},
+			expected: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, chooseParallelism(test.rounds))
+		})
+	}
+}
+
+func TestCalibrateParallelism(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			sar.Status.Allowed = true
+			return true, sar, nil
+		})
+
+	before := Stats().AccessReviews
+	assert.Equal(t, constants.MaxAutoParallelism, CalibrateParallelism(context.Background(), fakeReviews))
+	assert.Equal(t, int64(2*constants.MaxAutoParallelism-1), Stats().AccessReviews-before)
+	assert.Equal(t, int64(constants.MaxAutoParallelism), Stats().Parallelism)
+}
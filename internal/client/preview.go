@@ -0,0 +1,90 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// PreviewBindings determines the access which a subject would have, if it
+// were bound to the given roles and to nothing else. The rules of the roles
+// are evaluated directly, so nothing is created in the cluster. With a
+// namespace, the roles are bound by RoleBindings in that namespace, which
+// cannot grant access to cluster-scoped resources. Otherwise, the roles are
+// bound by ClusterRoleBindings.
+func PreviewBindings(ctx context.Context, opts *options.RakkessOptions, refs []result.RoleRef, grs []GroupResource, verbs []string, namespace string) (result.ResourceAccess, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []v1.PolicyRule
+	for _, ref := range refs {
+		switch ref.Kind {
+		case clusterRoleName:
+			role, err := rbacClient.ClusterRoles().Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "get ClusterRole %s", ref.Name)
+			}
+			rules = append(rules, role.Rules...)
+		case roleName:
+			if namespace == "" {
+				return nil, fmt.Errorf("cannot bind Role %s without namespace", ref.Name)
+			}
+			role, err := rbacClient.Roles(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "get Role %s", ref.Name)
+			}
+			rules = append(rules, role.Rules...)
+		}
+	}
+
+	preview := result.RoleRef{Name: "preview"}
+	res := result.NewResultAccumulator()
+	for _, gr := range grs {
+		sa := result.NewSubjectAccess(schema.GroupResource{Group: gr.APIGroup, Resource: gr.APIResource.Name}, "")
+		if namespace == "" || gr.APIResource.Namespaced {
+			for _, rule := range rules {
+				sa.MatchRules(preview, rule)
+			}
+		}
+		granted := sa.RoleVerbs(preview)
+		allowedVerbs := sets.NewString(gr.APIResource.Verbs...)
+		access := make(map[string]result.Access)
+		for _, v := range verbs {
+			switch {
+			case !allowedVerbs.Has(v):
+				access[v] = result.NotApplicable
+			case granted.Has(v):
+				access[v] = result.Allowed
+			default:
+				access[v] = result.Denied
+			}
+		}
+		res.AddResource(gr.fullName(), access)
+	}
+	return res.Result(), nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPreviewBindings(t *testing.T) {
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("get", "clusterroles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "edit"},
+				Rules: []v1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets", "nodes"}, Verbs: []string{"get", "list"}},
+				},
+			}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("get", "roles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			assert.Equal(t, "team-a", action.GetNamespace())
+			return true, &v1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "team-a"},
+				Rules: []v1.PolicyRule{
+					{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"delete"}},
+				},
+			}, nil
+		})
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	defer func() { getRbacClient = getRbacClientImpl }()
+
+	secrets := GroupResource{APIResource: metav1.APIResource{Name: "secrets", Namespaced: true, Verbs: []string{"get", "list", "delete"}}}
+	nodes := GroupResource{APIResource: metav1.APIResource{Name: "nodes", Verbs: []string{"get", "list", "delete"}}}
+	grs := []GroupResource{secrets, nodes}
+	refs := []result.RoleRef{{Name: "edit", Kind: "ClusterRole"}, {Name: "deployer", Kind: "Role"}}
+	verbs := []string{"get", "delete", "patch"}
+
+	ra, err := PreviewBindings(context.Background(), &options.RakkessOptions{}, refs, grs, verbs, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, result.ResourceAccess{
+		"secrets": {"get": result.Allowed, "delete": result.Allowed, "patch": result.NotApplicable},
+		// RoleBindings cannot grant access to cluster-scoped resources
+		"nodes": {"get": result.Denied, "delete": result.Denied, "patch": result.NotApplicable},
+	}, ra)
+
+	ra, err = PreviewBindings(context.Background(), &options.RakkessOptions{}, refs[:1], grs, verbs, "")
+	require.NoError(t, err)
+	assert.Equal(t, result.Allowed, ra["nodes"]["get"])
+
+	_, err = PreviewBindings(context.Background(), &options.RakkessOptions{}, refs, grs, verbs, "")
+	assert.EqualError(t, err, "cannot bind Role deployer without namespace")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// progress reports how many resources are checked completely, by rewriting a
+// single terminal line. A nil progress reports nothing.
+type progress struct {
+	out     io.Writer
+	mu      sync.Mutex
+	pending map[string]int
+	done    int
+}
+
+// newProgress tracks the given reviews. It returns nil if out is nil.
+func newProgress(out io.Writer, reviews []accessReview) *progress {
+	if out == nil {
+		return nil
+	}
+	pending := make(map[string]int)
+	for _, r := range reviews {
+		pending[r.name]++
+	}
+	return &progress{out: out, pending: pending}
+}
+
+// reviewed records a finished review of the given resource.
+func (p *progress) reviewed(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending[name]--; p.pending[name] == 0 {
+		p.done++
+		fmt.Fprintf(p.out, "\rchecked %d of %d resources", p.done, len(p.pending))
+	}
+}
+
+// finish clears the progress line, so that it does not mix with later output.
+func (p *progress) finish() {
+	if p == nil || p.done == 0 {
+		return
+	}
+	fmt.Fprint(p.out, "\r\033[K")
+}
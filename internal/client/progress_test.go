@@ -0,0 +1,47 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgress(&out, []accessReview{
+		{name: "pods", verb: "get"},
+		{name: "pods", verb: "list"},
+		{name: "secrets", verb: "get"},
+	})
+
+	p.reviewed("pods")
+	assert.Empty(t, out.String())
+	p.reviewed("secrets")
+	p.reviewed("pods")
+	p.finish()
+	assert.Equal(t, "\rchecked 1 of 2 resources\rchecked 2 of 2 resources\r\033[K", out.String())
+}
+
+func TestProgress_nil(t *testing.T) {
+	p := newProgress(nil, []accessReview{{name: "pods", verb: "get"}})
+	assert.Nil(t, p)
+	p.reviewed("pods")
+	p.finish()
+}
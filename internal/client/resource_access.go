@@ -18,10 +18,16 @@ package client
 
 import (
 	"context"
+	"io"
+	"net"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
@@ -30,71 +36,190 @@ import (
 
 // CheckResourceAccess determines the access rights for the given GroupResources and verbs.
 // Since it needs to do a lot of requests, the SelfSubjectAccessReviewInterface needs to
-// be configured for high queries per second.
-func CheckResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, grs []GroupResource, verbs []string, namespace *string) result.ResourceAccess {
-	var mu sync.Mutex // guards res
-	res := make(result.ResourceAccess)
+// be configured for high queries per second. The reviews are sent by a pool of
+// maxConcurrency workers, zero starts one worker per review. Reviews which fail
+// with a transient error are retried up to maxRetries times. When the context
+// is cancelled, no further reviews are sent and the context error is returned.
+// The number of checked resources is reported to progressOut, unless it is nil.
+func CheckResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, grs []GroupResource, verbs []string, namespace *string, resourceName string, maxConcurrency, maxRetries int, progressOut io.Writer) (result.ResourceAccess, error) {
+	res := result.NewResultAccumulator()
 
 	var ns string
 	if namespace != nil {
 		ns = *namespace
 	}
 
-	var wg sync.WaitGroup
+	var reviews []accessReview
 	for _, gr := range grs {
-		wg.Add(1)
-		// copy captured variables
+		klog.V(2).Infof("Checking access for %s", gr.fullName())
+
+		// This seems to be a bug in kubernetes. If namespace is set for non-namespaced
+		// resources, the access is reported as "allowed", but in fact it is forbidden.
 		namespace := ns
-		gr := gr
-		go func() {
-			defer wg.Done()
+		if !gr.APIResource.Namespaced {
+			namespace = ""
+		}
+
+		allowedVerbs := sets.NewString(gr.APIResource.Verbs...)
+		for _, v := range verbs {
+			if !allowedVerbs.Has(v) {
+				res.Add(gr.fullName(), v, result.NotApplicable)
+				continue
+			}
+			reviews = append(reviews, accessReview{
+				name: gr.fullName(),
+				verb: v,
+				spec: v1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: gr.resourceAttributes(v, namespace, resourceName),
+				},
+			})
+		}
+		// make sure that resources without any applicable verb still show up
+		res.AddResource(gr.fullName(), nil)
+	}
 
-			klog.V(2).Infof("Checking access for %s", gr.fullName())
+	if err := sendAccessReviews(ctx, sar, reviews, res, maxConcurrency, maxRetries, newProgress(progressOut, reviews)); err != nil {
+		return nil, err
+	}
+	return res.Result(), nil
+}
 
-			// This seems to be a bug in kubernetes. If namespace is set for non-namespaced
-			// resources, the access is reported as "allowed", but in fact it is forbidden.
-			if !gr.APIResource.Namespaced {
-				namespace = ""
-			}
+// CheckNonResourceAccess determines the access rights for the given
+// non-resource URL paths and verbs. The result is keyed by the path. Reviews
+// are sent as in CheckResourceAccess.
+func CheckNonResourceAccess(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, paths []string, verbs []string, maxConcurrency, maxRetries int) (result.ResourceAccess, error) {
+	res := result.NewResultAccumulator()
+
+	var reviews []accessReview
+	for _, path := range paths {
+		for _, v := range verbs {
+			reviews = append(reviews, accessReview{
+				name: path,
+				verb: v,
+				spec: v1.SelfSubjectAccessReviewSpec{
+					NonResourceAttributes: &v1.NonResourceAttributes{Path: path, Verb: v},
+				},
+			})
+		}
+	}
 
-			allowedVerbs := sets.NewString(gr.APIResource.Verbs...)
+	if err := sendAccessReviews(ctx, sar, reviews, res, maxConcurrency, maxRetries, nil); err != nil {
+		return nil, err
+	}
+	return res.Result(), nil
+}
 
-			access := make(map[string]result.Access)
-			for _, v := range verbs {
-				if !allowedVerbs.Has(v) {
-					access[v] = result.NotApplicable
-					continue
-				}
+var (
+	// retryBackoff is the delay before the first retry of an access review.
+	retryBackoff = 500 * time.Millisecond
+	// maxRetryBackoff caps the delay between retries.
+	maxRetryBackoff = 30 * time.Second
+)
 
-				req := v1.SelfSubjectAccessReview{
-					Spec: v1.SelfSubjectAccessReviewSpec{
-						ResourceAttributes: &v1.ResourceAttributes{
-							Verb:      v,
-							Resource:  gr.APIResource.Name,
-							Group:     gr.APIGroup,
-							Namespace: namespace,
-						},
-					},
-				}
+// retryDelay is the delay before the given retry, which starts at
+// retryBackoff and doubles up to maxRetryBackoff.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBackoff
+	for i := 0; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return delay
+}
+
+// accessReview is a pending access review of a single verb, whose result is
+// recorded under name.
+type accessReview struct {
+	name string
+	verb string
+	spec v1.SelfSubjectAccessReviewSpec
+}
+
+// sendAccessReviews sends the reviews from a pool of maxConcurrency workers,
+// zero starts one worker per review. When the context is cancelled, no further
+// reviews are sent and the context error is returned. Finished reviews are
+// recorded in the given progress.
+func sendAccessReviews(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, reviews []accessReview, res *result.ResultAccumulator, maxConcurrency, maxRetries int, p *progress) error {
+	if maxConcurrency <= 0 || maxConcurrency > len(reviews) {
+		maxConcurrency = len(reviews)
+	}
+	queue := make(chan accessReview)
 
-				var a result.Access
-				resp, err := sar.Create(ctx, &req, metav1.CreateOptions{})
-				switch {
-				case err != nil:
-					a = result.RequestErr
-				case resp.Status.Allowed:
-					a = result.Allowed
+	var wg sync.WaitGroup
+	for i := 0; i < maxConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range queue {
+				if ctx.Err() != nil {
+					continue
 				}
-				access[v] = a
+				res.Add(r.name, r.verb, r.send(ctx, sar, maxRetries))
+				p.reviewed(r.name)
 			}
-
-			mu.Lock()
-			res[gr.fullName()] = access
-			mu.Unlock()
 		}()
 	}
 
+dispatch:
+	for _, r := range reviews {
+		select {
+		case queue <- r:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
 	wg.Wait()
+	p.finish()
 
-	return res
+	return ctx.Err()
+}
+
+// send reviews the access and retries up to maxRetries times after transient
+// errors. The delay starts at retryBackoff and doubles with every retry up to
+// maxRetryBackoff, unless the API server asks for a different delay with
+// Retry-After.
+func (r accessReview) send(ctx context.Context, sar authv1.SelfSubjectAccessReviewInterface, maxRetries int) result.Access {
+	req := v1.SelfSubjectAccessReview{Spec: r.spec}
+	for attempt := 0; ; attempt++ {
+		countAccessReview()
+		resp, err := sar.Create(ctx, &req, metav1.CreateOptions{})
+		switch {
+		case err == nil && resp.Status.Allowed:
+			return result.Allowed
+		case err == nil:
+			return result.Denied
+		case attempt >= maxRetries || !isTransient(err) || ctx.Err() != nil:
+			klog.V(2).Infof("Access review of %s %s failed: %s", r.verb, r.name, err)
+			return result.RequestErr
+		}
+
+		delay := retryDelay(attempt)
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+		klog.V(2).Infof("Retrying access review of %s %s in %s: %s", r.verb, r.name, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return result.RequestErr
+		}
+	}
+}
+
+// isTransient tells whether the failed request may succeed when it is sent
+// again, such as after throttling (429), server errors (5xx), and timeouts.
+// Authentication and authorization errors are not transient.
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) {
+		return true
+	}
+	var status apierrors.APIStatus
+	return errors.As(err, &status) && status.Status().Code >= http.StatusInternalServerError
 }
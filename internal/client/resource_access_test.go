@@ -18,15 +18,22 @@ package client
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/corneliusweig/rakkess/internal/client/result"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apiV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
 	authTesting "k8s.io/client-go/testing"
 )
@@ -167,7 +174,8 @@ func TestCheckResourceAccess(t *testing.T) {
 					return false, nil, nil
 				})
 
-			results := CheckResourceAccess(ctx, fakeReviews, test.input, test.verbs, nil)
+			results, err := CheckResourceAccess(ctx, fakeReviews, test.input, test.verbs, nil, "", 2, 0, nil)
+			require.NoError(t, err)
 
 			var got []string
 			for name, access := range results {
@@ -192,3 +200,189 @@ func TestCheckResourceAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckResourceAccess_maxConcurrency(t *testing.T) {
+	var running, peak int32
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return true, action.(authTesting.CreateAction).GetObject(), nil
+		})
+
+	var grs []GroupResource
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		grs = append(grs, toGroupResource("group", name, "get", "list"))
+	}
+	results, err := CheckResourceAccess(context.Background(), fakeReviews, grs, []string{"get", "list"}, nil, "", 3, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, results, 6)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(3))
+}
+
+func TestCheckResourceAccess_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var reviews int32
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			atomic.AddInt32(&reviews, 1)
+			cancel()
+			return true, action.(authTesting.CreateAction).GetObject(), nil
+		})
+
+	var grs []GroupResource
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		grs = append(grs, toGroupResource("group", name, "get", "list"))
+	}
+	_, err := CheckResourceAccess(ctx, fakeReviews, grs, []string{"get", "list"}, nil, "", 1, 0, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.LessOrEqual(t, atomic.LoadInt32(&reviews), int32(2))
+}
+
+func TestCheckResourceAccess_subresource(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			attrs := sar.Spec.ResourceAttributes
+			sar.Status.Allowed = attrs.Resource == "pods" && attrs.Subresource == "exec"
+			return true, sar, nil
+		})
+
+	grs := []GroupResource{toGroupResource("", "pods/exec", "create"), toGroupResource("", "pods/log", "get")}
+	got, err := CheckResourceAccess(context.Background(), fakeReviews, grs, []string{"create", "get"}, nil, "", 1, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, result.ResourceAccess{
+		"pods/exec": {"create": result.Allowed, "get": result.NotApplicable},
+		"pods/log":  {"create": result.NotApplicable, "get": result.Denied},
+	}, got)
+}
+
+func TestCheckResourceAccess_resourceName(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			sar.Status.Allowed = sar.Spec.ResourceAttributes.Name == "my-secret"
+			return true, sar, nil
+		})
+
+	grs := []GroupResource{toGroupResource("", "secrets", "get")}
+	got, err := CheckResourceAccess(context.Background(), fakeReviews, grs, []string{"get"}, nil, "my-secret", 1, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, result.ResourceAccess{"secrets": {"get": result.Allowed}}, got)
+}
+
+func TestCheckResourceAccess_namespace(t *testing.T) {
+	reviewed := make(map[string]string)
+	var mu sync.Mutex
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			mu.Lock()
+			reviewed[sar.Spec.ResourceAttributes.Resource] = sar.Spec.ResourceAttributes.Namespace
+			mu.Unlock()
+			sar.Status.Allowed = true
+			return true, sar, nil
+		})
+
+	pods := toGroupResource("", "pods", "list")
+	pods.APIResource.Namespaced = true
+	nodes := toGroupResource("", "nodes", "list")
+
+	namespace := "foo"
+	_, err := CheckResourceAccess(context.Background(), fakeReviews, []GroupResource{pods, nodes}, []string{"list"}, &namespace, "", 1, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pods": "foo", "nodes": ""}, reviewed, "cluster-scoped resources must be reviewed without namespace")
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 500*time.Millisecond, retryDelay(0))
+	assert.Equal(t, time.Second, retryDelay(1))
+	assert.Equal(t, 16*time.Second, retryDelay(5))
+	assert.Equal(t, 30*time.Second, retryDelay(6), "the backoff is capped")
+	assert.Equal(t, 30*time.Second, retryDelay(100), "large attempts must not overflow")
+}
+
+func TestCheckResourceAccess_retries(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = 500 * time.Millisecond }()
+
+	attempts := make(map[string]int)
+	var mu sync.Mutex
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			resource := sar.Spec.ResourceAttributes.Resource
+			mu.Lock()
+			attempts[resource]++
+			n := attempts[resource]
+			mu.Unlock()
+
+			switch resource {
+			case "throttled":
+				if n <= 2 {
+					return true, nil, apierrors.NewTooManyRequests("slow down", 0)
+				}
+			case "forbidden":
+				return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "selfsubjectaccessreviews"}, "", errors.New("no"))
+			case "broken":
+				return true, nil, apierrors.NewInternalError(errors.New("etcd is down"))
+			}
+			sar.Status.Allowed = true
+			return true, sar, nil
+		})
+
+	grs := []GroupResource{
+		toGroupResource("", "throttled", "get"),
+		toGroupResource("", "forbidden", "get"),
+		toGroupResource("", "broken", "get"),
+	}
+	got, err := CheckResourceAccess(context.Background(), fakeReviews, grs, []string{"get"}, nil, "", 1, 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, result.ResourceAccess{
+		"throttled": {"get": result.Allowed},
+		"forbidden": {"get": result.RequestErr},
+		"broken":    {"get": result.RequestErr},
+	}, got)
+	assert.Equal(t, map[string]int{"throttled": 3, "forbidden": 1, "broken": 4}, attempts)
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, isTransient(apierrors.NewTooManyRequests("slow down", 1)))
+	assert.True(t, isTransient(apierrors.NewServerTimeout(schema.GroupResource{}, "create", 1)))
+	assert.True(t, isTransient(apierrors.NewServiceUnavailable("unavailable")))
+	assert.True(t, isTransient(apierrors.NewInternalError(errors.New("etcd is down"))))
+	assert.False(t, isTransient(apierrors.NewUnauthorized("who are you")))
+	assert.False(t, isTransient(apierrors.NewForbidden(schema.GroupResource{}, "", errors.New("no"))))
+	assert.False(t, isTransient(errors.New("connection refused")))
+}
+
+func TestCheckNonResourceAccess(t *testing.T) {
+	fakeReviews := &fake.FakeSelfSubjectAccessReviews{Fake: &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}}
+	fakeReviews.Fake.AddReactor("create", "selfsubjectaccessreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			sar := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectAccessReview)
+			attrs := sar.Spec.NonResourceAttributes
+			sar.Status.Allowed = attrs.Path == "/healthz" && attrs.Verb == "get"
+			return true, sar, nil
+		})
+
+	got, err := CheckNonResourceAccess(context.Background(), fakeReviews, []string{"/healthz", "/metrics"}, []string{"get", "post"}, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, result.ResourceAccess{
+		"/healthz": {"get": result.Allowed, "post": result.Denied},
+		"/metrics": {"get": result.Denied, "post": result.Denied},
+	}, got)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import "sync"
+
+// ResultAccumulator collects the outcomes of individual access reviews into a
+// ResourceAccess. It decouples the strategy of issuing reviews from the
+// collection of their results.
+//
+// All methods are safe for concurrent use by multiple goroutines. A zero
+// ResultAccumulator is ready to use.
+type ResultAccumulator struct {
+	mu  sync.Mutex // guards res
+	res ResourceAccess
+}
+
+// NewResultAccumulator creates an empty ResultAccumulator.
+func NewResultAccumulator() *ResultAccumulator {
+	return &ResultAccumulator{}
+}
+
+// Add records the access for a single resource and verb. The resource is
+// identified by its name as in ResourceAccess, e.g. "deployments.apps".
+// Recording the same resource and verb again overwrites the previous outcome.
+func (a *ResultAccumulator) Add(resource, verb string, access Access) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.forResource(resource)[verb] = access
+}
+
+// AddResource records the access for several verbs of the given resource.
+// Verbs which are not contained in access keep their previous outcome.
+func (a *ResultAccumulator) AddResource(resource string, access map[string]Access) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	verbs := a.forResource(resource)
+	for v, acc := range access {
+		verbs[v] = acc
+	}
+}
+
+// forResource returns the verb map of the resource. The caller must hold a.mu.
+func (a *ResultAccumulator) forResource(resource string) map[string]Access {
+	if a.res == nil {
+		a.res = make(ResourceAccess)
+	}
+	verbs, ok := a.res[resource]
+	if !ok {
+		verbs = make(map[string]Access)
+		a.res[resource] = verbs
+	}
+	return verbs
+}
+
+// Result returns a copy of all outcomes recorded so far. It may be called
+// while results are still added, and later additions do not affect the
+// returned ResourceAccess.
+func (a *ResultAccumulator) Result() ResourceAccess {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	res := make(ResourceAccess, len(a.res))
+	for resource, verbs := range a.res {
+		cp := make(map[string]Access, len(verbs))
+		for v, acc := range verbs {
+			cp[v] = acc
+		}
+		res[resource] = cp
+	}
+	return res
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultAccumulator(t *testing.T) {
+	var acc ResultAccumulator
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resource := fmt.Sprintf("resource-%d", i%2)
+			acc.Add(resource, fmt.Sprintf("verb-%d", i), Allowed)
+		}(i)
+	}
+	wg.Wait()
+
+	acc.AddResource("pods", map[string]Access{"get": Allowed, "list": Denied})
+	acc.AddResource("pods", map[string]Access{"list": RequestErr})
+	snapshot := acc.Result()
+	acc.Add("pods", "get", Denied)
+
+	assert.Equal(t, ResourceAccess{
+		"resource-0": {"verb-0": Allowed, "verb-2": Allowed, "verb-4": Allowed, "verb-6": Allowed, "verb-8": Allowed},
+		"resource-1": {"verb-1": Allowed, "verb-3": Allowed, "verb-5": Allowed, "verb-7": Allowed, "verb-9": Allowed},
+		"pods":       {"get": Allowed, "list": RequestErr},
+	}, snapshot)
+	assert.Equal(t, Denied, acc.Result()["pods"]["get"])
+}
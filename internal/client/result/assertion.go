@@ -0,0 +1,142 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Assertion is a policy check on the access matrix. It expects the access to
+// Verb on Resource to be allowed, or to be denied if Allowed is false.
+type Assertion struct {
+	// Flag is the name of the flag which defined the assertion.
+	Flag     string
+	Verb     string
+	Resource string
+	Allowed  bool
+}
+
+// Name is the human-readable name of the assertion, as given on the command line.
+func (a Assertion) Name() string {
+	return fmt.Sprintf("%s %s:%s", a.Flag, a.Verb, a.Resource)
+}
+
+// AssertionResult is an evaluated assertion. Message explains the outcome of
+// a failed assertion.
+type AssertionResult struct {
+	Assertion
+	Passed  bool
+	Message string
+}
+
+// ParseAssertions parses assertions in the form <verb>:<resource>[.<group>].
+// The assertions given as allowed expect access, all others expect no access.
+func ParseAssertions(allowedFlag string, allowed []string, deniedFlag string, denied []string) ([]Assertion, error) {
+	var assertions []Assertion
+	parse := func(flag string, specs []string, expectAllowed bool) error {
+		for _, s := range specs {
+			parts := strings.SplitN(s, ":", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("--%s expects format <verb>:<resource>, got %q", flag, s)
+			}
+			assertions = append(assertions, Assertion{
+				Flag:     flag,
+				Verb:     parts[0],
+				Resource: parts[1],
+				Allowed:  expectAllowed,
+			})
+		}
+		return nil
+	}
+	if err := parse(allowedFlag, allowed, true); err != nil {
+		return nil, err
+	}
+	if err := parse(deniedFlag, denied, false); err != nil {
+		return nil, err
+	}
+	return assertions, nil
+}
+
+// Evaluate checks the assertions against the access matrix. An assertion
+// fails if the resource was not checked or the access review failed.
+func (ra ResourceAccess) Evaluate(assertions []Assertion) []AssertionResult {
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		want := Denied
+		if a.Allowed {
+			want = Allowed
+		}
+		res := AssertionResult{Assertion: a}
+		access, ok := ra[a.Resource]
+		switch {
+		case !ok:
+			res.Message = fmt.Sprintf("resource %s was not checked", a.Resource)
+		case access[a.Verb] == want:
+			res.Passed = true
+		case access[a.Verb] == Allowed || access[a.Verb] == Denied:
+			res.Message = fmt.Sprintf("%s %s is %s, expected %s", a.Verb, a.Resource, access[a.Verb], want)
+		default:
+			res.Message = fmt.Sprintf("%s %s is %s", a.Verb, a.Resource, access[a.Verb])
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// Restricted returns the cells of the given verbs which are denied, or whose
+// access review failed, in the form <verb>:<resource>. Verbs which do not
+// apply to a resource are skipped.
+func (ra ResourceAccess) Restricted(verbs []string) []string {
+	var cells []string
+	for _, resource := range sortedKeys(ra) {
+		for _, v := range verbs {
+			if a, ok := ra[resource][v]; ok && (a == Denied || a == RequestErr) {
+				cells = append(cells, fmt.Sprintf("%s:%s", v, resource))
+			}
+		}
+	}
+	return cells
+}
+
+// Restricted returns the restricted cells of every namespace, prefixed with
+// the namespace as <namespace>/<verb>:<resource>.
+func (nra NamespacedResourceAccess) Restricted(verbs []string) []string {
+	namespaces := make([]string, 0, len(nra))
+	for ns := range nra {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var cells []string
+	for _, ns := range namespaces {
+		for _, cell := range nra[ns].Restricted(verbs) {
+			cells = append(cells, ns+"/"+cell)
+		}
+	}
+	return cells
+}
+
+func sortedKeys(ra ResourceAccess) []string {
+	keys := make([]string, 0, len(ra))
+	for k := range ra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssertions(t *testing.T) {
+	assertions, err := ParseAssertions("require-allowed", []string{"list:pods"}, "fail-if-allowed", []string{"delete:deployments.apps"})
+	require.NoError(t, err)
+	assert.Equal(t, []Assertion{
+		{Flag: "require-allowed", Verb: "list", Resource: "pods", Allowed: true},
+		{Flag: "fail-if-allowed", Verb: "delete", Resource: "deployments.apps"},
+	}, assertions)
+	assert.Equal(t, "fail-if-allowed delete:deployments.apps", assertions[1].Name())
+
+	_, err = ParseAssertions("require-allowed", []string{"pods"}, "fail-if-allowed", nil)
+	assert.EqualError(t, err, `--require-allowed expects format <verb>:<resource>, got "pods"`)
+}
+
+func TestResourceAccess_Evaluate(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":             {"list": Allowed, "delete": Denied},
+		"deployments.apps": {"list": RequestErr},
+	}
+	tests := []struct {
+		name      string
+		assertion Assertion
+		passed    bool
+		message   string
+	}{
+		{
+			name:      "required and allowed",
+			assertion: Assertion{Verb: "list", Resource: "pods", Allowed: true},
+			passed:    true,
+		},
+		{
+			name:      "required but denied",
+			assertion: Assertion{Verb: "delete", Resource: "pods", Allowed: true},
+			message:   "delete pods is denied, expected allowed",
+		},
+		{
+			name:      "forbidden and denied",
+			assertion: Assertion{Verb: "delete", Resource: "pods"},
+			passed:    true,
+		},
+		{
+			name:      "forbidden but allowed",
+			assertion: Assertion{Verb: "list", Resource: "pods"},
+			message:   "list pods is allowed, expected denied",
+		},
+		{
+			name:      "request error",
+			assertion: Assertion{Verb: "list", Resource: "deployments.apps"},
+			message:   "list deployments.apps is error",
+		},
+		{
+			name:      "unknown resource",
+			assertion: Assertion{Verb: "list", Resource: "secrets"},
+			message:   "resource secrets was not checked",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := ra.Evaluate([]Assertion{test.assertion})
+			require.Len(t, results, 1)
+			assert.Equal(t, test.passed, results[0].Passed)
+			assert.Equal(t, test.message, results[0].Message)
+		})
+	}
+}
+
+func TestResourceAccess_Restricted(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":             {"get": Allowed, "list": Denied, "delete": Denied},
+		"deployments.apps": {"get": RequestErr, "list": NotApplicable},
+		"configmaps":       {"get": Allowed, "list": Allowed},
+	}
+	assert.Equal(t, []string{"get:deployments.apps", "list:pods"}, ra.Restricted([]string{"get", "list"}))
+	assert.Empty(t, ra.Restricted([]string{"watch"}))
+
+	nra := NamespacedResourceAccess{
+		"prod":    {"pods": {"get": Denied}},
+		"default": {"pods": {"get": Allowed}, "secrets": {"get": Denied}},
+	}
+	assert.Equal(t, []string{"default/get:secrets", "prod/get:pods"}, nra.Restricted([]string{"get"}))
+}
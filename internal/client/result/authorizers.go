@@ -0,0 +1,86 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// AuthorizerComparison contrasts the combined result of all authorizers, as
+// reported by access reviews, with the access derived from RBAC rules alone.
+type AuthorizerComparison struct {
+	Combined ResourceAccess
+	RBAC     ResourceAccess
+}
+
+// Differences returns the verbs for which the combined result and the RBAC
+// result differ, per resource. Errors and not applicable verbs are skipped.
+func (c *AuthorizerComparison) Differences(verbs []string) map[string][]string {
+	diffs := make(map[string][]string)
+	for name, combined := range c.Combined {
+		for _, v := range verbs {
+			a, r := combined[v], c.RBAC[name][v]
+			if a == RequestErr || a == NotApplicable || a == r {
+				continue
+			}
+			diffs[name] = append(diffs[name], v)
+		}
+	}
+	return diffs
+}
+
+// Table renders the RBAC result for each resource, as well as the combined
+// result for wide tables. The DIFFERS column names the verbs for which the
+// other authorizers change the outcome.
+func (c *AuthorizerComparison) Table(verbs []string, wide bool) *printer.Table {
+	headers := []string{"NAME"}
+	for _, v := range verbs {
+		if wide {
+			headers = append(headers, strings.ToUpper(v))
+		}
+		headers = append(headers, strings.ToUpper(v)+"-RBAC")
+	}
+	headers = append(headers, "DIFFERS")
+	p := printer.TableWithHeaders(headers)
+
+	names := make([]string, 0, len(c.Combined))
+	for name := range c.Combined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	diffs := c.Differences(verbs)
+	for _, name := range names {
+		var outcomes []printer.Outcome
+		for _, v := range verbs {
+			if wide {
+				outcomes = append(outcomes, accessOutcomes(c.Combined[name], []string{v})...)
+			}
+			outcomes = append(outcomes, accessOutcomes(c.RBAC[name], []string{v})...)
+		}
+		var notes []string
+		for _, v := range diffs[name] {
+			notes = append(notes, fmt.Sprintf("%s (rbac %s, combined %s)", v, c.RBAC[name][v], c.Combined[name][v]))
+		}
+		p.Rows = append(p.Rows, printer.Row{Intro: []string{name}, Entries: outcomes, Outro: []string{strings.Join(notes, ", ")}})
+	}
+	return p
+}
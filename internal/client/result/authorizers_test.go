@@ -0,0 +1,51 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizerComparison(t *testing.T) {
+	c := &AuthorizerComparison{
+		Combined: ResourceAccess{
+			"pods":       {"get": Allowed, "list": Denied},
+			"configmaps": {"get": RequestErr, "list": Allowed},
+		},
+		RBAC: ResourceAccess{
+			"pods":       {"get": Allowed, "list": Allowed},
+			"configmaps": {"get": Denied, "list": Allowed},
+		},
+	}
+	verbs := []string{"get", "list"}
+
+	assert.Equal(t, map[string][]string{"pods": {"list"}}, c.Differences(verbs))
+
+	narrow := c.Table(verbs, false)
+	assert.Equal(t, []string{"NAME", "GET-RBAC", "LIST-RBAC", "DIFFERS"}, narrow.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps"}, Entries: []printer.Outcome{printer.Down, printer.Up}, Outro: []string{""}},
+		{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Up, printer.Up}, Outro: []string{"list (rbac allowed, combined denied)"}},
+	}, narrow.Rows)
+
+	wide := c.Table(verbs, true)
+	assert.Equal(t, []string{"NAME", "GET", "GET-RBAC", "LIST", "LIST-RBAC", "DIFFERS"}, wide.Headers)
+	assert.Equal(t, []printer.Outcome{printer.Up, printer.Up, printer.Down, printer.Up}, wide.Rows[1].Entries)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DefaultServiceAccountName is the service-account which pods use, if they do
+// not name one explicitly.
+const DefaultServiceAccountName = "default"
+
+// DefaultServiceAccountFinding is the access of the default service-account
+// of a namespace to one resource.
+type DefaultServiceAccountFinding struct {
+	Namespace string
+	Resource  string
+	Verbs     []string
+}
+
+// Write tells whether the finding allows to modify objects.
+func (f DefaultServiceAccountFinding) Write() bool {
+	for _, v := range f.Verbs {
+		if writeVerbs[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultServiceAccountFindings lists the access of the default
+// service-accounts to several resources.
+type DefaultServiceAccountFindings []DefaultServiceAccountFinding
+
+// FindDefaultServiceAccounts returns the access of the default service-account
+// of every namespace to the resources of the given subject accesses. Only the
+// given verbs are considered, and the findings are sorted by namespace.
+func FindDefaultServiceAccounts(accesses []*SubjectAccess, verbs []string) DefaultServiceAccountFindings {
+	var findings DefaultServiceAccountFindings
+	for _, sa := range accesses {
+		for s, granted := range sa.subjectToVerbs {
+			if s.Kind != v1.ServiceAccountKind || s.Name != DefaultServiceAccountName {
+				continue
+			}
+			var allowed []string
+			for _, v := range verbs {
+				if granted.Has(v) {
+					allowed = append(allowed, v)
+				}
+			}
+			if len(allowed) == 0 {
+				continue
+			}
+			findings = append(findings, DefaultServiceAccountFinding{Namespace: s.Namespace, Resource: sa.GroupResource.String(), Verbs: allowed})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Namespace != findings[j].Namespace {
+			return findings[i].Namespace < findings[j].Namespace
+		}
+		return findings[i].Resource < findings[j].Resource
+	})
+	return findings
+}
+
+// Table renders one row per namespace and resource. Rows with write access
+// are highlighted.
+func (f DefaultServiceAccountFindings) Table(verbs []string) *printer.Table {
+	headers := []string{"NAMESPACE", "RESOURCE"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	p := printer.TableWithHeaders(headers)
+	for _, finding := range f {
+		p.AddRow([]string{finding.Namespace, finding.Resource}, verbOutcomes(sets.NewString(finding.Verbs...), verbs)...)
+		p.Rows[len(p.Rows)-1].Highlight = finding.Write()
+	}
+	return p
+}
+
+// WriteNamespaces returns the namespaces whose default service-account has write
+// access to any resource.
+func (f DefaultServiceAccountFindings) WriteNamespaces() []string {
+	var namespaces []string
+	for _, finding := range f {
+		if finding.Write() && (len(namespaces) == 0 || namespaces[len(namespaces)-1] != finding.Namespace) {
+			namespaces = append(namespaces, finding.Namespace)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestFindDefaultServiceAccounts(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	editor := RoleRef{Name: "editor", Kind: "ClusterRole"}
+
+	secrets := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	secrets.roleToVerbs[reader] = sets.NewString("get", "list")
+	secrets.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "RoleBinding", Namespace: "prod"}, []v1.Subject{
+		{Kind: "ServiceAccount", Name: "default", Namespace: "prod"},
+		{Kind: "ServiceAccount", Name: "app", Namespace: "prod"},
+	})
+	deployments := NewSubjectAccess(schema.GroupResource{Group: "apps", Resource: "deployments"}, "")
+	deployments.roleToVerbs[editor] = sets.NewString("list", "update")
+	deployments.ResolveRoleRef(editor, BindingRef{Name: "editors", Kind: "ClusterRoleBinding"}, []v1.Subject{
+		{Kind: "ServiceAccount", Name: "default", Namespace: "ci"},
+		{Kind: "User", Name: "default"},
+	})
+
+	findings := FindDefaultServiceAccounts([]*SubjectAccess{secrets, deployments}, []string{"list", "update"})
+	assert.Equal(t, DefaultServiceAccountFindings{
+		{Namespace: "ci", Resource: "deployments.apps", Verbs: []string{"list", "update"}},
+		{Namespace: "prod", Resource: "secrets", Verbs: []string{"list"}},
+	}, findings)
+	assert.Equal(t, []string{"ci"}, findings.WriteNamespaces())
+
+	table := findings.Table([]string{"list", "update"})
+	assert.Equal(t, []string{"NAMESPACE", "RESOURCE", "LIST", "UPDATE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"ci", "deployments.apps"}, Entries: []printer.Outcome{printer.Up, printer.Up}, Highlight: true},
+		{Intro: []string{"prod", "secrets"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+	}, table.Rows)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Describe translates the allowed verbs out of the reviewed verbs into a
+// phrase such as "read-only", followed by the allowed verbs in parentheses.
+// If no description matches, the phrase names the allowed and denied verbs,
+// such as "can delete but not create".
+func Describe(allowed sets.String, reviewed []string, descriptions []constants.AccessDescription) string {
+	var granted, denied []string
+	for _, v := range reviewed {
+		if allowed.Has(v) {
+			granted = append(granted, v)
+		} else {
+			denied = append(denied, v)
+		}
+	}
+	if len(granted) == 0 {
+		return "no access"
+	}
+
+	phrase := fmt.Sprintf("can %s", strings.Join(granted, ", "))
+	if len(denied) > 0 {
+		phrase += fmt.Sprintf(" but not %s", strings.Join(denied, ", "))
+	}
+	reviewedSet := sets.NewString(reviewed...)
+	grantedSet := sets.NewString(granted...)
+	// the most specific description wins, so that get and list are read-only
+	// rather than read-write, if no write verb was reviewed
+	specific := -1
+	for _, d := range descriptions {
+		verbs := sets.NewString(d.Verbs...)
+		if !verbs.Intersection(reviewedSet).Equal(grantedSet) {
+			continue
+		}
+		if specific < 0 || verbs.Len() < specific {
+			phrase = d.Description
+			specific = verbs.Len()
+		}
+	}
+	return fmt.Sprintf("%s (%s)", phrase, strings.Join(granted, ", "))
+}
+
+// DescriptionTable renders the access to every resource as a description of
+// the allowed verbs. Verbs which are not applicable to a resource are ignored.
+func (ra ResourceAccess) DescriptionTable(verbs []string, descriptions []constants.AccessDescription) *printer.Table {
+	p := printer.TableWithHeaders([]string{"NAME", "ACCESS"})
+	for _, gr := range ra.sortedGroupResources() {
+		access := ra[gr.String()]
+		allowed := sets.NewString()
+		var reviewed []string
+		for _, v := range verbs {
+			switch access[v] {
+			case NotApplicable:
+				continue
+			case Allowed:
+				allowed.Insert(v)
+			}
+			reviewed = append(reviewed, v)
+		}
+		p.AddRow([]string{gr.String(), Describe(allowed, reviewed, descriptions)})
+	}
+	return p
+}
+
+// DescriptionTable renders the access of every subject as a description of
+// the allowed verbs.
+func (sa *SubjectAccess) DescriptionTable(verbs []string, descriptions []constants.AccessDescription) *printer.Table {
+	p := printer.TableWithHeaders([]string{"NAME", "KIND", "SA-NAMESPACE", "ACCESS"})
+	for _, s := range sa.Subjects() {
+		p.AddRow([]string{s.Name, s.Kind, s.Namespace, Describe(sa.subjectToVerbs[s], verbs, descriptions)})
+	}
+	return p
+}
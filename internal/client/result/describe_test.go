@@ -0,0 +1,121 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		reviewed []string
+		expected string
+	}{
+		{
+			name:     "nothing allowed",
+			reviewed: []string{"get", "list"},
+			expected: "no access",
+		},
+		{
+			name:     "read-only",
+			allowed:  []string{"get", "list", "watch"},
+			reviewed: constants.ValidVerbs,
+			expected: "read-only (get, list, watch)",
+		},
+		{
+			name:     "read-only restricted to reviewed verbs",
+			allowed:  []string{"list"},
+			reviewed: []string{"list", "create", "update", "delete"},
+			expected: "read-only (list)",
+		},
+		{
+			name:     "read-write",
+			allowed:  []string{"list", "create", "update"},
+			reviewed: []string{"list", "create", "update", "delete"},
+			expected: "read-write (list, create, update)",
+		},
+		{
+			name:     "read-only without write verbs",
+			allowed:  []string{"get", "list"},
+			reviewed: []string{"get", "list"},
+			expected: "read-only (get, list)",
+		},
+		{
+			name:     "full control",
+			allowed:  []string{"list", "create", "update", "delete"},
+			reviewed: []string{"list", "create", "update", "delete"},
+			expected: "full control (list, create, update, delete)",
+		},
+		{
+			name:     "no matching description",
+			allowed:  []string{"delete"},
+			reviewed: []string{"list", "create", "delete"},
+			expected: "can delete but not list, create (delete)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Describe(sets.NewString(test.allowed...), test.reviewed, constants.AccessDescriptions)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDescribe_customDescriptions(t *testing.T) {
+	descriptions := []constants.AccessDescription{{Description: "can deploy", Verbs: []string{"get", "create", "update", "patch"}}}
+
+	actual := Describe(sets.NewString("get", "create"), []string{"get", "create", "delete"}, descriptions)
+	assert.Equal(t, "can deploy (get, create)", actual)
+}
+
+func TestResourceAccess_DescriptionTable(t *testing.T) {
+	ra := ResourceAccess{
+		"configmaps":       {"get": Allowed, "list": Allowed, "delete": Denied},
+		"deployments.apps": {"get": Allowed, "list": Allowed, "delete": NotApplicable},
+	}
+
+	table := ra.DescriptionTable([]string{"get", "list", "delete"}, constants.AccessDescriptions)
+
+	assert.Equal(t, []string{"NAME", "ACCESS"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"configmaps", "read-only (get, list)"}},
+		{Intro: []string{"deployments.apps", "read-only (get, list)"}},
+	}, table.Rows)
+}
+
+func TestSubjectAccess_DescriptionTable(t *testing.T) {
+	sa := &SubjectAccess{subjectToVerbs: map[SubjectRef]sets.String{
+		{Name: "alice", Kind: "User"}:                               sets.NewString("get", "list"),
+		{Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}: sets.NewString("delete"),
+	}}
+
+	table := sa.DescriptionTable([]string{"get", "list", "delete"}, constants.AccessDescriptions)
+
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "ACCESS"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"alice", "User", "", "read-only (get, list)"}},
+		{Intro: []string{"deployer", "ServiceAccount", "ci", "can delete but not get, list (delete)"}},
+	}, table.Rows)
+}
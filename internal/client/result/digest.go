@@ -0,0 +1,48 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Digest returns the SHA-256 hash of the canonicalized access for the given
+// verbs. Resources and verbs are sorted, so that the digest only changes if
+// the access changes.
+func (ra ResourceAccess) Digest(verbs []string) string {
+	sortedVerbs := append([]string(nil), verbs...)
+	sort.Strings(sortedVerbs)
+	resources := make([]string, 0, len(ra))
+	for name := range ra {
+		resources = append(resources, name)
+	}
+	sort.Strings(resources)
+
+	h := sha256.New()
+	for _, name := range resources {
+		outcomes := make([]string, 0, len(sortedVerbs))
+		for _, v := range sortedVerbs {
+			outcomes = append(outcomes, fmt.Sprintf("%s=%s", v, ra[name][v]))
+		}
+		fmt.Fprintf(h, "%s\t%s\n", name, strings.Join(outcomes, ","))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceAccess_Digest(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":             {"get": Allowed, "list": Denied},
+		"deployments.apps": {"get": NotApplicable, "list": RequestErr},
+	}
+	digest := ra.Digest([]string{"list", "get"})
+
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", digest)
+	assert.Equal(t, digest, ra.Digest([]string{"get", "list"}), "verb order must not matter")
+
+	ra["pods"]["list"] = Allowed
+	assert.NotEqual(t, digest, ra.Digest([]string{"get", "list"}))
+}
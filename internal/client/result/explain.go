@@ -0,0 +1,65 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// DenyExplanation tells why access to a verb on a resource is denied.
+type DenyExplanation struct {
+	Resource string
+	Verb     string
+	// RulesGrant tells whether the RBAC rules of the user grant the verb, so
+	// that another authorizer must have denied it.
+	RulesGrant bool
+	// Reason is the reason of the access review, if the authorizers gave one.
+	Reason string
+}
+
+// DenyExplanations are the explanations for all denied verbs.
+type DenyExplanations []DenyExplanation
+
+// Sort orders the explanations by resource and verb.
+func (e DenyExplanations) Sort() {
+	sort.Slice(e, func(i, j int) bool {
+		if e[i].Resource != e[j].Resource {
+			return e[i].Resource < e[j].Resource
+		}
+		return e[i].Verb < e[j].Verb
+	})
+}
+
+// Table renders one row per denied verb.
+func (e DenyExplanations) Table() *printer.Table {
+	p := printer.TableWithHeaders([]string{"NAME", "VERB", "REASON"})
+	for _, x := range e {
+		reason := x.Reason
+		switch {
+		case reason == "" && x.RulesGrant:
+			reason = "granted by RBAC rules, but denied by another authorizer"
+		case reason == "":
+			reason = "no RBAC rule grants this verb"
+		case x.RulesGrant:
+			reason += " (granted by RBAC rules)"
+		}
+		p.AddRow([]string{x.Resource, x.Verb, reason})
+	}
+	return p
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"strings"
+)
+
+// writeVerbs are the verbs which modify objects.
+var writeVerbs = map[string]bool{
+	"*":                true,
+	"create":           true,
+	"update":           true,
+	"patch":            true,
+	"delete":           true,
+	"deletecollection": true,
+}
+
+// IsWrite tells whether the grant allows to modify objects.
+func (g Grant) IsWrite() bool {
+	return writeVerbs[g.Verb]
+}
+
+// GitHubComment formats the changes as Markdown for a pull request comment.
+// Added grants which allow to write or escalate are called out above a
+// collapsible section with the full list of changes.
+func (c GrantChanges) GitHubComment() string {
+	var b strings.Builder
+	b.WriteString("### RBAC impact\n\n")
+
+	var prominent []Grant
+	for _, g := range c.Added {
+		if g.IsWrite() || g.IsEscalation() {
+			prominent = append(prominent, g)
+		}
+	}
+	if len(prominent) > 0 {
+		fmt.Fprintf(&b, "> :warning: **%d added grants allow to write or escalate:**\n", len(prominent))
+		for _, g := range prominent {
+			fmt.Fprintf(&b, "> - %s\n", markdownGrant(g))
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "<details>\n<summary>%d grants added, %d grants removed</summary>\n\n", len(c.Added), len(c.Removed))
+	b.WriteString("| | SUBJECT | NAMESPACE | RESOURCE | VERB |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, g := range c.Added {
+		mark := ":heavy_plus_sign:"
+		if g.IsWrite() || g.IsEscalation() {
+			mark = ":warning:"
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", mark, strings.Join(markdownCells(g), " | "))
+	}
+	for _, g := range c.Removed {
+		fmt.Fprintf(&b, "| :heavy_minus_sign: | %s |\n", strings.Join(markdownCells(g), " | "))
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
+
+// markdownGrant formats a grant as a sentence, e.g. `create` on `secrets` in
+// `prod` for `User/alice`.
+func markdownGrant(g Grant) string {
+	cells := markdownCells(g)
+	return fmt.Sprintf("%s on %s in %s for %s", cells[3], cells[2], cells[1], cells[0])
+}
+
+// markdownCells returns the subject, namespace, resource, and verb of the
+// grant as inline code.
+func markdownCells(g Grant) []string {
+	intro := grantIntro(g)
+	cells := make([]string, 0, len(intro))
+	for _, c := range intro {
+		cells = append(cells, "`"+strings.ReplaceAll(c, "|", "\\|")+"`")
+	}
+	return cells
+}
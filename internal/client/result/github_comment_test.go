@@ -0,0 +1,54 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrantChanges_GitHubComment(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	changes := GrantChanges{
+		Added: []Grant{
+			{Subject: alice, Namespace: "prod", Resource: "secrets", Verb: "create"},
+			{Subject: alice, Namespace: "prod", Resource: "secrets", Verb: "get"},
+		},
+		Removed: []Grant{
+			{Subject: alice, APIGroup: "apps", Resource: "deployments", Verb: "list"},
+		},
+	}
+
+	expected := "### RBAC impact\n\n" +
+		"> :warning: **1 added grants allow to write or escalate:**\n" +
+		"> - `create` on `secrets` in `prod` for `User/alice`\n\n" +
+		"<details>\n<summary>2 grants added, 1 grants removed</summary>\n\n" +
+		"| | SUBJECT | NAMESPACE | RESOURCE | VERB |\n" +
+		"|---|---|---|---|---|\n" +
+		"| :warning: | `User/alice` | `prod` | `secrets` | `create` |\n" +
+		"| :heavy_plus_sign: | `User/alice` | `prod` | `secrets` | `get` |\n" +
+		"| :heavy_minus_sign: | `User/alice` | `*` | `deployments.apps` | `list` |\n" +
+		"\n</details>\n"
+	assert.Equal(t, expected, changes.GitHubComment())
+}
+
+func TestGrant_IsWrite(t *testing.T) {
+	assert.True(t, Grant{Verb: "patch"}.IsWrite())
+	assert.True(t, Grant{Verb: "*"}.IsWrite())
+	assert.False(t, Grant{Verb: "watch"}.IsWrite())
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	v1 "k8s.io/api/rbac/v1"
+)
+
+// RBAC is a set of RBAC objects, either from a cluster or from manifests.
+type RBAC struct {
+	ClusterRoles        []v1.ClusterRole
+	ClusterRoleBindings []v1.ClusterRoleBinding
+	Roles               []v1.Role
+	RoleBindings        []v1.RoleBinding
+}
+
+// Overlay returns the RBAC objects after applying the objects of other.
+// Objects in other replace objects of the same kind, namespace, and name.
+func (r *RBAC) Overlay(other *RBAC) *RBAC {
+	ret := &RBAC{}
+
+	clusterRoles := make(map[string]bool)
+	for _, o := range other.ClusterRoles {
+		clusterRoles[o.Name] = true
+	}
+	for _, o := range r.ClusterRoles {
+		if !clusterRoles[o.Name] {
+			ret.ClusterRoles = append(ret.ClusterRoles, o)
+		}
+	}
+	ret.ClusterRoles = append(ret.ClusterRoles, other.ClusterRoles...)
+
+	clusterRoleBindings := make(map[string]bool)
+	for _, o := range other.ClusterRoleBindings {
+		clusterRoleBindings[o.Name] = true
+	}
+	for _, o := range r.ClusterRoleBindings {
+		if !clusterRoleBindings[o.Name] {
+			ret.ClusterRoleBindings = append(ret.ClusterRoleBindings, o)
+		}
+	}
+	ret.ClusterRoleBindings = append(ret.ClusterRoleBindings, other.ClusterRoleBindings...)
+
+	roles := make(map[string]bool)
+	for _, o := range other.Roles {
+		roles[o.Namespace+"/"+o.Name] = true
+	}
+	for _, o := range r.Roles {
+		if !roles[o.Namespace+"/"+o.Name] {
+			ret.Roles = append(ret.Roles, o)
+		}
+	}
+	ret.Roles = append(ret.Roles, other.Roles...)
+
+	roleBindings := make(map[string]bool)
+	for _, o := range other.RoleBindings {
+		roleBindings[o.Namespace+"/"+o.Name] = true
+	}
+	for _, o := range r.RoleBindings {
+		if !roleBindings[o.Namespace+"/"+o.Name] {
+			ret.RoleBindings = append(ret.RoleBindings, o)
+		}
+	}
+	ret.RoleBindings = append(ret.RoleBindings, other.RoleBindings...)
+
+	return ret
+}
+
+// Grant is a single verb on a resource (or non-resource URL), which is granted
+// to a subject. The namespace is empty for grants in all namespaces.
+type Grant struct {
+	Subject      SubjectRef
+	Namespace    string
+	APIGroup     string
+	Resource     string
+	ResourceName string
+	Verb         string
+}
+
+// resourceString formats the granted resource, e.g. deployments.apps/my-app.
+func (g Grant) resourceString() string {
+	res := g.Resource
+	if g.APIGroup != "" {
+		res += "." + g.APIGroup
+	}
+	if g.ResourceName != "" {
+		res += "/" + g.ResourceName
+	}
+	return res
+}
+
+// IsEscalation tells whether the grant uses wildcards or one of the special
+// verbs, which allow to gain further privileges.
+func (g Grant) IsEscalation() bool {
+	if g.Verb == v1.VerbAll || g.Resource == v1.ResourceAll || g.APIGroup == v1.APIGroupAll {
+		return true
+	}
+	for _, v := range constants.SpecialVerbs {
+		if g.Verb == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Grants determines every grant of every bound subject. Wildcards in rules are
+// not expanded.
+func (r *RBAC) Grants() map[Grant]bool {
+	clusterRoles := make(map[string][]v1.PolicyRule, len(r.ClusterRoles))
+	for _, role := range r.ClusterRoles {
+		clusterRoles[role.Name] = role.Rules
+	}
+	roles := make(map[string][]v1.PolicyRule, len(r.Roles))
+	for _, role := range r.Roles {
+		roles[role.Namespace+"/"+role.Name] = role.Rules
+	}
+
+	grants := make(map[Grant]bool)
+	for _, crb := range r.ClusterRoleBindings {
+		if crb.RoleRef.Kind != "ClusterRole" {
+			continue
+		}
+		addGrants(grants, clusterRoles[crb.RoleRef.Name], crb.Subjects, "")
+	}
+	for _, rb := range r.RoleBindings {
+		rules := roles[rb.Namespace+"/"+rb.RoleRef.Name]
+		if rb.RoleRef.Kind == "ClusterRole" {
+			rules = clusterRoles[rb.RoleRef.Name]
+		}
+		addGrants(grants, rules, rb.Subjects, rb.Namespace)
+	}
+	return grants
+}
+
+func addGrants(grants map[Grant]bool, rules []v1.PolicyRule, subjects []v1.Subject, namespace string) {
+	for _, subject := range subjects {
+		s := SubjectRef{Name: subject.Name, Kind: subject.Kind, Namespace: subject.Namespace}
+		for _, rule := range rules {
+			for _, verb := range rule.Verbs {
+				for _, url := range rule.NonResourceURLs {
+					if namespace == "" { // RoleBindings cannot grant non-resource URLs
+						grants[Grant{Subject: s, Resource: url, Verb: verb}] = true
+					}
+				}
+				names := rule.ResourceNames
+				if len(names) == 0 {
+					names = []string{""}
+				}
+				for _, group := range rule.APIGroups {
+					for _, resource := range rule.Resources {
+						for _, name := range names {
+							grants[Grant{Subject: s, Namespace: namespace, APIGroup: group, Resource: resource, ResourceName: name, Verb: verb}] = true
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// GrantChanges are the grants which are added or removed by an RBAC change.
+type GrantChanges struct {
+	Added, Removed []Grant
+}
+
+// DiffGrants determines the grants which are only in after (added), or only in
+// before (removed).
+func DiffGrants(before, after map[Grant]bool) GrantChanges {
+	var changes GrantChanges
+	for g := range after {
+		if !before[g] {
+			changes.Added = append(changes.Added, g)
+		}
+	}
+	for g := range before {
+		if !after[g] {
+			changes.Removed = append(changes.Removed, g)
+		}
+	}
+	sortGrants(changes.Added)
+	sortGrants(changes.Removed)
+	return changes
+}
+
+// Escalations returns the added grants which are escalations.
+func (c GrantChanges) Escalations() []Grant {
+	var ret []Grant
+	for _, g := range c.Added {
+		if g.IsEscalation() {
+			ret = append(ret, g)
+		}
+	}
+	return ret
+}
+
+// Table renders the changes with one grant per row. Added grants are marked
+// as Up, removed grants as Down.
+func (c GrantChanges) Table() *printer.Table {
+	p := printer.TableWithHeaders([]string{"SUBJECT", "NAMESPACE", "RESOURCE", "VERB", "CHANGE"})
+	for _, g := range c.Added {
+		p.AddRow(grantIntro(g), printer.Up)
+	}
+	for _, g := range c.Removed {
+		p.AddRow(grantIntro(g), printer.Down)
+	}
+	return p
+}
+
+func grantIntro(g Grant) []string {
+	namespace := g.Namespace
+	if namespace == "" {
+		namespace = "*"
+	}
+	return []string{formatSubject(g.Subject), namespace, g.resourceString(), g.Verb}
+}
+
+func sortGrants(grants []Grant) {
+	sort.Slice(grants, func(i, j int) bool {
+		a, b := grantIntro(grants[i]), grantIntro(grants[j])
+		return strings.Join(a, "\x00") < strings.Join(b, "\x00")
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRBAC_Grants(t *testing.T) {
+	rbac := &RBAC{
+		ClusterRoles: []v1.ClusterRole{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "reader"},
+				Rules: []v1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+					{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+				},
+			},
+		},
+		ClusterRoleBindings: []v1.ClusterRoleBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "readers"},
+				RoleRef:    v1.RoleRef{Kind: "ClusterRole", Name: "reader"},
+				Subjects:   []v1.Subject{{Kind: "User", Name: "alice"}},
+			},
+		},
+		Roles: []v1.Role{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "ns"},
+				Rules:      []v1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}, Verbs: []string{"get"}}},
+			},
+		},
+		RoleBindings: []v1.RoleBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "ns"},
+				RoleRef:    v1.RoleRef{Kind: "Role", Name: "secret"},
+				Subjects:   []v1.Subject{{Kind: "User", Name: "bob"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "ns"},
+				RoleRef:    v1.RoleRef{Kind: "ClusterRole", Name: "reader"},
+				Subjects:   []v1.Subject{{Kind: "User", Name: "bob"}},
+			},
+		},
+	}
+
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	bob := SubjectRef{Name: "bob", Kind: "User"}
+	assert.Equal(t, map[Grant]bool{
+		{Subject: alice, Resource: "pods", Verb: "get"}:                                       true,
+		{Subject: alice, Resource: "/metrics", Verb: "get"}:                                   true,
+		{Subject: bob, Namespace: "ns", Resource: "secrets", ResourceName: "db", Verb: "get"}: true,
+		{Subject: bob, Namespace: "ns", Resource: "pods", Verb: "get"}:                        true,
+	}, rbac.Grants())
+}
+
+func TestRBAC_Overlay(t *testing.T) {
+	current := &RBAC{
+		ClusterRoles: []v1.ClusterRole{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Rules: []v1.PolicyRule{{Verbs: []string{"get"}}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		},
+		Roles: []v1.Role{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns1"}},
+		},
+	}
+	proposed := &RBAC{
+		ClusterRoles: []v1.ClusterRole{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Rules: []v1.PolicyRule{{Verbs: []string{"*"}}}},
+		},
+		Roles: []v1.Role{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns2"}},
+		},
+	}
+
+	assert.Equal(t, &RBAC{
+		ClusterRoles: []v1.ClusterRole{
+			{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Rules: []v1.PolicyRule{{Verbs: []string{"*"}}}},
+		},
+		Roles: []v1.Role{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns2"}},
+		},
+	}, current.Overlay(proposed))
+}
+
+func TestDiffGrants(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	get := Grant{Subject: alice, Resource: "pods", Verb: "get"}
+	list := Grant{Subject: alice, Resource: "pods", Verb: "list"}
+	impersonate := Grant{Subject: alice, Resource: "users", Verb: "impersonate"}
+	wildcard := Grant{Subject: alice, Namespace: "ns", APIGroup: "*", Resource: "deployments", Verb: "get"}
+
+	changes := DiffGrants(
+		map[Grant]bool{get: true, list: true},
+		map[Grant]bool{get: true, impersonate: true, wildcard: true},
+	)
+
+	assert.Equal(t, GrantChanges{
+		Added:   []Grant{impersonate, wildcard},
+		Removed: []Grant{list},
+	}, changes)
+	assert.Equal(t, []Grant{impersonate, wildcard}, changes.Escalations())
+	assert.Equal(t, [][]string{
+		{"User/alice", "*", "users", "impersonate"},
+		{"User/alice", "ns", "deployments.*", "get"},
+		{"User/alice", "*", "pods", "list"},
+	}, [][]string{changes.Table().Rows[0].Intro, changes.Table().Rows[1].Intro, changes.Table().Rows[2].Intro})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	v1 "k8s.io/api/rbac/v1"
+)
+
+// WildcardFinding is a role rule using wildcards, which is bound to a
+// non-system subject.
+type WildcardFinding struct {
+	Role    RoleRef
+	Binding BindingRef
+	Subject SubjectRef
+	// Wildcards lists the rule fields which contain *, out of verbs,
+	// resources, and apiGroups.
+	Wildcards []string
+}
+
+// Breadth ranks the finding. Every wildcard field counts, and bindings across
+// all namespaces rank higher than bindings in a single namespace.
+func (f WildcardFinding) Breadth() int {
+	breadth := 2 * len(f.Wildcards)
+	if f.Binding.Kind == "ClusterRoleBinding" {
+		breadth++
+	}
+	return breadth
+}
+
+// WildcardFindings is a list of WildcardFinding.
+type WildcardFindings []WildcardFinding
+
+// RuleWildcards returns the fields of the rule which use wildcards.
+func RuleWildcards(rule v1.PolicyRule) []string {
+	var wildcards []string
+	if hasWildcard(rule.Verbs) {
+		wildcards = append(wildcards, "verbs")
+	}
+	if hasWildcard(rule.Resources) {
+		wildcards = append(wildcards, "resources")
+	}
+	if hasWildcard(rule.APIGroups) {
+		wildcards = append(wildcards, "apiGroups")
+	}
+	return wildcards
+}
+
+func hasWildcard(values []string) bool {
+	for _, v := range values {
+		if v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSystemSubject tells whether the subject is maintained by kubernetes itself.
+func IsSystemSubject(s SubjectRef) bool {
+	return strings.HasPrefix(s.Name, constants.SystemRolePrefix)
+}
+
+// Sort orders the findings by decreasing breadth, and by role, binding, and
+// subject otherwise.
+func (fs WildcardFindings) Sort() {
+	sort.SliceStable(fs, func(i, j int) bool {
+		a, b := fs[i], fs[j]
+		if a.Breadth() != b.Breadth() {
+			return a.Breadth() > b.Breadth()
+		}
+		if a.Role != b.Role {
+			return formatRole(a.Role) < formatRole(b.Role)
+		}
+		if a.Binding != b.Binding {
+			return formatBinding(a.Binding) < formatBinding(b.Binding)
+		}
+		return formatSubject(a.Subject) < formatSubject(b.Subject)
+	})
+}
+
+// Table renders the findings, one finding per row.
+func (fs WildcardFindings) Table() *printer.Table {
+	p := printer.TableWithHeaders([]string{"ROLE", "BINDING", "SUBJECT", "WILDCARDS"})
+	for _, f := range fs {
+		p.AddRow([]string{formatRole(f.Role), formatBinding(f.Binding), formatSubject(f.Subject), strings.Join(f.Wildcards, ",")})
+	}
+	return p
+}
+
+func formatRole(r RoleRef) string {
+	return fmt.Sprintf("%s/%s", r.Kind, r.Name)
+}
+
+func formatBinding(b BindingRef) string {
+	if b.Namespace == "" {
+		return fmt.Sprintf("%s/%s", b.Kind, b.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", b.Kind, b.Namespace, b.Name)
+}
+
+func formatSubject(s SubjectRef) string {
+	if s.Namespace == "" {
+		return fmt.Sprintf("%s/%s", s.Kind, s.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", s.Kind, s.Namespace, s.Name)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+)
+
+func TestRuleWildcards(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     v1.PolicyRule
+		expected []string
+	}{
+		{
+			name: "no wildcards",
+			rule: v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+		},
+		{
+			name:     "all verbs",
+			rule:     v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "*"}},
+			expected: []string{"verbs"},
+		},
+		{
+			name:     "everything",
+			rule:     v1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			expected: []string{"verbs", "resources", "apiGroups"},
+		},
+		{
+			name: "subresource wildcard is not a wildcard resource",
+			rule: v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/*"}, Verbs: []string{"get"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, RuleWildcards(test.rule))
+		})
+	}
+}
+
+func TestWildcardFindings_Sort(t *testing.T) {
+	narrow := WildcardFinding{
+		Role:      RoleRef{Name: "narrow", Kind: "ClusterRole"},
+		Binding:   BindingRef{Name: "b", Kind: "ClusterRoleBinding"},
+		Wildcards: []string{"verbs"},
+	}
+	namespaced := WildcardFinding{
+		Role:      RoleRef{Name: "broad", Kind: "Role"},
+		Binding:   BindingRef{Name: "b", Kind: "RoleBinding", Namespace: "ns"},
+		Wildcards: []string{"verbs", "resources"},
+	}
+	clusterWide := WildcardFinding{
+		Role:      RoleRef{Name: "broad", Kind: "ClusterRole"},
+		Binding:   BindingRef{Name: "b", Kind: "ClusterRoleBinding"},
+		Wildcards: []string{"verbs", "resources"},
+	}
+
+	findings := WildcardFindings{narrow, namespaced, clusterWide}
+	findings.Sort()
+	assert.Equal(t, WildcardFindings{clusterWide, namespaced, narrow}, findings)
+}
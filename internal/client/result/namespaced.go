@@ -0,0 +1,159 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// NamespacedSubjectAccess holds the subject access per namespace. The empty
+// namespace holds the access which is granted cluster-wide.
+type NamespacedSubjectAccess map[string]*SubjectAccess
+
+// namespaces returns the namespaces in sorted order, so that the cluster-wide
+// access comes first.
+func (nsa NamespacedSubjectAccess) namespaces() []string {
+	namespaces := make([]string, 0, len(nsa))
+	for ns := range nsa {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Merge combines the access in all namespaces into a single SubjectAccess.
+// The bindings keep their namespaces, so that the scope of every subject is
+// retained.
+func (nsa NamespacedSubjectAccess) Merge() *SubjectAccess {
+	parts := make([]*SubjectAccess, 0, len(nsa))
+	for _, ns := range nsa.namespaces() {
+		parts = append(parts, nsa[ns])
+	}
+	return MergeSubjectAccess(parts...)
+}
+
+// MergeSubjectAccess combines the access of all parts into a single
+// SubjectAccess. The parts are expected to be about the same resource.
+func MergeSubjectAccess(parts ...*SubjectAccess) *SubjectAccess {
+	var merged *SubjectAccess
+	for _, sa := range parts {
+		if merged == nil {
+			merged = NewSubjectAccess(sa.GroupResource, sa.ResourceName)
+			merged.NonResourceURL = sa.NonResourceURL
+			merged.Normalize = sa.Normalize
+		}
+		for r, verbs := range sa.roleToVerbs {
+			merged.roleToVerbs[r] = verbs.Union(merged.roleToVerbs[r])
+		}
+		for b, r := range sa.bindingToRole {
+			merged.bindingToRole[b] = r
+		}
+		for s, verbs := range sa.subjectToVerbs {
+			merged.subjectToVerbs[s] = verbs.Union(merged.subjectToVerbs[s])
+		}
+		for s, bindings := range sa.subjectToBindings {
+			mergedBindings, ok := merged.subjectToBindings[s]
+			if !ok {
+				mergedBindings = make(map[BindingRef]sets.String, len(bindings))
+				merged.subjectToBindings[s] = mergedBindings
+			}
+			for b, verbs := range bindings {
+				mergedBindings[b] = verbs.Union(mergedBindings[b])
+			}
+		}
+	}
+	if merged == nil {
+		return NewSubjectAccess(schema.GroupResource{}, "")
+	}
+	return merged
+}
+
+// ExcludeMasters removes the access via the masters group and the
+// cluster-admin ClusterRole in all namespaces.
+func (nsa NamespacedSubjectAccess) ExcludeMasters() {
+	for _, sa := range nsa {
+		sa.ExcludeMasters()
+	}
+}
+
+// NonSystemSubjects returns the subjects which are granted any of the verbs,
+// but are neither system subjects nor service-accounts in kube-system. The
+// subjects are formatted as Kind/name or Kind/namespace/name.
+func (nsa NamespacedSubjectAccess) NonSystemSubjects(verbs []string) []string {
+	seen := make(map[SubjectRef]bool)
+	var subjects []string
+	for _, ns := range nsa.namespaces() {
+		sa := nsa[ns]
+		for _, s := range sa.Subjects() {
+			if seen[s] || !sa.subjectToVerbs[s].HasAny(verbs...) || isSystemReader(s) {
+				continue
+			}
+			seen[s] = true
+			subjects = append(subjects, formatSubject(s))
+		}
+	}
+	return subjects
+}
+
+func isSystemReader(s SubjectRef) bool {
+	return IsSystemSubject(s) || (s.Kind == v1.ServiceAccountKind && s.Namespace == metav1.NamespaceSystem)
+}
+
+// Table renders one row per subject and namespace in which the subject is
+// granted any of the verbs. Cluster-wide access has the namespace "*".
+func (nsa NamespacedSubjectAccess) Table(verbs []string) *printer.Table {
+	return nsa.table(verbs, false)
+}
+
+// HighlightedTable renders the table like Table, but highlights the rows of
+// subjects which are neither system subjects nor service-accounts in
+// kube-system.
+func (nsa NamespacedSubjectAccess) HighlightedTable(verbs []string) *printer.Table {
+	return nsa.table(verbs, true)
+}
+
+func (nsa NamespacedSubjectAccess) table(verbs []string, highlight bool) *printer.Table {
+	headers := []string{"NAME", "KIND", "SA-NAMESPACE", "NAMESPACE"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	p := printer.TableWithHeaders(headers)
+
+	for _, ns := range nsa.namespaces() {
+		sa := nsa[ns]
+		displayNamespace := ns
+		if ns == "" {
+			displayNamespace = "*"
+		}
+		for _, s := range sa.Subjects() {
+			valid := sa.subjectToVerbs[s]
+			if !valid.HasAny(verbs...) {
+				continue
+			}
+			p.AddRow([]string{s.Name, s.Kind, s.Namespace, displayNamespace}, verbOutcomes(valid, verbs)...)
+			p.Rows[len(p.Rows)-1].Highlight = highlight && !isSystemReader(s)
+		}
+	}
+	return p
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// NamespacedResourceAccess holds the access result for all resources per namespace.
+type NamespacedResourceAccess map[string]ResourceAccess
+
+// RetainMinVerbs removes all resources which allow fewer than n out of the
+// given verbs in the respective namespace.
+func (nra NamespacedResourceAccess) RetainMinVerbs(verbs []string, n int) {
+	for _, ra := range nra {
+		ra.RetainMinVerbs(verbs, n)
+	}
+}
+
+// RetainOnly removes all resources where none of the given verbs has the
+// access a in the respective namespace.
+func (nra NamespacedResourceAccess) RetainOnly(verbs []string, a Access) {
+	for _, ra := range nra {
+		ra.RetainOnly(verbs, a)
+	}
+}
+
+// RetainApplicable removes all resources which support none of the given
+// verbs in the respective namespace.
+func (nra NamespacedResourceAccess) RetainApplicable(verbs []string) {
+	for _, ra := range nra {
+		ra.RetainApplicable(verbs)
+	}
+}
+
+// ApplicableVerbs returns the given verbs without those which no resource
+// supports in any namespace, in their original order.
+func (nra NamespacedResourceAccess) ApplicableVerbs(verbs []string) []string {
+	merged := make(ResourceAccess)
+	for ns, ra := range nra {
+		for name, access := range ra {
+			merged[ns+"/"+name] = access
+		}
+	}
+	return merged.ApplicableVerbs(verbs)
+}
+
+// Table renders one row per namespace and resource. The namespace column is
+// placed first or last, according to position.
+func (nra NamespacedResourceAccess) Table(verbs []string, position string) *printer.Table {
+	namespaces := make([]string, 0, len(nra))
+	for ns := range nra {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	headers := []string{"NAME"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	if position == constants.NamespaceColumnLast {
+		headers = append(headers, "NAMESPACE")
+	} else {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+	p := printer.TableWithHeaders(headers)
+
+	for _, ns := range namespaces {
+		ra := nra[ns]
+		for _, gr := range ra.sortedGroupResources() {
+			name := gr.String()
+			row := printer.Row{Intro: []string{ns, name}, Entries: accessOutcomes(ra[name], verbs)}
+			if position == constants.NamespaceColumnLast {
+				row.Intro, row.Outro = []string{name}, []string{ns}
+			}
+			p.Rows = append(p.Rows, row)
+		}
+	}
+	return p
+}
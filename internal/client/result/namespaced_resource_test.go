@@ -0,0 +1,57 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacedResourceAccess_Table(t *testing.T) {
+	nra := NamespacedResourceAccess{
+		"prod":    {"pods": {"get": Allowed}, "deployments.apps": {"get": Denied}},
+		"default": {"pods": {"get": RequestErr}},
+	}
+
+	first := nra.Table([]string{"get"}, "first")
+	assert.Equal(t, []string{"NAMESPACE", "NAME", "GET"}, first.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"default", "pods"}, Entries: []printer.Outcome{printer.Err}},
+		{Intro: []string{"prod", "pods"}, Entries: []printer.Outcome{printer.Up}},
+		{Intro: []string{"prod", "deployments.apps"}, Entries: []printer.Outcome{printer.Down}},
+	}, first.Rows)
+
+	last := nra.Table([]string{"get"}, "last")
+	assert.Equal(t, []string{"NAME", "GET", "NAMESPACE"}, last.Headers)
+	assert.Equal(t, printer.Row{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Err}, Outro: []string{"default"}}, last.Rows[0])
+}
+
+func TestNamespacedResourceAccess_RetainApplicable(t *testing.T) {
+	nra := NamespacedResourceAccess{
+		"prod":    {"pods": {"get": Allowed, "proxy": NotApplicable}, "bindings": {"get": NotApplicable, "proxy": NotApplicable}},
+		"default": {"pods": {"get": Denied, "proxy": NotApplicable}},
+	}
+
+	nra.RetainApplicable([]string{"get", "proxy"})
+	assert.Equal(t, NamespacedResourceAccess{
+		"prod":    {"pods": {"get": Allowed, "proxy": NotApplicable}},
+		"default": {"pods": {"get": Denied, "proxy": NotApplicable}},
+	}, nra)
+	assert.Equal(t, []string{"get"}, nra.ApplicableVerbs([]string{"get", "proxy"}))
+}
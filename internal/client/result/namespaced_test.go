@@ -0,0 +1,119 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestNamespacedSubjectAccess(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	newAccess := func(subjects ...v1.Subject) *SubjectAccess {
+		sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+		sa.roleToVerbs[reader] = sets.NewString("get")
+		sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "RoleBinding"}, subjects)
+		return sa
+	}
+	nsa := NamespacedSubjectAccess{
+		"prod": newAccess(v1.Subject{Kind: "User", Name: "alice"}, v1.Subject{Kind: "ServiceAccount", Name: "replicaset-controller", Namespace: "kube-system"}),
+		"":     newAccess(v1.Subject{Kind: "Group", Name: "system:masters"}, v1.Subject{Kind: "User", Name: "alice"}),
+	}
+
+	table := nsa.Table([]string{"get", "list"})
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "NAMESPACE", "GET", "LIST"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"alice", "User", "", "*"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+		{Intro: []string{"system:masters", "Group", "", "*"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+		{Intro: []string{"alice", "User", "", "prod"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+		{Intro: []string{"replicaset-controller", "ServiceAccount", "kube-system", "prod"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+	}, table.Rows)
+
+	assert.Equal(t, []string{"User/alice"}, nsa.NonSystemSubjects([]string{"get"}))
+	assert.Empty(t, nsa.NonSystemSubjects([]string{"list"}))
+}
+
+func TestNamespacedSubjectAccess_HighlightedTable(t *testing.T) {
+	admin := RoleRef{Name: "rbac-admin", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "roles"}, "")
+	sa.roleToVerbs[admin] = sets.NewString("create", "bind")
+	sa.ResolveRoleRef(admin, BindingRef{Name: "rbac-admins", Kind: "ClusterRoleBinding"}, []v1.Subject{
+		{Kind: "Group", Name: "system:masters"},
+		{Kind: "User", Name: "alice"},
+	})
+	nsa := NamespacedSubjectAccess{"": sa}
+
+	table := nsa.HighlightedTable([]string{"create", "bind"})
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"alice", "User", "", "*"}, Entries: []printer.Outcome{printer.Up, printer.Up}, Highlight: true},
+		{Intro: []string{"system:masters", "Group", "", "*"}, Entries: []printer.Outcome{printer.Up, printer.Up}},
+	}, table.Rows)
+}
+
+func TestNamespacedSubjectAccess_Merge(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	writer := RoleRef{Name: "writer", Kind: "ClusterRole"}
+	newAccess := func(ns string, role RoleRef, verbs sets.String, subjects ...v1.Subject) *SubjectAccess {
+		sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+		sa.roleToVerbs[role] = verbs
+		kind := "RoleBinding"
+		if ns == "" {
+			kind = "ClusterRoleBinding"
+		}
+		sa.ResolveRoleRef(role, BindingRef{Name: role.Name, Kind: kind, Namespace: ns}, subjects)
+		return sa
+	}
+	alice := v1.Subject{Kind: "User", Name: "alice"}
+	bob := v1.Subject{Kind: "User", Name: "bob"}
+	nsa := NamespacedSubjectAccess{
+		"team-b": newAccess("team-b", writer, sets.NewString("delete"), alice),
+		"team-a": newAccess("team-a", reader, sets.NewString("get"), alice),
+		"":       newAccess("", reader, sets.NewString("get"), bob),
+	}
+
+	merged := nsa.Merge()
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "alice", Kind: "User"}: sets.NewString("get", "delete"),
+		{Name: "bob", Kind: "User"}:   sets.NewString("get"),
+	}, merged.Get())
+	assert.Equal(t, []SubjectRow{
+		{Name: "alice", Kind: "User", Verbs: []string{"get", "delete"}, Namespaces: []string{"team-a", "team-b"}, Roles: []RoleGrant{
+			{Name: "reader", Kind: "ClusterRole", Verbs: []string{"get"}},
+			{Name: "writer", Kind: "ClusterRole", Verbs: []string{"delete"}},
+		}},
+		{Name: "bob", Kind: "User", Verbs: []string{"get"}, ClusterWide: true, Roles: []RoleGrant{{Name: "reader", Kind: "ClusterRole", Verbs: []string{"get"}}}},
+	}, merged.Rows([]string{"get", "delete"}))
+
+	clusterWide, namespaces := merged.Scope(SubjectRef{Name: "alice", Kind: "User"}, []string{"delete"})
+	assert.False(t, clusterWide)
+	assert.Equal(t, []string{"team-b"}, namespaces)
+
+	table := merged.Table([]string{"get"}, TableOptions{Wide: true})
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "VIA-BUILTIN", "SCOPE", "GET"}, table.Headers)
+	assert.Equal(t, []string{"alice", "User", "", "no", "team-a"}, table.Rows[0].Intro)
+	assert.Equal(t, []string{"bob", "User", "", "no", "cluster-wide"}, table.Rows[1].Intro)
+
+	table = merged.Table([]string{"get"}, TableOptions{Scope: true})
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "SCOPE", "GET"}, table.Headers)
+	assert.Equal(t, []string{"alice", "User", "", "team-a"}, table.Rows[0].Intro)
+	assert.Equal(t, []string{"bob", "User", "", "cluster-wide"}, table.Rows[1].Intro)
+}
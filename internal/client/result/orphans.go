@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"sort"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// OrphanSubject is a ServiceAccount subject of a binding, where the
+// ServiceAccount does not exist.
+type OrphanSubject struct {
+	Binding BindingRef
+	Subject SubjectRef
+}
+
+// OrphanSubjects is a list of bindings with missing ServiceAccounts.
+type OrphanSubjects []OrphanSubject
+
+// Sort orders the orphan subjects by binding and subject.
+func (o OrphanSubjects) Sort() {
+	sort.SliceStable(o, func(i, j int) bool {
+		a, b := o[i], o[j]
+		if a.Binding != b.Binding {
+			return formatBinding(a.Binding) < formatBinding(b.Binding)
+		}
+		return formatSubject(a.Subject) < formatSubject(b.Subject)
+	})
+}
+
+// Table renders the orphan subjects, one binding and subject per row.
+func (o OrphanSubjects) Table() *printer.Table {
+	p := printer.TableWithHeaders([]string{"BINDING", "MISSING-SERVICEACCOUNT"})
+	for _, orphan := range o {
+		p.AddRow([]string{formatBinding(orphan.Binding), orphan.Subject.Namespace + "/" + orphan.Subject.Name})
+	}
+	return p
+}
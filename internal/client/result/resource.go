@@ -18,9 +18,11 @@ package result
 
 import (
 	"cmp"
+	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/printer"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -28,8 +30,167 @@ import (
 // ResourceAccess holds the access result for all resources.
 type ResourceAccess map[string]map[string]Access
 
-// Print implements MatrixPrinter.Print. It prints a tab-separated table with a header.
-func (ra ResourceAccess) Table(verbs []string) *printer.Table {
+// RetainMinVerbs removes all resources which allow fewer than n out of the given verbs.
+func (ra ResourceAccess) RetainMinVerbs(verbs []string, n int) {
+	for name, access := range ra {
+		allowed := 0
+		for _, v := range verbs {
+			if access[v] == Allowed {
+				allowed++
+			}
+		}
+		if allowed < n {
+			delete(ra, name)
+		}
+	}
+}
+
+// RetainOnly removes all resources where none of the given verbs has the
+// access a. The remaining resources keep the access of all verbs.
+func (ra ResourceAccess) RetainOnly(verbs []string, a Access) {
+	for name, access := range ra {
+		keep := false
+		for _, v := range verbs {
+			if access[v] == a {
+				keep = true
+				break
+			}
+		}
+		if !keep {
+			delete(ra, name)
+		}
+	}
+}
+
+// RetainApplicable removes all resources which support none of the given
+// verbs. Resources with a denied verb are always kept.
+func (ra ResourceAccess) RetainApplicable(verbs []string) {
+	for name, access := range ra {
+		if !applicable(access, verbs) {
+			delete(ra, name)
+		}
+	}
+}
+
+// ApplicableVerbs returns the given verbs without those which no resource
+// supports, in their original order. Without resources, all verbs are kept.
+func (ra ResourceAccess) ApplicableVerbs(verbs []string) []string {
+	if len(ra) == 0 {
+		return verbs
+	}
+	var ret []string
+	for _, v := range verbs {
+		for _, access := range ra {
+			if access[v] != NotApplicable {
+				ret = append(ret, v)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+func applicable(access map[string]Access, verbs []string) bool {
+	for _, v := range verbs {
+		if access[v] != NotApplicable {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceRow is the structured representation of the access to one resource.
+type ResourceRow struct {
+	Resource string `json:"resource"`
+	APIGroup string `json:"apiGroup"`
+	// Access maps the requested verbs to their access outcome.
+	Access map[string]string `json:"access"`
+	// Permissiveness is the fraction of applicable verbs which are allowed,
+	// ranging from 0 (nothing allowed) to 1 (everything allowed).
+	Permissiveness float64 `json:"permissiveness"`
+	// Risk is the sensitivity level from --risk-tags, if given.
+	Risk string `json:"risk,omitempty"`
+}
+
+// Rows returns the access for the given verbs as structured rows, in the
+// order given by sortBy.
+func (ra ResourceAccess) Rows(verbs []string, sortBy string) []ResourceRow {
+	rows := make([]ResourceRow, 0, len(ra))
+	for _, gr := range ra.sortedGroupResourcesBy(verbs, sortBy) {
+		res := ra[gr.String()]
+		row := ResourceRow{
+			Resource: gr.Resource,
+			APIGroup: gr.Group,
+			Access:   make(map[string]string, len(verbs)),
+		}
+		applicable, allowed := 0, 0
+		for _, v := range verbs {
+			row.Access[v] = res[v].String()
+			if res[v] == NotApplicable {
+				continue
+			}
+			applicable++
+			if res[v] == Allowed {
+				allowed++
+			}
+		}
+		if applicable > 0 {
+			row.Permissiveness = float64(allowed) / float64(applicable)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// RetainAllowedRows drops the verbs of every row which are not allowed, and
+// the rows without any allowed verb, so that only the grants remain. The
+// permissiveness still counts all applicable verbs.
+func RetainAllowedRows(rows []ResourceRow) []ResourceRow {
+	allowed := rows[:0]
+	for _, row := range rows {
+		for v, a := range row.Access {
+			if a != Allowed.String() {
+				delete(row.Access, v)
+			}
+		}
+		if len(row.Access) > 0 {
+			allowed = append(allowed, row)
+		}
+	}
+	return allowed
+}
+
+// AccessCounts are the totals of the access matrix for --report-summary.
+type AccessCounts struct {
+	Resources     int `json:"resources"`
+	Verbs         int `json:"verbs"`
+	Allowed       int `json:"allowed"`
+	Denied        int `json:"denied"`
+	Errors        int `json:"errors"`
+	NotApplicable int `json:"notApplicable"`
+}
+
+// Counts totals the outcomes of the given verbs over all resources.
+func (ra ResourceAccess) Counts(verbs []string) AccessCounts {
+	c := AccessCounts{Resources: len(ra), Verbs: len(verbs)}
+	for _, access := range ra {
+		for _, v := range verbs {
+			switch access[v] {
+			case Allowed:
+				c.Allowed++
+			case Denied:
+				c.Denied++
+			case RequestErr:
+				c.Errors++
+			default:
+				c.NotApplicable++
+			}
+		}
+	}
+	return c
+}
+
+func (ra ResourceAccess) sortedGroupResources() []schema.GroupResource {
 	var groupResources []schema.GroupResource
 	for name := range ra {
 		groupResources = append(groupResources, schema.ParseGroupResource(name))
@@ -43,12 +204,119 @@ func (ra ResourceAccess) Table(verbs []string) *printer.Table {
 		}
 		return cmp.Less(x.Resource, y.Resource)
 	})
+	return groupResources
+}
+
+// sortedGroupResourcesBy orders the resources by API group and resource for
+// SortByGroup, by resource and API group for SortByName, and with the most
+// denied verbs first for SortByAccess. Ties are ordered by name.
+func (ra ResourceAccess) sortedGroupResourcesBy(verbs []string, sortBy string) []schema.GroupResource {
+	groupResources := ra.sortedGroupResources()
+	if sortBy == constants.SortByGroup || sortBy == "" {
+		return groupResources
+	}
+	sort.SliceStable(groupResources, func(i, j int) bool {
+		return cmp.Less(groupResources[i].Resource, groupResources[j].Resource)
+	})
+	if sortBy == constants.SortByAccess {
+		denied := make(map[schema.GroupResource]int, len(groupResources))
+		for _, gr := range groupResources {
+			for _, v := range verbs {
+				if ra[gr.String()][v] == Denied {
+					denied[gr]++
+				}
+			}
+		}
+		sort.SliceStable(groupResources, func(i, j int) bool {
+			return denied[groupResources[i]] > denied[groupResources[j]]
+		})
+	}
+	return groupResources
+}
+
+// Print implements MatrixPrinter.Print. It prints a tab-separated table with a header.
+func (ra ResourceAccess) Table(verbs []string) *printer.Table {
+	return ra.SortedTable(verbs, nil, constants.SortByGroup)
+}
+
+// RiskTable renders the access matrix like Table, with an additional RISK
+// column. Rows of critical resources are highlighted.
+func (ra ResourceAccess) RiskTable(verbs []string, tags RiskTags) *printer.Table {
+	return ra.SortedTable(verbs, tags, constants.SortByGroup)
+}
+
+// SortedTable renders the access matrix like RiskTable, in the order given by
+// sortBy. The tags may be nil to omit the RISK column. Only SortByGroup keeps
+// a section per API group, otherwise the resources are listed with their
+// group in a single section.
+func (ra ResourceAccess) SortedTable(verbs []string, tags RiskTags, sortBy string) *printer.Table {
+	return ra.sortedTable(verbs, tags, sortBy, false)
+}
+
+// SummaryTable renders the access matrix like SortedTable, with an ALLOWED
+// column which counts the allowed out of the applicable verbs of every
+// resource, and a final TOTAL row which counts the resources allowing each verb.
+func (ra ResourceAccess) SummaryTable(verbs []string, tags RiskTags, sortBy string) *printer.Table {
+	p := ra.sortedTable(verbs, tags, sortBy, true)
+	if len(ra) == 0 {
+		return p
+	}
+
+	total := []string{"TOTAL"}
+	var allowed, applicable int
+	for _, v := range verbs {
+		var a, n int
+		for _, access := range ra {
+			if access[v] == NotApplicable {
+				continue
+			}
+			n++
+			if access[v] == Allowed {
+				a++
+			}
+		}
+		total = append(total, fmt.Sprintf("%d/%d", a, n))
+		allowed, applicable = allowed+a, applicable+n
+	}
+	if tags != nil {
+		total = append(total, "")
+	}
+	total = append(total, fmt.Sprintf("%d/%d", allowed, applicable))
+
+	// no separator, so that the counts align with the last section
+	p.AddRow(total)
+	return p
+}
+
+func (ra ResourceAccess) sortedTable(verbs []string, tags RiskTags, sortBy string, summary bool) *printer.Table {
+	groupResources := ra.sortedGroupResourcesBy(verbs, sortBy)
 
 	upperVerbs := make([]string, 0, len(verbs))
 	for _, v := range verbs {
 		upperVerbs = append(upperVerbs, strings.ToUpper(v))
 	}
 
+	if sortBy != constants.SortByGroup && sortBy != "" {
+		headers := append([]string{"NAME"}, upperVerbs...)
+		if tags != nil {
+			headers = append(headers, "RISK")
+		}
+		if summary {
+			headers = append(headers, "ALLOWED")
+		}
+		p := printer.TableWithHeaders(headers)
+		for _, gr := range groupResources {
+			p.AddRow([]string{gr.String()}, accessOutcomes(ra[gr.String()], verbs)...)
+			if tags != nil {
+				addRisk(&p.Rows[len(p.Rows)-1], tags.Level(gr.String()))
+			}
+			if summary {
+				addAllowed(&p.Rows[len(p.Rows)-1], ra[gr.String()], verbs)
+			}
+		}
+		return p
+	}
+
 	p := printer.TableWithHeaders(nil)
 
 	// table body
@@ -66,28 +334,168 @@ func (ra ResourceAccess) Table(verbs []string) *printer.Table {
 				displayGroup = "core"
 			}
 
-			p.AddRow(append([]string{displayGroup + ":"}, upperVerbs...), printer.None)
+			heading := append([]string{displayGroup + ":"}, upperVerbs...)
+			if tags != nil {
+				heading = append(heading, "RISK")
+			}
+			if summary {
+				heading = append(heading, "ALLOWED")
+			}
+			p.AddRow(heading, printer.None)
+			p.Rows[len(p.Rows)-1].Heading = true
 			lastGroup = gr.Group
 		}
 
-		var outcomes []printer.Outcome
+		p.AddRow([]string{gr.Resource}, accessOutcomes(ra[gr.String()], verbs)...)
+		if tags != nil {
+			addRisk(&p.Rows[len(p.Rows)-1], tags.Level(gr.String()))
+		}
+		if summary {
+			addAllowed(&p.Rows[len(p.Rows)-1], ra[gr.String()], verbs)
+		}
+	}
+	return p
+}
+
+// addAllowed counts the allowed out of the applicable verbs at the end of
+// the row.
+func addAllowed(row *printer.Row, access map[string]Access, verbs []string) {
+	var allowed, applicable int
+	for _, v := range verbs {
+		if access[v] == NotApplicable {
+			continue
+		}
+		applicable++
+		if access[v] == Allowed {
+			allowed++
+		}
+	}
+	row.Outro = append(row.Outro, fmt.Sprintf("%d/%d", allowed, applicable))
+}
+
+// addRisk shows the risk level at the end of the row, and highlights the rows
+// of critical resources.
+func addRisk(row *printer.Row, level string) {
+	row.Outro = []string{level}
+	row.Highlight = level == RiskCritical
+}
+
+// NonResourceTable renders the access to non-resource URLs, whose paths are
+// the keys of the ResourceAccess. Unlike Table, paths are not grouped by API group.
+func (ra ResourceAccess) NonResourceTable(verbs []string) *printer.Table {
+	paths := make([]string, 0, len(ra))
+	for path := range ra {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	headers := []string{"PATH"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	p := printer.TableWithHeaders(headers)
+	for _, path := range paths {
+		p.AddRow([]string{path}, accessOutcomes(ra[path], verbs)...)
+	}
+	return p
+}
+
+// CSVHeader returns the header of a CSV matrix, whose first column is named
+// first and which has one column per verb.
+func CSVHeader(first string, verbs []string) []string {
+	return append([]string{first}, verbs...)
+}
+
+// CSVRecords returns one record per resource, in the order given by sortBy,
+// with the access for every verb in the columns of CSVHeader("resource", verbs).
+func (ra ResourceAccess) CSVRecords(verbs []string, sortBy string) [][]string {
+	groupResources := ra.sortedGroupResourcesBy(verbs, sortBy)
+	records := make([][]string, 0, len(groupResources))
+	for _, gr := range groupResources {
+		access := ra[gr.String()]
+		record := []string{gr.String()}
+		for _, v := range verbs {
+			record = append(record, csvValue(access[v]))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// AllowedLines returns one line per resource with the allowed verbs out of the
+// given ones, e.g. "deployments.apps: get,list". Denied verbs are left out, and
+// resources without any allowed verb are listed with an empty verb list.
+func (ra ResourceAccess) AllowedLines(verbs []string) []string {
+	lines := make([]string, 0, len(ra))
+	for name, access := range ra {
+		var allowed []string
+		for _, v := range verbs {
+			if access[v] == Allowed {
+				allowed = append(allowed, v)
+			}
+		}
+		lines = append(lines, strings.TrimSpace(name+": "+strings.Join(allowed, ",")))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// csvValue is the short spelling of the access in CSV cells.
+func csvValue(a Access) string {
+	if a == RequestErr {
+		return "err"
+	}
+	return a.String()
+}
+
+// accessOutcomes converts the access for the given verbs to printer outcomes.
+func accessOutcomes(access map[string]Access, verbs []string) []printer.Outcome {
+	outcomes := make([]printer.Outcome, 0, len(verbs))
+	for _, v := range verbs {
+		var o printer.Outcome
+		switch access[v] {
+		case Denied:
+			o = printer.Down
+		case Allowed:
+			o = printer.Up
+		case NotApplicable:
+			o = printer.None
+		case RequestErr:
+			o = printer.Err
+		}
+		outcomes = append(outcomes, o)
+	}
+	return outcomes
+}
+
+// Tree returns the access as a tree of API groups, resources, and verbs.
+// Verbs which are not applicable to a resource are omitted.
+func (ra ResourceAccess) Tree(verbs []string) *printer.Tree {
+	t := &printer.Tree{}
+	var group *printer.Node
+	lastGroup := ""
+	for i, gr := range ra.sortedGroupResources() {
+		if gr.Group != lastGroup || i == 0 {
+			displayGroup := gr.Group
+			if displayGroup == "" {
+				displayGroup = "core"
+			}
+			group = t.Add(displayGroup)
+			lastGroup = gr.Group
+		}
 
+		resource := group.Add(gr.Resource, printer.None)
 		res := ra[gr.String()]
 		for _, v := range verbs {
-			var o printer.Outcome
 			switch res[v] {
-			case Denied:
-				o = printer.Down
 			case Allowed:
-				o = printer.Up
-			case NotApplicable:
-				o = printer.None
+				resource.Add(v, printer.Up)
+			case Denied:
+				resource.Add(v, printer.Down)
 			case RequestErr:
-				o = printer.Err
+				resource.Add(v, printer.Err)
 			}
-			outcomes = append(outcomes, o)
 		}
-		p.AddRow([]string{gr.Resource}, outcomes...)
 	}
-	return p
+	return t
 }
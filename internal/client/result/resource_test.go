@@ -0,0 +1,278 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceAccess_RetainMinVerbs(t *testing.T) {
+	ra := ResourceAccess{
+		"none":         {"get": Denied, "list": Denied, "delete": Denied},
+		"one":          {"get": Allowed, "list": Denied, "delete": NotApplicable},
+		"two":          {"get": Allowed, "list": Allowed, "delete": Denied},
+		"unrequested":  {"get": Allowed, "list": Denied, "delete": Allowed},
+		"three.apps":   {"get": Allowed, "list": Allowed, "delete": Allowed},
+		"errors.count": {"get": RequestErr, "list": Allowed, "delete": RequestErr},
+	}
+
+	ra.RetainMinVerbs([]string{"get", "list"}, 2)
+
+	assert.Equal(t, ResourceAccess{
+		"two":        {"get": Allowed, "list": Allowed, "delete": Denied},
+		"three.apps": {"get": Allowed, "list": Allowed, "delete": Allowed},
+	}, ra)
+}
+
+func TestResourceAccess_RetainOnly(t *testing.T) {
+	newAccess := func() ResourceAccess {
+		return ResourceAccess{
+			"none":   {"get": Denied, "list": Denied},
+			"mixed":  {"get": Allowed, "list": Denied},
+			"all":    {"get": Allowed, "list": Allowed},
+			"errors": {"get": RequestErr, "list": NotApplicable},
+			"other":  {"get": NotApplicable, "list": Allowed, "delete": Denied},
+		}
+	}
+
+	allowed := newAccess()
+	allowed.RetainOnly([]string{"get", "list"}, Allowed)
+	assert.Equal(t, ResourceAccess{
+		"mixed": {"get": Allowed, "list": Denied},
+		"all":   {"get": Allowed, "list": Allowed},
+		"other": {"get": NotApplicable, "list": Allowed, "delete": Denied},
+	}, allowed)
+
+	denied := newAccess()
+	denied.RetainOnly([]string{"get", "list"}, Denied)
+	assert.Equal(t, ResourceAccess{
+		"none":  {"get": Denied, "list": Denied},
+		"mixed": {"get": Allowed, "list": Denied},
+	}, denied)
+}
+
+func TestResourceAccess_RetainApplicable(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":         {"get": Allowed, "watch": Denied, "proxy": NotApplicable},
+		"tokenreviews": {"get": NotApplicable, "watch": NotApplicable, "proxy": NotApplicable},
+		"bindings":     {"get": NotApplicable, "watch": Denied, "proxy": NotApplicable},
+	}
+	verbs := []string{"get", "watch", "proxy"}
+
+	ra.RetainApplicable(verbs)
+	assert.Equal(t, ResourceAccess{
+		"pods":     {"get": Allowed, "watch": Denied, "proxy": NotApplicable},
+		"bindings": {"get": NotApplicable, "watch": Denied, "proxy": NotApplicable},
+	}, ra)
+	assert.Equal(t, []string{"get", "watch"}, ra.ApplicableVerbs(verbs))
+	assert.Equal(t, verbs, ResourceAccess{}.ApplicableVerbs(verbs))
+}
+
+func TestResourceAccess_Table_columnOrder(t *testing.T) {
+	ra := ResourceAccess{
+		"pods": {"get": Allowed, "list": Denied, "patch": RequestErr, "delete": NotApplicable},
+	}
+
+	table := ra.Table([]string{"patch", "delete", "get", "list"})
+
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"core:", "PATCH", "DELETE", "GET", "LIST"}, Entries: []printer.Outcome{printer.None}, Heading: true},
+		{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Err, printer.None, printer.Up, printer.Down}},
+	}, table.Rows)
+}
+
+func TestResourceAccess_SortedTable(t *testing.T) {
+	ra := ResourceAccess{
+		"deployments.apps": {"get": Denied, "list": Denied},
+		"pods":             {"get": Allowed, "list": Denied},
+		"configmaps":       {"get": Allowed, "list": Allowed},
+		"secrets":          {"get": Denied, "list": Denied},
+	}
+
+	tests := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{sortBy: "name", expected: []string{"configmaps", "deployments.apps", "pods", "secrets"}},
+		{sortBy: "access", expected: []string{"deployments.apps", "secrets", "pods", "configmaps"}},
+	}
+	for _, test := range tests {
+		t.Run(test.sortBy, func(t *testing.T) {
+			table := ra.SortedTable([]string{"get", "list"}, nil, test.sortBy)
+			assert.Equal(t, []string{"NAME", "GET", "LIST"}, table.Headers)
+			var names []string
+			for _, row := range table.Rows {
+				names = append(names, row.Intro[0])
+			}
+			assert.Equal(t, test.expected, names)
+			assert.Equal(t, test.expected[0], ra.CSVRecords([]string{"get", "list"}, test.sortBy)[0][0])
+		})
+	}
+
+	table := ra.SortedTable([]string{"get"}, RiskTags{"secrets": "critical"}, "access")
+	assert.Equal(t, []string{"NAME", "GET", "RISK"}, table.Headers)
+	assert.Equal(t, printer.Row{Intro: []string{"secrets"}, Entries: []printer.Outcome{printer.Down}, Outro: []string{"critical"}, Highlight: true}, table.Rows[1])
+}
+
+func TestResourceAccess_SummaryTable(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":             {"get": Allowed, "list": Denied},
+		"deployments.apps": {"get": Allowed, "list": NotApplicable},
+	}
+
+	table := ra.SummaryTable([]string{"get", "list"}, nil, "")
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"core:", "GET", "LIST", "ALLOWED"}, Entries: []printer.Outcome{printer.None}, Heading: true},
+		{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Up, printer.Down}, Outro: []string{"1/2"}},
+		{Intro: []string{" "}, Entries: []printer.Outcome{printer.None}},
+		{Intro: []string{"apps:", "GET", "LIST", "ALLOWED"}, Entries: []printer.Outcome{printer.None}, Heading: true},
+		{Intro: []string{"deployments"}, Entries: []printer.Outcome{printer.Up, printer.None}, Outro: []string{"1/1"}},
+		{Intro: []string{"TOTAL", "2/2", "0/1", "2/3"}},
+	}, table.Rows)
+
+	table = ra.SummaryTable([]string{"get", "list"}, RiskTags{"pods": "high"}, "name")
+	assert.Equal(t, []string{"NAME", "GET", "LIST", "RISK", "ALLOWED"}, table.Headers)
+	assert.Equal(t, []string{"high", "1/2"}, table.Rows[1].Outro)
+	assert.Equal(t, []string{"TOTAL", "2/2", "0/1", "", "2/3"}, table.Rows[2].Intro)
+}
+
+func TestResourceAccess_NonResourceTable(t *testing.T) {
+	ra := ResourceAccess{
+		"/metrics":                          {"get": Denied, "post": Denied},
+		"/.well-known/openid-configuration": {"get": Allowed, "post": RequestErr},
+		"/healthz":                          {"get": Allowed, "post": Denied},
+	}
+
+	table := ra.NonResourceTable([]string{"get", "post"})
+
+	assert.Equal(t, []string{"PATH", "GET", "POST"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"/.well-known/openid-configuration"}, Entries: []printer.Outcome{printer.Up, printer.Err}},
+		{Intro: []string{"/healthz"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+		{Intro: []string{"/metrics"}, Entries: []printer.Outcome{printer.Down, printer.Down}},
+	}, table.Rows)
+}
+
+func TestResourceAccess_CSVRecords(t *testing.T) {
+	ra := ResourceAccess{
+		"deployments.apps": {"get": Allowed, "list": Denied, "delete": RequestErr},
+		"pods":             {"get": Allowed, "list": Allowed, "delete": NotApplicable},
+	}
+
+	assert.Equal(t, []string{"resource", "get", "list", "delete"}, CSVHeader("resource", []string{"get", "list", "delete"}))
+	assert.Equal(t, [][]string{
+		{"pods", "allowed", "allowed", "n/a"},
+		{"deployments.apps", "allowed", "denied", "err"},
+	}, ra.CSVRecords([]string{"get", "list", "delete"}, ""))
+}
+
+func TestResourceAccess_AllowedLines(t *testing.T) {
+	ra := ResourceAccess{
+		"deployments.apps": {"get": Allowed, "list": Denied, "watch": Allowed, "create": RequestErr},
+		"pods":             {"get": Allowed, "list": Allowed, "watch": Allowed, "create": Allowed},
+		"secrets":          {"get": Denied, "list": Denied, "watch": NotApplicable, "create": Denied},
+	}
+
+	assert.Equal(t, []string{
+		"deployments.apps: get,watch",
+		"pods: get,list,watch,create",
+		"secrets:",
+	}, ra.AllowedLines([]string{"get", "list", "watch", "create"}))
+}
+
+func TestResourceAccess_Rows(t *testing.T) {
+	ra := ResourceAccess{
+		"deployments.apps":                   {"get": Allowed, "list": Denied, "delete": RequestErr},
+		"pods":                               {"get": Allowed, "list": Allowed, "delete": Allowed},
+		"tokenreviews.authentication.k8s.io": {"get": NotApplicable, "list": NotApplicable, "delete": NotApplicable},
+		"bindings":                           {"get": Allowed, "list": NotApplicable, "delete": Denied},
+	}
+
+	rows := ra.Rows([]string{"get", "list", "delete"}, "")
+
+	assert.Equal(t, []ResourceRow{
+		{
+			Resource:       "bindings",
+			Access:         map[string]string{"get": "allowed", "list": "n/a", "delete": "denied"},
+			Permissiveness: 0.5,
+		},
+		{
+			Resource:       "pods",
+			Access:         map[string]string{"get": "allowed", "list": "allowed", "delete": "allowed"},
+			Permissiveness: 1,
+		},
+		{
+			Resource:       "deployments",
+			APIGroup:       "apps",
+			Access:         map[string]string{"get": "allowed", "list": "denied", "delete": "error"},
+			Permissiveness: 1.0 / 3,
+		},
+		{
+			Resource:       "tokenreviews",
+			APIGroup:       "authentication.k8s.io",
+			Access:         map[string]string{"get": "n/a", "list": "n/a", "delete": "n/a"},
+			Permissiveness: 0,
+		},
+	}, rows)
+}
+
+func TestRetainAllowedRows(t *testing.T) {
+	rows := ResourceAccess{
+		"deployments.apps": {"get": Allowed, "list": Denied, "delete": RequestErr},
+		"pods":             {"get": Allowed, "list": Allowed, "delete": NotApplicable},
+		"secrets":          {"get": Denied, "list": Denied, "delete": Denied},
+	}.Rows([]string{"get", "list", "delete"}, "name")
+
+	assert.Equal(t, []ResourceRow{
+		{Resource: "deployments", APIGroup: "apps", Access: map[string]string{"get": "allowed"}, Permissiveness: 1.0 / 3},
+		{Resource: "pods", Access: map[string]string{"get": "allowed", "list": "allowed"}, Permissiveness: 1},
+	}, RetainAllowedRows(rows))
+}
+
+func TestResourceAccess_Counts(t *testing.T) {
+	ra := ResourceAccess{
+		"deployments.apps": {"get": Allowed, "list": Denied, "delete": RequestErr},
+		"pods":             {"get": Allowed, "list": Allowed, "delete": NotApplicable},
+	}
+
+	assert.Equal(t, AccessCounts{Resources: 2, Verbs: 3, Allowed: 3, Denied: 1, Errors: 1, NotApplicable: 1}, ra.Counts([]string{"get", "list", "delete"}))
+	assert.Equal(t, AccessCounts{Verbs: 1}, ResourceAccess{}.Counts([]string{"get"}))
+}
+
+func TestResourceAccess_Tree(t *testing.T) {
+	ra := ResourceAccess{
+		"deployments.apps": {"get": Allowed, "list": RequestErr},
+		"pods":             {"get": Allowed, "list": Denied},
+		"bindings":         {"get": NotApplicable, "list": Allowed},
+	}
+
+	tree := ra.Tree([]string{"get", "list"})
+
+	expected := &printer.Tree{}
+	core := expected.Add("core")
+	core.Add("bindings", printer.None).Add("list", printer.Up)
+	pods := core.Add("pods", printer.None)
+	pods.Add("get", printer.Up)
+	pods.Add("list", printer.Down)
+	deployments := expected.Add("apps").Add("deployments", printer.None)
+	deployments.Add("get", printer.Up)
+	deployments.Add("list", printer.Err)
+	assert.Equal(t, expected, tree)
+}
@@ -25,3 +25,19 @@ const (
 	NotApplicable
 	RequestErr
 )
+
+// String returns a lower-case description of the access, as used in structured output.
+func (a Access) String() string {
+	switch a {
+	case Denied:
+		return "denied"
+	case Allowed:
+		return "allowed"
+	case NotApplicable:
+		return "n/a"
+	case RequestErr:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+const (
+	// RiskCritical is the risk level which is highlighted in tables.
+	RiskCritical = "critical"
+	// RiskUnknown is the risk level of resources without a risk tag.
+	RiskUnknown = "unknown"
+)
+
+// RiskTags maps resources, such as secrets or deployments.apps, to their
+// sensitivity level, such as critical or medium.
+type RiskTags map[string]string
+
+// Level returns the risk level of the resource, or RiskUnknown if the resource
+// is not tagged.
+func (t RiskTags) Level(resource string) string {
+	if level, ok := t[resource]; ok {
+		return level
+	}
+	return RiskUnknown
+}
+
+// AnnotateRows sets the risk level of every row.
+func (t RiskTags) AnnotateRows(rows []ResourceRow) {
+	for i, row := range rows {
+		gr := schema.GroupResource{Group: row.APIGroup, Resource: row.Resource}
+		rows[i].Risk = t.Level(gr.String())
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRiskTags_AnnotateRows(t *testing.T) {
+	tags := RiskTags{"secrets": "critical", "deployments.apps": "high"}
+	rows := []ResourceRow{
+		{Resource: "secrets"},
+		{Resource: "deployments", APIGroup: "apps"},
+		{Resource: "configmaps"},
+	}
+
+	tags.AnnotateRows(rows)
+
+	assert.Equal(t, []string{"critical", "high", "unknown"}, []string{rows[0].Risk, rows[1].Risk, rows[2].Risk})
+}
+
+func TestResourceAccess_RiskTable(t *testing.T) {
+	ra := ResourceAccess{
+		"secrets":          {"list": Allowed},
+		"configmaps":       {"list": Denied},
+		"deployments.apps": {"list": Allowed},
+	}
+
+	table := ra.RiskTable([]string{"list"}, RiskTags{"secrets": "critical", "configmaps": "medium"})
+
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"core:", "LIST", "RISK"}, Entries: []printer.Outcome{printer.None}, Heading: true},
+		{Intro: []string{"configmaps"}, Entries: []printer.Outcome{printer.Down}, Outro: []string{"medium"}},
+		{Intro: []string{"secrets"}, Entries: []printer.Outcome{printer.Up}, Outro: []string{"critical"}, Highlight: true},
+		{Intro: []string{" "}, Entries: []printer.Outcome{printer.None}},
+		{Intro: []string{"apps:", "LIST", "RISK"}, Entries: []printer.Outcome{printer.None}, Heading: true},
+		{Intro: []string{"deployments"}, Entries: []printer.Outcome{printer.Up}, Outro: []string{"unknown"}},
+	}, table.Rows)
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package result
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/corneliusweig/rakkess/internal/constants"
@@ -38,57 +40,669 @@ type SubjectRef struct {
 	Name, Kind, Namespace string
 }
 
+// BindingRef uniquely identifies a RoleBinding or ClusterRoleBinding. The
+// namespace is empty for ClusterRoleBindings.
+type BindingRef struct {
+	Name, Kind, Namespace string
+}
+
 // SubjectAccess holds the access information of all subjects for the given resource.
 type SubjectAccess struct {
 	// GroupResource is the kubernetes GroupResource of this query.
 	GroupResource schema.GroupResource
 	// ResourceName is the name of the kubernetes resource instance of this query.
 	ResourceName string
+	// NonResourceURL is the requested non-resource URL path, such as /metrics.
+	// If set, only the NonResourceURLs of rules are matched.
+	NonResourceURL string
 	// roleToVerbs holds all rule data concerning this resource and is extracted from Roles and ClusterRoles.
 	roleToVerbs map[RoleRef]sets.String
 	// subjectToVerbs holds all subject access data for this resource and is extracted from RoleBindings and ClusterRoleBindings.
 	subjectToVerbs map[SubjectRef]sets.String
+	// subjectToBindings records which (Cluster)RoleBindings grant which verbs to a subject.
+	subjectToBindings map[SubjectRef]map[BindingRef]sets.String
+	// bindingToRole records the role which is referenced by a (Cluster)RoleBinding.
+	bindingToRole map[BindingRef]RoleRef
+	// inherited records which verbs a subject inherits from which groups.
+	inherited map[SubjectRef]map[string]sets.String
+	// direct records the subjects with bindings of their own. It is only set
+	// once groups are collapsed into effective identities.
+	direct map[SubjectRef]bool
+	// Normalize maps equivalent subjects to the same key. If nil, subjects
+	// are only equal if their names match exactly.
+	Normalize SubjectNormalizer
+}
+
+// SubjectNormalizer maps a subject to its canonical form.
+type SubjectNormalizer func(SubjectRef) SubjectRef
+
+// subjectNormalizers are the built-in normalizers. They only change users and
+// groups, because service-account names are always lowercase and have no domain.
+var subjectNormalizers = map[string]func(string) string{
+	constants.NormalizeNone:      func(name string) string { return name },
+	constants.NormalizeLowercase: strings.ToLower,
+	constants.NormalizeStripDomain: func(name string) string {
+		if i := strings.LastIndex(name, "@"); i > 0 {
+			return name[:i]
+		}
+		return name
+	},
+}
+
+// ParseSubjectNormalizer combines the built-in normalizers of the given
+// names, which are applied in order.
+func ParseSubjectNormalizer(names []string) (SubjectNormalizer, error) {
+	var fns []func(string) string
+	for _, name := range names {
+		fn, ok := subjectNormalizers[name]
+		if !ok {
+			return nil, fmt.Errorf("unexpected subject normalizer %q, must be one of %s", name, strings.Join(constants.SubjectNormalizers, ", "))
+		}
+		fns = append(fns, fn)
+	}
+	return func(s SubjectRef) SubjectRef {
+		if s.Kind == v1.ServiceAccountKind {
+			return s
+		}
+		for _, fn := range fns {
+			s.Name = fn(s.Name)
+		}
+		return s
+	}, nil
+}
+
+func (sa *SubjectAccess) normalize(s SubjectRef) SubjectRef {
+	if sa.Normalize == nil {
+		return s
+	}
+	return sa.Normalize(s)
+}
+
+// GroupMembers maps group names to the names of their members. Members with
+// the prefix system:serviceaccount: are service-accounts, all others are users.
+type GroupMembers map[string][]string
+
+// TableOptions control the columns of the subject access table.
+type TableOptions struct {
+	// Wide adds the VIA-BUILTIN and SCOPE columns.
+	Wide bool
+	// SubjectPrefix shows the subject kind as abbreviation or emoji in front
+	// of the name, instead of in the KIND column.
+	SubjectPrefix string
+	// Sources adds the SOURCES column, which tells whether the access of a
+	// subject is bound directly or inherited from groups.
+	Sources bool
+	// Scope adds the SCOPE column without the other wide columns, e.g. when
+	// the RoleBindings of all namespaces are merged.
+	Scope bool
+	// SortBy orders the subjects by name (default) or, for SortByAccess, with
+	// the most denied verbs first.
+	SortBy string
+	// Roles adds the ROLES column, which lists the roles granting the verbs.
+	Roles bool
+	// Baseline adds the EXCEEDS column, which lists the verbs a subject is
+	// granted beyond the verbs of the baseline role. Nil omits the column.
+	Baseline sets.String
+}
+
+// subjectPrefixes maps the subject kinds to their abbreviation and emoji.
+var subjectPrefixes = map[string]map[string]string{
+	constants.SubjectPrefixAbbrev: {
+		v1.UserKind:           "U:",
+		v1.GroupKind:          "G:",
+		v1.ServiceAccountKind: "SA:",
+	},
+	constants.SubjectPrefixEmoji: {
+		v1.UserKind:           "👤 ",
+		v1.GroupKind:          "👥 ",
+		v1.ServiceAccountKind: "🤖 ",
+	},
 }
 
 // NewSubjectAccess creates a new SubjectAccess with initialized fields.
 func NewSubjectAccess(gr schema.GroupResource, resourceName string) *SubjectAccess {
 	return &SubjectAccess{
-		GroupResource:  gr,
-		ResourceName:   resourceName,
-		roleToVerbs:    make(map[RoleRef]sets.String),
-		subjectToVerbs: make(map[SubjectRef]sets.String),
+		GroupResource:     gr,
+		ResourceName:      resourceName,
+		roleToVerbs:       make(map[RoleRef]sets.String),
+		subjectToVerbs:    make(map[SubjectRef]sets.String),
+		subjectToBindings: make(map[SubjectRef]map[BindingRef]sets.String),
+		bindingToRole:     make(map[BindingRef]RoleRef),
+		inherited:         make(map[SubjectRef]map[string]sets.String),
 	}
 }
 
+// NewNonResourceSubjectAccess creates a new SubjectAccess for the given
+// non-resource URL path.
+func NewNonResourceSubjectAccess(path string) *SubjectAccess {
+	sa := NewSubjectAccess(schema.GroupResource{}, "")
+	sa.NonResourceURL = path
+	return sa
+}
+
 // Get provides access to the actual result (for testing).
 func (sa *SubjectAccess) Get() map[SubjectRef]sets.String {
 	return sa.subjectToVerbs
 }
 
+// Bindings returns the (Cluster)RoleBindings which grant access to the given
+// subject, together with the verbs granted by each binding.
+func (sa *SubjectAccess) Bindings(s SubjectRef) map[BindingRef]sets.String {
+	return sa.subjectToBindings[s]
+}
+
+// Tree returns the subject access as a tree of the API group, the resource,
+// the verbs, and the subjects which are granted each verb.
+func (sa *SubjectAccess) Tree(verbs []string) *printer.Tree {
+	displayGroup := sa.GroupResource.Group
+	if displayGroup == "" {
+		displayGroup = "core"
+	}
+	resourceLabel := sa.GroupResource.Resource
+	if sa.ResourceName != "" {
+		resourceLabel = fmt.Sprintf("%s/%s", resourceLabel, sa.ResourceName)
+	}
+	if sa.NonResourceURL != "" {
+		displayGroup, resourceLabel = "nonResourceURLs", sa.NonResourceURL
+	}
+
+	t := &printer.Tree{}
+	resource := t.Add(displayGroup).Add(resourceLabel, printer.None)
+	subjects := sa.Subjects()
+	for _, v := range verbs {
+		verb := resource.Add(v, printer.Down)
+		for _, s := range subjects {
+			if sa.subjectToVerbs[s].Has(v) {
+				verb.Outcome = printer.Up
+				verb.Add(formatSubject(s), printer.None)
+			}
+		}
+	}
+	return t
+}
+
+// MergedColumn is a column in a table which merges several subject access results.
+type MergedColumn struct {
+	Header string
+	Access *SubjectAccess
+	Verb   string
+}
+
+// MergedTable renders a table with one column per (subject access, verb)
+// combination. A subject is listed once, if it has access in any column.
+func MergedTable(columns []MergedColumn) *printer.Table {
+	headers := []string{"NAME", "KIND", "SA-NAMESPACE"}
+	merged := NewSubjectAccess(schema.GroupResource{}, "")
+	for _, c := range columns {
+		headers = append(headers, c.Header)
+		for s, verbs := range c.Access.subjectToVerbs {
+			if verbs.Has(c.Verb) {
+				merged.subjectToVerbs[s] = sets.NewString()
+			}
+		}
+	}
+	p := printer.TableWithHeaders(headers)
+
+	for _, s := range merged.Subjects() {
+		var outcomes []printer.Outcome
+		for _, c := range columns {
+			o := printer.Down
+			if c.Access.subjectToVerbs[s].Has(c.Verb) {
+				o = printer.Up
+			}
+			outcomes = append(outcomes, o)
+		}
+		p.AddRow([]string{s.Name, s.Kind, s.Namespace}, outcomes...)
+	}
+	return p
+}
+
+// SubjectFilter selects subjects by name. An empty Kind or Namespace matches
+// any kind or namespace.
+type SubjectFilter struct {
+	Name, Kind, Namespace string
+}
+
+// subjectKindPrefixes maps the accepted filter prefixes to subject kinds.
+var subjectKindPrefixes = map[string]string{
+	"user":           v1.UserKind,
+	"group":          v1.GroupKind,
+	"sa":             v1.ServiceAccountKind,
+	"serviceaccount": v1.ServiceAccountKind,
+}
+
+// ParseSubjectFilter parses a subject filter of the form [<kind>:]<name>. The
+// kind is one of user, group, or serviceaccount (short sa). Service-accounts
+// may be qualified with a namespace, as in sa:<namespace>:<name>.
+func ParseSubjectFilter(s string) (SubjectFilter, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) == 1 {
+		if s == "" {
+			return SubjectFilter{}, fmt.Errorf("empty subject filter")
+		}
+		return SubjectFilter{Name: s}, nil
+	}
+	kind, ok := subjectKindPrefixes[strings.ToLower(parts[0])]
+	if !ok {
+		return SubjectFilter{}, fmt.Errorf("unexpected subject kind %q, must be one of user, group, serviceaccount", parts[0])
+	}
+	f := SubjectFilter{Kind: kind, Name: parts[1]}
+	if kind == v1.ServiceAccountKind {
+		if nsName := strings.SplitN(parts[1], ":", 2); len(nsName) == 2 {
+			f.Namespace, f.Name = nsName[0], nsName[1]
+		}
+	}
+	if f.Name == "" {
+		return SubjectFilter{}, fmt.Errorf("subject filter %q has no name", s)
+	}
+	return f, nil
+}
+
+// Matches tells whether the subject is selected by the filter.
+func (f SubjectFilter) Matches(s SubjectRef) bool {
+	return f.Name == s.Name &&
+		(f.Kind == "" || f.Kind == s.Kind) &&
+		(f.Namespace == "" || f.Namespace == s.Namespace)
+}
+
+// SubjectKinds are the kinds of subjects in RBAC bindings.
+var SubjectKinds = []string{v1.UserKind, v1.GroupKind, v1.ServiceAccountKind}
+
+// ParseSubjectKinds checks the given subject kinds and returns them in their
+// canonical spelling. Kinds are matched case-insensitively.
+func ParseSubjectKinds(kinds []string) ([]string, error) {
+	parsed := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		var canonical string
+		for _, k := range SubjectKinds {
+			if strings.EqualFold(k, kind) {
+				canonical = k
+			}
+		}
+		if canonical == "" {
+			return nil, fmt.Errorf("unknown subject kind %q, valid kinds are (%s)", kind, strings.Join(SubjectKinds, ", "))
+		}
+		parsed = append(parsed, canonical)
+	}
+	return parsed, nil
+}
+
+// RetainKinds removes all subjects which are not of any of the given kinds.
+func (sa *SubjectAccess) RetainKinds(kinds []string) {
+	retain := sets.NewString(kinds...)
+	sa.filter(func(s SubjectRef, _ sets.String) bool {
+		return retain.Has(s.Kind)
+	})
+}
+
+// RetainServiceAccounts removes all subjects which are not among the given
+// service-accounts, including all users and groups.
+func (sa *SubjectAccess) RetainServiceAccounts(accounts map[SubjectRef]bool) {
+	sa.filter(func(s SubjectRef, _ sets.String) bool {
+		return s.Kind == v1.ServiceAccountKind && accounts[s]
+	})
+}
+
+// String formats the filter in the form which ParseSubjectFilter accepts.
+func (f SubjectFilter) String() string {
+	switch {
+	case f.Kind == "":
+		return f.Name
+	case f.Namespace != "":
+		return fmt.Sprintf("sa:%s:%s", f.Namespace, f.Name)
+	}
+	for prefix, kind := range map[string]string{"user": v1.UserKind, "group": v1.GroupKind, "sa": v1.ServiceAccountKind} {
+		if kind == f.Kind {
+			return prefix + ":" + f.Name
+		}
+	}
+	return f.Name
+}
+
+// RetainSubjects removes all subjects which are not selected by any of the
+// filters. The filter names are normalized like the subjects.
+func (sa *SubjectAccess) RetainSubjects(filters ...SubjectFilter) {
+	normalized := sa.normalizeFilters(filters)
+	sa.filter(func(s SubjectRef, _ sets.String) bool {
+		for _, f := range normalized {
+			if f.Matches(s) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (sa *SubjectAccess) normalizeFilters(filters []SubjectFilter) []SubjectFilter {
+	normalized := make([]SubjectFilter, 0, len(filters))
+	for _, f := range filters {
+		f.Name = sa.normalize(SubjectRef{Name: f.Name, Kind: f.Kind, Namespace: f.Namespace}).Name
+		normalized = append(normalized, f)
+	}
+	return normalized
+}
+
+// Intersect returns the verbs which are granted to every filter. A filter
+// without kind is granted the verbs of all subjects with that name.
+func (sa *SubjectAccess) Intersect(filters ...SubjectFilter) sets.String {
+	var shared sets.String
+	for _, f := range sa.normalizeFilters(filters) {
+		granted := sets.NewString()
+		for s, verbs := range sa.subjectToVerbs {
+			if f.Matches(s) {
+				granted = granted.Union(verbs)
+			}
+		}
+		if shared == nil {
+			shared = granted
+		} else {
+			shared = shared.Intersection(granted)
+		}
+	}
+	if shared == nil {
+		return sets.NewString()
+	}
+	return shared
+}
+
+// IntersectionTable renders a single row with the verbs which are granted to
+// every filter.
+func (sa *SubjectAccess) IntersectionTable(filters []SubjectFilter, verbs []string) *printer.Table {
+	headers := []string{"SUBJECTS"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	p := printer.TableWithHeaders(headers)
+
+	names := make([]string, 0, len(filters))
+	for _, f := range filters {
+		names = append(names, f.String())
+	}
+	p.AddRow([]string{strings.Join(names, ",")}, verbOutcomes(sa.Intersect(filters...), verbs)...)
+	return p
+}
+
+// ExpandGroups grants the access of every group to its members. The members
+// also inherit the bindings of their groups, and the contributing groups are
+// recorded per member.
+func (sa *SubjectAccess) ExpandGroups(members GroupMembers) {
+	normalized := make(GroupMembers, len(members))
+	for g, ms := range members {
+		group := sa.normalize(SubjectRef{Name: g, Kind: v1.GroupKind}).Name
+		normalized[group] = append(normalized[group], ms...)
+	}
+	for s, verbs := range sa.subjectToVerbs {
+		if s.Kind != v1.GroupKind {
+			continue
+		}
+		for _, m := range normalized[s.Name] {
+			member := sa.normalize(memberRef(m))
+			sa.subjectToVerbs[member] = verbs.Union(sa.subjectToVerbs[member])
+
+			bindings, ok := sa.subjectToBindings[member]
+			if !ok {
+				bindings = make(map[BindingRef]sets.String)
+				sa.subjectToBindings[member] = bindings
+			}
+			for b, bVerbs := range sa.subjectToBindings[s] {
+				bindings[b] = bVerbs.Union(bindings[b])
+			}
+
+			groups, ok := sa.inherited[member]
+			if !ok {
+				groups = make(map[string]sets.String)
+				sa.inherited[member] = groups
+			}
+			groups[s.Name] = verbs.Union(groups[s.Name])
+		}
+	}
+}
+
+// EffectiveIdentities collapses every group with known members into the rows
+// of its members, so that each user or service-account shows the union of its
+// own grants and the grants of its groups. Groups without known members are
+// kept, since their members cannot be resolved.
+func (sa *SubjectAccess) EffectiveIdentities(members GroupMembers) {
+	sa.direct = make(map[SubjectRef]bool)
+	for s := range sa.subjectToVerbs {
+		sa.direct[s] = true
+	}
+	sa.ExpandGroups(members)
+
+	resolved := sets.NewString()
+	for g, ms := range members {
+		if len(ms) > 0 {
+			resolved.Insert(sa.normalize(SubjectRef{Name: g, Kind: v1.GroupKind}).Name)
+		}
+	}
+	sa.filter(func(s SubjectRef, _ sets.String) bool {
+		return s.Kind != v1.GroupKind || !resolved.Has(s.Name)
+	})
+}
+
+// sources lists where the access to any of the given verbs comes from, e.g.
+// "direct, group developers".
+func (sa *SubjectAccess) sources(s SubjectRef, verbs []string) string {
+	var sources []string
+	if sa.direct == nil || sa.direct[s] {
+		sources = append(sources, "direct")
+	}
+	inherited := sa.Inherited(s, verbs)
+	groups := make([]string, 0, len(inherited))
+	for g := range inherited {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	for _, g := range groups {
+		sources = append(sources, "group "+g)
+	}
+	return strings.Join(sources, ", ")
+}
+
+// memberRef converts a group member name into a SubjectRef.
+func memberRef(name string) SubjectRef {
+	const saPrefix = "system:serviceaccount:"
+	if strings.HasPrefix(name, saPrefix) {
+		if nsName := strings.SplitN(strings.TrimPrefix(name, saPrefix), ":", 2); len(nsName) == 2 {
+			return SubjectRef{Name: nsName[1], Kind: v1.ServiceAccountKind, Namespace: nsName[0]}
+		}
+	}
+	return SubjectRef{Name: name, Kind: v1.UserKind}
+}
+
+// Inherited returns the groups from which the subject inherits the given
+// verbs, together with the inherited verbs per group.
+func (sa *SubjectAccess) Inherited(s SubjectRef, verbs []string) map[string]sets.String {
+	requested := sets.NewString(verbs...)
+	ret := make(map[string]sets.String)
+	for group, groupVerbs := range sa.inherited[s] {
+		if v := groupVerbs.Intersection(requested); v.Len() > 0 {
+			ret[group] = v
+		}
+	}
+	return ret
+}
+
+// displayName returns the subject name, annotated with the groups which
+// contribute any of the given verbs, e.g. "alice (via group developers [get])".
+func (sa *SubjectAccess) displayName(s SubjectRef, verbs []string) string {
+	inherited := sa.Inherited(s, verbs)
+	if len(inherited) == 0 {
+		return s.Name
+	}
+	groups := make([]string, 0, len(inherited))
+	for g := range inherited {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+
+	vias := make([]string, 0, len(groups))
+	for _, g := range groups {
+		var groupVerbs []string
+		for _, v := range verbs {
+			if inherited[g].Has(v) {
+				groupVerbs = append(groupVerbs, v)
+			}
+		}
+		vias = append(vias, fmt.Sprintf("group %s [%s]", g, strings.Join(groupVerbs, ",")))
+	}
+	return fmt.Sprintf("%s (via %s)", s.Name, strings.Join(vias, ", "))
+}
+
+// ExcludeMasters removes the grants which flow through the group
+// system:masters or through bindings of the ClusterRole cluster-admin.
+// Subjects without any other grants are removed entirely.
+func (sa *SubjectAccess) ExcludeMasters() {
+	clusterAdmin := RoleRef{Name: constants.ClusterAdminRole, Kind: "ClusterRole"}
+	for s, bindings := range sa.subjectToBindings {
+		if s.Kind == v1.GroupKind && s.Name == constants.MastersGroup {
+			continue
+		}
+		delete(sa.inherited[s], constants.MastersGroup)
+		remaining := sets.NewString()
+		for b, verbs := range bindings {
+			if sa.bindingToRole[b] == clusterAdmin {
+				delete(bindings, b)
+				continue
+			}
+			remaining = remaining.Union(verbs)
+		}
+		sa.subjectToVerbs[s] = remaining
+	}
+	sa.filter(func(s SubjectRef, verbs sets.String) bool {
+		return verbs.Len() > 0 && !(s.Kind == v1.GroupKind && s.Name == constants.MastersGroup)
+	})
+}
+
+// RetainMinVerbs removes all subjects which are granted fewer than n out of the given verbs.
+func (sa *SubjectAccess) RetainMinVerbs(verbs []string, n int) {
+	requested := sets.NewString(verbs...)
+	sa.filter(func(_ SubjectRef, granted sets.String) bool {
+		return granted.Intersection(requested).Len() >= n
+	})
+}
+
+// RetainOnly removes all subjects where none of the given verbs has the
+// access a, which is either Allowed or Denied.
+func (sa *SubjectAccess) RetainOnly(verbs []string, a Access) {
+	sa.filter(func(_ SubjectRef, granted sets.String) bool {
+		for _, v := range verbs {
+			if granted.Has(v) == (a == Allowed) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// filter removes all subjects for which keep returns false.
+// RetainAllOf removes all subjects which are not granted every one of the
+// given verbs.
+func (sa *SubjectAccess) RetainAllOf(verbs []string) {
+	sa.filter(func(_ SubjectRef, granted sets.String) bool {
+		return granted.HasAll(verbs...)
+	})
+}
+
+// RetainAnyOf removes all subjects which are granted none of the given verbs.
+func (sa *SubjectAccess) RetainAnyOf(verbs []string) {
+	sa.filter(func(_ SubjectRef, granted sets.String) bool {
+		return granted.HasAny(verbs...)
+	})
+}
+
+func (sa *SubjectAccess) filter(keep func(SubjectRef, sets.String) bool) {
+	for s, verbs := range sa.subjectToVerbs {
+		if !keep(s, verbs) {
+			delete(sa.subjectToVerbs, s)
+			delete(sa.subjectToBindings, s)
+			delete(sa.inherited, s)
+		}
+	}
+}
+
+// Exceeding returns the verbs out of the given ones which the subject is
+// granted, but which the baseline does not include.
+func (sa *SubjectAccess) Exceeding(s SubjectRef, baseline sets.String, verbs []string) []string {
+	var extra []string
+	for _, v := range verbs {
+		if sa.subjectToVerbs[s].Has(v) && !baseline.Has(v) {
+			extra = append(extra, v)
+		}
+	}
+	return extra
+}
+
+// RetainExceeding removes all subjects which are granted none of the given
+// verbs beyond the baseline.
+func (sa *SubjectAccess) RetainExceeding(baseline sets.String, verbs []string) {
+	sa.filter(func(s SubjectRef, _ sets.String) bool {
+		return len(sa.Exceeding(s, baseline, verbs)) > 0
+	})
+}
+
+// ParseRoleRef parses a role in the form clusterrole/<name> or role/<name>.
+// The kind is case-insensitive.
+func ParseRoleRef(s string) (RoleRef, error) {
+	i := strings.Index(s, "/")
+	if i < 0 || i == len(s)-1 {
+		return RoleRef{}, fmt.Errorf("unexpected role %q, must be clusterrole/<name> or role/<name>", s)
+	}
+	switch kind := strings.ToLower(s[:i]); kind {
+	case "clusterrole":
+		return RoleRef{Name: s[i+1:], Kind: "ClusterRole"}, nil
+	case "role":
+		return RoleRef{Name: s[i+1:], Kind: "Role"}, nil
+	}
+	return RoleRef{}, fmt.Errorf("unexpected role %q, must be clusterrole/<name> or role/<name>", s)
+}
+
+// Role returns the role which is referenced by the given (Cluster)RoleBinding.
+func (sa *SubjectAccess) Role(b BindingRef) RoleRef {
+	return sa.bindingToRole[b]
+}
+
+// RoleVerbs returns the verbs which the given role grants on the resource.
+func (sa *SubjectAccess) RoleVerbs(r RoleRef) sets.String {
+	return sets.NewString(sa.roleToVerbs[r].List()...)
+}
+
 // Empty checks if any subjects with access were found.
 func (sa *SubjectAccess) Empty() bool {
 	return len(sa.subjectToVerbs) == 0
 }
 
 // ResolveRoleRef takes a RoleRef and a list of subjects and stores the access
-// rights of the given role for each subject. The RoleRef and subjects usually
-// come from a (Cluster)RoleBinding.
-func (sa *SubjectAccess) ResolveRoleRef(r RoleRef, subjects []v1.Subject) {
+// rights of the given role for each subject. The RoleRef and subjects come
+// from the (Cluster)RoleBinding identified by b.
+func (sa *SubjectAccess) ResolveRoleRef(r RoleRef, b BindingRef, subjects []v1.Subject) {
 	verbsForRole, ok := sa.roleToVerbs[r]
 	if !ok {
 		return
 	}
+	sa.bindingToRole[b] = r
 	for _, subject := range subjects {
-		s := SubjectRef{
+		s := sa.normalize(SubjectRef{
 			Name:      subject.Name,
 			Kind:      subject.Kind,
 			Namespace: subject.Namespace,
-		}
+		})
 		if verbs, ok := sa.subjectToVerbs[s]; ok {
 			sa.subjectToVerbs[s] = verbs.Union(verbsForRole)
 		} else {
 			sa.subjectToVerbs[s] = verbsForRole
 		}
+
+		bindings, ok := sa.subjectToBindings[s]
+		if !ok {
+			bindings = make(map[BindingRef]sets.String)
+			sa.subjectToBindings[s] = bindings
+		}
+		if verbs, ok := bindings[b]; ok {
+			bindings[b] = verbs.Union(verbsForRole)
+		} else {
+			bindings[b] = verbsForRole
+		}
 	}
 }
 
@@ -96,6 +710,13 @@ func (sa *SubjectAccess) ResolveRoleRef(r RoleRef, subjects []v1.Subject) {
 // allowed verbs for the RoleRef, if the sa.resource matches the rule.
 // The RoleRef and rule usually come from a (Cluster)Role.
 func (sa *SubjectAccess) MatchRules(ref RoleRef, rule v1.PolicyRule) {
+	if sa.NonResourceURL != "" {
+		if nonResourceURLMatches(rule.NonResourceURLs, sa.NonResourceURL) {
+			sa.addRoleVerbs(ref, expandNonResource(rule.Verbs))
+		}
+		return
+	}
+
 	if len(rule.ResourceNames) > 0 && !includes(rule.ResourceNames, sa.ResourceName) {
 		return
 	}
@@ -108,14 +729,28 @@ func (sa *SubjectAccess) MatchRules(ref RoleRef, rule v1.PolicyRule) {
 
 	for _, r := range rule.Resources {
 		if r == v1.ResourceAll || r == sa.GroupResource.Resource {
-			expandedVerbs := expand(rule.Verbs)
-			if verbs, ok := sa.roleToVerbs[ref]; ok {
-				sa.roleToVerbs[ref] = sets.NewString(expandedVerbs...).Union(verbs)
-			} else {
-				sa.roleToVerbs[ref] = sets.NewString(expandedVerbs...)
-			}
+			sa.addRoleVerbs(ref, expand(rule.Verbs))
+		}
+	}
+}
+
+func (sa *SubjectAccess) addRoleVerbs(ref RoleRef, expandedVerbs []string) {
+	if verbs, ok := sa.roleToVerbs[ref]; ok {
+		sa.roleToVerbs[ref] = sets.NewString(expandedVerbs...).Union(verbs)
+	} else {
+		sa.roleToVerbs[ref] = sets.NewString(expandedVerbs...)
+	}
+}
+
+// nonResourceURLMatches tells whether any of the rule entries matches the path.
+// As in RBAC, an entry with a trailing * matches all paths with that prefix.
+func nonResourceURLMatches(entries []string, path string) bool {
+	for _, entry := range entries {
+		if entry == path || strings.HasSuffix(entry, "*") && strings.HasPrefix(path, strings.TrimSuffix(entry, "*")) {
+			return true
 		}
 	}
+	return false
 }
 
 func apiGroupMatches(entries []string, target string) bool {
@@ -142,29 +777,278 @@ func includes(coll []string, x string) bool {
 func expand(verbs []string) []string {
 	for _, verb := range verbs {
 		if verb == v1.VerbAll {
-			return constants.ValidVerbs
+			return append(append([]string{}, constants.ValidVerbs...), constants.SpecialVerbs...)
+		}
+	}
+	return verbs
+}
+
+func expandNonResource(verbs []string) []string {
+	for _, verb := range verbs {
+		if verb == v1.VerbAll {
+			return append([]string{}, constants.NonResourceVerbs...)
 		}
 	}
 	return verbs
 }
 
-func (sa *SubjectAccess) Table(verbs []string) *printer.Table {
+// Subjects returns all subjects with access, sorted by name, kind, and
+// namespace, so that service-accounts of the same name are kept apart in a
+// stable order.
+func (sa *SubjectAccess) Subjects() []SubjectRef {
 	subjects := make([]SubjectRef, 0, len(sa.subjectToVerbs))
 	for s := range sa.subjectToVerbs {
 		subjects = append(subjects, s)
 	}
 	sort.Slice(subjects, func(i, j int) bool {
-		comp := strings.Compare(subjects[i].Name, subjects[j].Name)
-		if comp == 0 {
-			return subjects[i].Kind < subjects[j].Kind
+		x, y := subjects[i], subjects[j]
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		return x.Namespace < y.Namespace
+	})
+	return subjects
+}
+
+// Scope determines how far the given verbs reach for the subject. It is
+// cluster-wide if any ClusterRoleBinding grants any of the verbs. Otherwise,
+// it is the sorted list of namespaces whose RoleBindings grant any of them.
+func (sa *SubjectAccess) Scope(s SubjectRef, verbs []string) (clusterWide bool, namespaces []string) {
+	ns := sets.NewString()
+	for b, granted := range sa.subjectToBindings[s] {
+		if !granted.HasAny(verbs...) {
+			continue
+		}
+		if b.Namespace == "" {
+			return true, nil
+		}
+		ns.Insert(b.Namespace)
+	}
+	return false, ns.List()
+}
+
+// scopeString formats the scope of the subject, e.g. "cluster-wide" or
+// "team-a,team-b".
+func (sa *SubjectAccess) scopeString(s SubjectRef, verbs []string) string {
+	clusterWide, namespaces := sa.Scope(s, verbs)
+	if clusterWide {
+		return "cluster-wide"
+	}
+	return strings.Join(namespaces, ",")
+}
+
+// SubjectRow is the structured form of the access of a single subject.
+type SubjectRow struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Namespace is only set for service-accounts.
+	Namespace string `json:"namespace,omitempty"`
+	// Verbs are the granted verbs out of the requested ones.
+	Verbs []string `json:"verbs"`
+	// ClusterWide tells whether any of the verbs is granted in all namespaces.
+	ClusterWide bool `json:"clusterWide"`
+	// Namespaces are the namespaces in which any of the verbs is granted, if
+	// the access is not cluster-wide.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Roles are the (Cluster)Roles which grant the verbs.
+	Roles []RoleGrant `json:"roles,omitempty"`
+	// Exceeds are the verbs which go beyond the baseline role of --exceeds.
+	Exceeds []string `json:"exceeds,omitempty"`
+}
+
+// RoleGrant is a (Cluster)Role with the verbs which it grants to a subject.
+type RoleGrant struct {
+	Name  string   `json:"name"`
+	Kind  string   `json:"kind"`
+	Verbs []string `json:"verbs"`
+}
+
+// String formats the grant as kind/name(verbs), e.g. ClusterRole/edit(get,list).
+func (g RoleGrant) String() string {
+	return fmt.Sprintf("%s/%s(%s)", g.Kind, g.Name, strings.Join(g.Verbs, ","))
+}
+
+// Rows returns the access of every subject which is granted any of the verbs,
+// sorted by subject.
+func (sa *SubjectAccess) Rows(verbs []string) []SubjectRow {
+	rows := []SubjectRow{}
+	for _, s := range sa.Subjects() {
+		valid := sa.subjectToVerbs[s]
+		if !valid.HasAny(verbs...) {
+			continue
+		}
+		var granted []string
+		for _, v := range verbs {
+			if valid.Has(v) {
+				granted = append(granted, v)
+			}
+		}
+		clusterWide, namespaces := sa.Scope(s, verbs)
+		rows = append(rows, SubjectRow{
+			Name:        s.Name,
+			Kind:        s.Kind,
+			Namespace:   s.Namespace,
+			Verbs:       granted,
+			ClusterWide: clusterWide,
+			Namespaces:  namespaces,
+			Roles:       sa.Roles(s, verbs),
+		})
+	}
+	return rows
+}
+
+// ViaBuiltin tells whether the given verbs are granted to the subject through
+// built-in ClusterRoles. It is "yes" if all granted verbs come from built-in
+// roles, "partial" if some verbs are only granted by custom roles, and "no" if
+// no built-in role is involved.
+func (sa *SubjectAccess) ViaBuiltin(s SubjectRef, verbs []string) string {
+	requested := sets.NewString(verbs...)
+	builtin, custom := sets.NewString(), sets.NewString()
+	for b, granted := range sa.subjectToBindings[s] {
+		if isBuiltinRole(sa.bindingToRole[b]) {
+			builtin = builtin.Union(granted.Intersection(requested))
+		} else {
+			custom = custom.Union(granted.Intersection(requested))
+		}
+	}
+
+	switch {
+	case builtin.Len() == 0:
+		return "no"
+	case custom.Difference(builtin).Len() == 0:
+		return "yes"
+	default:
+		return "partial"
+	}
+}
+
+// Roles returns the (Cluster)Roles which grant any of the verbs to the
+// subject, sorted by kind and name. Each role lists the verbs which it grants,
+// in the order of the given verbs.
+func (sa *SubjectAccess) Roles(s SubjectRef, verbs []string) []RoleGrant {
+	granted := make(map[RoleRef]sets.String)
+	for b, bVerbs := range sa.subjectToBindings[s] {
+		r := sa.bindingToRole[b]
+		granted[r] = bVerbs.Union(granted[r])
+	}
+
+	var grants []RoleGrant
+	for r, rVerbs := range granted {
+		var vs []string
+		for _, v := range verbs {
+			if rVerbs.Has(v) {
+				vs = append(vs, v)
+			}
+		}
+		if len(vs) > 0 {
+			grants = append(grants, RoleGrant{Name: r.Name, Kind: r.Kind, Verbs: vs})
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].Kind != grants[j].Kind {
+			return grants[i].Kind < grants[j].Kind
+		}
+		return grants[i].Name < grants[j].Name
+	})
+	return grants
+}
+
+// rolesString formats the roles which grant any of the verbs to the subject,
+// e.g. "ClusterRole/edit(get,list),Role/deployer(create)".
+func (sa *SubjectAccess) rolesString(s SubjectRef, verbs []string) string {
+	var roles []string
+	for _, g := range sa.Roles(s, verbs) {
+		roles = append(roles, g.String())
+	}
+	return strings.Join(roles, ",")
+}
+
+// ProvenanceTable lists the bindings which grant any of the verbs to the
+// subject, with the bound role and the verbs which each binding grants. The
+// namespace of ClusterRoleBindings is shown as *.
+func (sa *SubjectAccess) ProvenanceTable(s SubjectRef, verbs []string) *printer.Table {
+	headers := []string{"BINDING", "NAMESPACE", "ROLE"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	p := printer.TableWithHeaders(headers)
+
+	bindings := sa.subjectToBindings[s]
+	refs := make([]BindingRef, 0, len(bindings))
+	for b, granted := range bindings {
+		if granted.HasAny(verbs...) {
+			refs = append(refs, b)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Namespace != refs[j].Namespace {
+			return refs[i].Namespace < refs[j].Namespace
 		}
-		return comp < 0
+		if refs[i].Kind != refs[j].Kind {
+			return refs[i].Kind < refs[j].Kind
+		}
+		return refs[i].Name < refs[j].Name
 	})
 
+	for _, b := range refs {
+		namespace := b.Namespace
+		if namespace == "" {
+			namespace = "*"
+		}
+		role := sa.bindingToRole[b]
+		p.AddRow([]string{b.Kind + "/" + b.Name, namespace, role.Kind + "/" + role.Name}, verbOutcomes(bindings[b], verbs)...)
+	}
+	return p
+}
+
+func isBuiltinRole(r RoleRef) bool {
+	if r.Kind != "ClusterRole" {
+		return false
+	}
+	if strings.HasPrefix(r.Name, constants.SystemRolePrefix) {
+		return true
+	}
+	for _, name := range constants.BuiltinClusterRoles {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (sa *SubjectAccess) Table(verbs []string, opts TableOptions) *printer.Table {
+	subjects := sa.Subjects()
+	if opts.SortBy == constants.SortByAccess {
+		sort.SliceStable(subjects, func(i, j int) bool {
+			return sa.denied(subjects[i], verbs) > sa.denied(subjects[j], verbs)
+		})
+	}
+
+	prefixes, prefixed := subjectPrefixes[opts.SubjectPrefix]
 	headers := []string{"NAME", "KIND", "SA-NAMESPACE"}
+	if prefixed {
+		headers = []string{"NAME", "SA-NAMESPACE"}
+	}
+	if opts.Sources {
+		headers = append(headers, "SOURCES")
+	}
+	if opts.Wide {
+		headers = append(headers, "VIA-BUILTIN", "SCOPE")
+	} else if opts.Scope {
+		headers = append(headers, "SCOPE")
+	}
 	for _, v := range verbs {
 		headers = append(headers, strings.ToUpper(v))
 	}
+	if opts.Baseline != nil {
+		headers = append(headers, "EXCEEDS")
+	}
+	if opts.Roles {
+		headers = append(headers, "ROLES")
+	}
 	p := printer.TableWithHeaders(headers)
 
 	// table body
@@ -173,17 +1057,138 @@ func (sa *SubjectAccess) Table(verbs []string) *printer.Table {
 		if !valid.HasAny(verbs...) {
 			continue
 		}
-		var outcomes []printer.Outcome
+		name := s.Name
+		if opts.Wide {
+			name = sa.displayName(s, verbs)
+		}
+		intro := []string{name, s.Kind, s.Namespace}
+		if prefixed {
+			intro = []string{prefixes[s.Kind] + name, s.Namespace}
+		}
+		if opts.Sources {
+			intro = append(intro, sa.sources(s, verbs))
+		}
+		if opts.Wide {
+			intro = append(intro, sa.ViaBuiltin(s, verbs), sa.scopeString(s, verbs))
+		} else if opts.Scope {
+			intro = append(intro, sa.scopeString(s, verbs))
+		}
+		p.AddRow(intro, verbOutcomes(valid, verbs)...)
+		var outro []string
+		if opts.Baseline != nil {
+			outro = append(outro, strings.Join(sa.Exceeding(s, opts.Baseline, verbs), ","))
+		}
+		if opts.Roles {
+			outro = append(outro, sa.rolesString(s, verbs))
+		}
+		p.Rows[len(p.Rows)-1].Outro = outro
+	}
+
+	return p
+}
+
+// denied counts the given verbs which the subject is not granted.
+func (sa *SubjectAccess) denied(s SubjectRef, verbs []string) int {
+	n := 0
+	for _, v := range verbs {
+		if !sa.subjectToVerbs[s].Has(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// Lines returns one sorted line per grant of the given verbs, in the form
+// "subject<TAB>kind<TAB>verb<TAB>group/resource<TAB>namespace". The group is
+// empty for the core API group, and the resource name is appended as
+// group/resource/name. The namespace is the one of the granting RoleBinding,
+// or * for ClusterRoleBindings. Service-accounts are qualified as
+// namespace:name.
+func (sa *SubjectAccess) Lines(verbs []string) []string {
+	resource := sa.NonResourceURL
+	if resource == "" {
+		resource = sa.GroupResource.Group + "/" + sa.GroupResource.Resource
+		if sa.ResourceName != "" {
+			resource += "/" + sa.ResourceName
+		}
+	}
+
+	lines := sets.NewString()
+	for s, bindings := range sa.subjectToBindings {
+		name := s.Name
+		if s.Kind == v1.ServiceAccountKind {
+			name = s.Namespace + ":" + s.Name
+		}
+		for b, granted := range bindings {
+			namespace := b.Namespace
+			if namespace == "" {
+				namespace = "*"
+			}
+			for _, v := range verbs {
+				if granted.Has(v) {
+					lines.Insert(strings.Join([]string{name, s.Kind, v, resource, namespace}, "\t"))
+				}
+			}
+		}
+	}
+	return lines.List()
+}
+
+// CSVLongHeader is the header of the records of LongRecords.
+var CSVLongHeader = []string{"subject", "kind", "namespace", "resource", "group", "verb", "allowed"}
+
+// LongRecords returns one record per subject and verb, in the columns of
+// CSVLongHeader. The namespace is the namespace of service-accounts. This
+// normalized format is preferred by BI tools over a wide matrix.
+func (sa *SubjectAccess) LongRecords(verbs []string) [][]string {
+	resource, group := sa.NonResourceURL, ""
+	if resource == "" {
+		resource, group = sa.GroupResource.Resource, sa.GroupResource.Group
+		if sa.ResourceName != "" {
+			resource += "/" + sa.ResourceName
+		}
+	}
+
+	var records [][]string
+	for _, s := range sa.Subjects() {
+		granted := sa.subjectToVerbs[s]
 		for _, v := range verbs {
-			o := printer.Down
-			if valid.Has(v) {
-				o = printer.Up
+			records = append(records, []string{s.Name, s.Kind, s.Namespace, resource, group, v, strconv.FormatBool(granted.Has(v))})
+		}
+	}
+	return records
+}
+
+// CSVRecords returns one record per subject, in the order of Table, with
+// allowed or denied for every verb in the columns of CSVHeader("subject", verbs).
+// Subjects are written as user:<name>, group:<name>, or sa:<namespace>:<name>.
+func (sa *SubjectAccess) CSVRecords(verbs []string) [][]string {
+	subjects := sa.Subjects()
+	records := make([][]string, 0, len(subjects))
+	for _, s := range subjects {
+		granted := sa.subjectToVerbs[s]
+		record := []string{SubjectFilter(s).String()}
+		for _, v := range verbs {
+			a := Denied
+			if granted.Has(v) {
+				a = Allowed
 			}
-			outcomes = append(outcomes, o)
+			record = append(record, a.String())
 		}
-		intro := []string{s.Name, s.Kind, s.Namespace}
-		p.AddRow(intro, outcomes...)
+		records = append(records, record)
 	}
+	return records
+}
 
-	return p
+// verbOutcomes marks the valid verbs as allowed and all others as denied.
+func verbOutcomes(valid sets.String, verbs []string) []printer.Outcome {
+	outcomes := make([]printer.Outcome, 0, len(verbs))
+	for _, v := range verbs {
+		o := printer.Down
+		if valid.Has(v) {
+			o = printer.Up
+		}
+		outcomes = append(outcomes, o)
+	}
+	return outcomes
 }
@@ -17,10 +17,13 @@ limitations under the License.
 package result
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/printer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -83,7 +86,7 @@ func TestSubjectAccess_MatchRules(t *testing.T) {
 				Resources: []string{resource},
 				Verbs:     []string{v1.VerbAll},
 			},
-			expectedVerbs: constants.ValidVerbs,
+			expectedVerbs: append(append([]string{}, constants.ValidVerbs...), constants.SpecialVerbs...),
 		},
 		{
 			name: "simple rule with resourceNames does not match",
@@ -158,13 +161,19 @@ func TestSubjectAccess_ResolveRoleRef(t *testing.T) {
 		Name: "some-role",
 		Kind: "some-kind",
 	}
+	b := BindingRef{
+		Name:      "some-binding",
+		Kind:      "RoleBinding",
+		Namespace: "some-ns",
+	}
 	subject := "main"
 	mainSubject := SubjectRef{Name: subject, Kind: "some-kind", Namespace: "some-ns"}
 	tests := []struct {
-		name          string
-		verbsForRole  []string
-		subjects      []string
-		expectedVerbs []string
+		name             string
+		verbsForRole     []string
+		subjects         []string
+		expectedVerbs    []string
+		expectedBindings map[BindingRef]sets.String
 	}{
 		{
 			name:          "no role",
@@ -172,16 +181,18 @@ func TestSubjectAccess_ResolveRoleRef(t *testing.T) {
 			expectedVerbs: []string{"initial-verb"},
 		},
 		{
-			name:          "match with one subject",
-			verbsForRole:  []string{"get", "list"},
-			subjects:      []string{subject},
-			expectedVerbs: []string{"initial-verb", "get", "list"},
+			name:             "match with one subject",
+			verbsForRole:     []string{"get", "list"},
+			subjects:         []string{subject},
+			expectedVerbs:    []string{"initial-verb", "get", "list"},
+			expectedBindings: map[BindingRef]sets.String{b: sets.NewString("get", "list")},
 		},
 		{
-			name:          "match with multiple subject",
-			verbsForRole:  []string{"get", "list"},
-			subjects:      []string{"other", subject, "yet-another"},
-			expectedVerbs: []string{"initial-verb", "get", "list"},
+			name:             "match with multiple subject",
+			verbsForRole:     []string{"get", "list"},
+			subjects:         []string{"other", subject, "yet-another"},
+			expectedVerbs:    []string{"initial-verb", "get", "list"},
+			expectedBindings: map[BindingRef]sets.String{b: sets.NewString("get", "list")},
 		},
 		{
 			name:          "no match with other subjects",
@@ -194,8 +205,10 @@ func TestSubjectAccess_ResolveRoleRef(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			sa := SubjectAccess{
-				subjectToVerbs: map[SubjectRef]sets.String{mainSubject: sets.NewString("initial-verb")},
-				roleToVerbs:    make(map[RoleRef]sets.String),
+				subjectToVerbs:    map[SubjectRef]sets.String{mainSubject: sets.NewString("initial-verb")},
+				subjectToBindings: make(map[SubjectRef]map[BindingRef]sets.String),
+				bindingToRole:     make(map[BindingRef]RoleRef),
+				roleToVerbs:       make(map[RoleRef]sets.String),
 			}
 			if test.verbsForRole != nil {
 				sa.roleToVerbs[r] = sets.NewString(test.verbsForRole...)
@@ -209,9 +222,823 @@ func TestSubjectAccess_ResolveRoleRef(t *testing.T) {
 					Namespace: "some-ns",
 				})
 			}
-			sa.ResolveRoleRef(r, subjects)
+			sa.ResolveRoleRef(r, b, subjects)
 
 			assert.Equal(t, sets.NewString(test.expectedVerbs...), sa.subjectToVerbs[mainSubject])
+			assert.Equal(t, test.expectedBindings, sa.Bindings(mainSubject))
+		})
+	}
+}
+
+func TestSubjectAccess_ViaBuiltin(t *testing.T) {
+	subject := SubjectRef{Name: "main", Kind: "User"}
+	builtin := RoleRef{Name: "edit", Kind: "ClusterRole"}
+	system := RoleRef{Name: "system:controller:foo", Kind: "ClusterRole"}
+	custom := RoleRef{Name: "custom", Kind: "ClusterRole"}
+	namedLikeBuiltin := RoleRef{Name: "edit", Kind: "Role"}
+
+	tests := []struct {
+		name     string
+		grants   map[RoleRef][]string
+		verbs    []string
+		expected string
+	}{
+		{
+			name:     "only built-in",
+			grants:   map[RoleRef][]string{builtin: {"get", "list"}},
+			verbs:    []string{"get", "list"},
+			expected: "yes",
+		},
+		{
+			name:     "system role",
+			grants:   map[RoleRef][]string{system: {"get"}},
+			verbs:    []string{"get"},
+			expected: "yes",
+		},
+		{
+			name:     "only custom",
+			grants:   map[RoleRef][]string{custom: {"get"}},
+			verbs:    []string{"get"},
+			expected: "no",
+		},
+		{
+			name:     "namespaced role with built-in name",
+			grants:   map[RoleRef][]string{namedLikeBuiltin: {"get"}},
+			verbs:    []string{"get"},
+			expected: "no",
+		},
+		{
+			name:     "custom role adds verbs",
+			grants:   map[RoleRef][]string{builtin: {"get"}, custom: {"delete"}},
+			verbs:    []string{"get", "delete"},
+			expected: "partial",
+		},
+		{
+			name:     "custom role duplicates built-in verbs",
+			grants:   map[RoleRef][]string{builtin: {"get", "delete"}, custom: {"delete"}},
+			verbs:    []string{"get", "delete"},
+			expected: "yes",
+		},
+		{
+			name:     "custom role verbs are not requested",
+			grants:   map[RoleRef][]string{builtin: {"get"}, custom: {"delete"}},
+			verbs:    []string{"get"},
+			expected: "yes",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sa := NewSubjectAccess(schema.GroupResource{Resource: "configmaps"}, "")
+			for role, verbs := range test.grants {
+				sa.roleToVerbs[role] = sets.NewString(verbs...)
+				b := BindingRef{Name: role.Name + "-binding", Kind: "ClusterRoleBinding"}
+				sa.ResolveRoleRef(role, b, []v1.Subject{{Name: subject.Name, Kind: subject.Kind}})
+			}
+
+			assert.Equal(t, test.expected, sa.ViaBuiltin(subject, test.verbs))
+		})
+	}
+}
+
+func TestSubjectAccess_RetainMinVerbs(t *testing.T) {
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "configmaps"}, "")
+	for name, verbs := range map[string][]string{
+		"one":         {"get"},
+		"two":         {"get", "list"},
+		"unrequested": {"get", "delete", "patch"},
+	} {
+		r := RoleRef{Name: name, Kind: "ClusterRole"}
+		sa.roleToVerbs[r] = sets.NewString(verbs...)
+		sa.ResolveRoleRef(r, BindingRef{Name: name, Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: name, Kind: "User"}})
+	}
+
+	sa.RetainMinVerbs([]string{"get", "list"}, 2)
+
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "two", Kind: "User"}: sets.NewString("get", "list"),
+	}, sa.Get())
+	assert.Nil(t, sa.Bindings(SubjectRef{Name: "one", Kind: "User"}))
+}
+
+func TestSubjectAccess_RetainAllOfAnyOf(t *testing.T) {
+	newAccess := func() *SubjectAccess {
+		sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+		for name, verbs := range map[string][]string{
+			"reader":  {"get", "list"},
+			"cleaner": {"delete"},
+			"admin":   {"get", "list", "delete"},
+		} {
+			r := RoleRef{Name: name, Kind: "ClusterRole"}
+			sa.roleToVerbs[r] = sets.NewString(verbs...)
+			sa.ResolveRoleRef(r, BindingRef{Name: name, Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: name, Kind: "User"}})
+		}
+		return sa
+	}
+
+	allOf := newAccess()
+	allOf.RetainAllOf([]string{"get", "delete"})
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "admin", Kind: "User"}: sets.NewString("get", "list", "delete"),
+	}, allOf.Get())
+
+	anyOf := newAccess()
+	anyOf.RetainAnyOf([]string{"delete", "patch"})
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "cleaner", Kind: "User"}: sets.NewString("delete"),
+		{Name: "admin", Kind: "User"}:   sets.NewString("get", "list", "delete"),
+	}, anyOf.Get())
+}
+
+func TestMergedTable(t *testing.T) {
+	user := SubjectRef{Name: "main", Kind: "User"}
+	sa := SubjectRef{Name: "robot", Kind: "ServiceAccount", Namespace: "ns"}
+	nobody := SubjectRef{Name: "nobody", Kind: "User"}
+
+	reviews := NewSubjectAccess(schema.GroupResource{Resource: "subjectaccessreviews"}, "")
+	reviews.subjectToVerbs[user] = sets.NewString("create")
+	reviews.subjectToVerbs[nobody] = sets.NewString("get")
+	users := NewSubjectAccess(schema.GroupResource{Resource: "users"}, "")
+	users.subjectToVerbs[sa] = sets.NewString("impersonate")
+	users.subjectToVerbs[user] = sets.NewString("impersonate")
+
+	table := MergedTable([]MergedColumn{
+		{Header: "CREATE-SAR", Access: reviews, Verb: "create"},
+		{Header: "IMPERSONATE-USERS", Access: users, Verb: "impersonate"},
+	})
+
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "CREATE-SAR", "IMPERSONATE-USERS"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"main", "User", ""}, Entries: []printer.Outcome{printer.Up, printer.Up}},
+		{Intro: []string{"robot", "ServiceAccount", "ns"}, Entries: []printer.Outcome{printer.Down, printer.Up}},
+	}, table.Rows)
+}
+
+func TestParseSubjectFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected SubjectFilter
+		err      string
+	}{
+		{
+			name:     "name only",
+			input:    "developers",
+			expected: SubjectFilter{Name: "developers"},
+		},
+		{
+			name:     "group",
+			input:    "group:developers",
+			expected: SubjectFilter{Name: "developers", Kind: "Group"},
+		},
+		{
+			name:     "user with colon in name",
+			input:    "user:system:kube-scheduler",
+			expected: SubjectFilter{Name: "system:kube-scheduler", Kind: "User"},
+		},
+		{
+			name:     "service-account without namespace",
+			input:    "sa:default",
+			expected: SubjectFilter{Name: "default", Kind: "ServiceAccount"},
+		},
+		{
+			name:     "qualified service-account",
+			input:    "serviceaccount:kube-system:default",
+			expected: SubjectFilter{Name: "default", Kind: "ServiceAccount", Namespace: "kube-system"},
+		},
+		{
+			name:  "unknown kind",
+			input: "robot:r2d2",
+			err:   `unexpected subject kind "robot", must be one of user, group, serviceaccount`,
+		},
+		{
+			name:  "missing name",
+			input: "group:",
+			err:   `subject filter "group:" has no name`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseSubjectFilter(test.input)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestSubjectAccess_RetainSubjects(t *testing.T) {
+	group := SubjectRef{Name: "developers", Kind: "Group"}
+	user := SubjectRef{Name: "developers", Kind: "User"}
+	sa := SubjectRef{Name: "developers", Kind: "ServiceAccount", Namespace: "ci"}
+
+	tests := []struct {
+		name     string
+		filter   SubjectFilter
+		expected []SubjectRef
+	}{
+		{
+			name:     "any kind",
+			filter:   SubjectFilter{Name: "developers"},
+			expected: []SubjectRef{group, sa, user},
+		},
+		{
+			name:     "only group",
+			filter:   SubjectFilter{Name: "developers", Kind: "Group"},
+			expected: []SubjectRef{group},
+		},
+		{
+			name:     "service-account in other namespace",
+			filter:   SubjectFilter{Name: "developers", Kind: "ServiceAccount", Namespace: "default"},
+			expected: []SubjectRef{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sa := &SubjectAccess{subjectToVerbs: map[SubjectRef]sets.String{
+				group: sets.NewString("get"),
+				user:  sets.NewString("get"),
+				sa:    sets.NewString("get"),
+			}}
+			sa.RetainSubjects(test.filter)
+			assert.Equal(t, test.expected, sa.Subjects())
+		})
+	}
+}
+
+func TestSubjectAccess_RetainSubjects_multipleFilters(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	bob := SubjectRef{Name: "bob", Kind: "User"}
+	devs := SubjectRef{Name: "devs", Kind: "Group"}
+
+	sa := &SubjectAccess{subjectToVerbs: map[SubjectRef]sets.String{
+		alice: sets.NewString("get"),
+		bob:   sets.NewString("get"),
+		devs:  sets.NewString("get"),
+	}}
+	sa.RetainSubjects(SubjectFilter{Name: "alice", Kind: "User"}, SubjectFilter{Name: "devs"})
+	assert.Equal(t, []SubjectRef{alice, devs}, sa.Subjects())
+}
+
+func TestParseSubjectKinds(t *testing.T) {
+	kinds, err := ParseSubjectKinds([]string{"user", "ServiceAccount"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"User", "ServiceAccount"}, kinds)
+
+	_, err = ParseSubjectKinds([]string{"Robot"})
+	assert.EqualError(t, err, `unknown subject kind "Robot", valid kinds are (User, Group, ServiceAccount)`)
+}
+
+func TestSubjectAccess_RetainKinds(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	devs := SubjectRef{Name: "devs", Kind: "Group"}
+	ci := SubjectRef{Name: "ci", Kind: "ServiceAccount", Namespace: "ci"}
+
+	sa := &SubjectAccess{subjectToVerbs: map[SubjectRef]sets.String{
+		alice: sets.NewString("get"),
+		devs:  sets.NewString("get"),
+		ci:    sets.NewString("get"),
+	}}
+	sa.RetainKinds([]string{"User", "ServiceAccount"})
+	assert.Equal(t, []SubjectRef{alice, ci}, sa.Subjects())
+}
+
+func TestSubjectAccess_Intersect(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	aliceGroup := SubjectRef{Name: "alice", Kind: "Group"}
+	bob := SubjectRef{Name: "bob", Kind: "User"}
+
+	tests := []struct {
+		name     string
+		filters  []SubjectFilter
+		expected []string
+	}{
+		{
+			name:     "shared verbs",
+			filters:  []SubjectFilter{{Name: "alice", Kind: "User"}, {Name: "bob", Kind: "User"}},
+			expected: []string{"get"},
+		},
+		{
+			name:     "filter without kind merges all kinds",
+			filters:  []SubjectFilter{{Name: "alice"}, {Name: "bob", Kind: "User"}},
+			expected: []string{"get", "list"},
+		},
+		{
+			name:     "unknown subject",
+			filters:  []SubjectFilter{{Name: "alice", Kind: "User"}, {Name: "carol", Kind: "User"}},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sa := &SubjectAccess{subjectToVerbs: map[SubjectRef]sets.String{
+				alice:      sets.NewString("get", "create"),
+				aliceGroup: sets.NewString("list"),
+				bob:        sets.NewString("get", "list", "delete"),
+			}}
+			assert.Equal(t, test.expected, sa.Intersect(test.filters...).List())
 		})
 	}
 }
+
+func TestSubjectAccess_IntersectionTable(t *testing.T) {
+	sa := &SubjectAccess{subjectToVerbs: map[SubjectRef]sets.String{
+		{Name: "alice", Kind: "User"}:                               sets.NewString("get", "create"),
+		{Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}: sets.NewString("get", "list"),
+	}}
+	filters := []SubjectFilter{{Name: "alice", Kind: "User"}, {Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}}
+
+	table := sa.IntersectionTable(filters, []string{"get", "list"})
+
+	assert.Equal(t, []string{"SUBJECTS", "GET", "LIST"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"user:alice,sa:ci:deployer"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+	}, table.Rows)
+}
+
+func TestSubjectAccess_ExcludeMasters(t *testing.T) {
+	masters := SubjectRef{Name: "system:masters", Kind: "Group"}
+	admin := SubjectRef{Name: "admin", Kind: "User"}
+	dev := SubjectRef{Name: "dev", Kind: "User"}
+
+	clusterAdmin := RoleRef{Name: "cluster-admin", Kind: "ClusterRole"}
+	editor := RoleRef{Name: "editor", Kind: "ClusterRole"}
+	localAdmin := RoleRef{Name: "cluster-admin", Kind: "Role"}
+
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[clusterAdmin] = sets.NewString("get", "delete")
+	sa.roleToVerbs[editor] = sets.NewString("get")
+	sa.roleToVerbs[localAdmin] = sets.NewString("list")
+	sa.ResolveRoleRef(clusterAdmin, BindingRef{Name: "cluster-admin", Kind: "ClusterRoleBinding"}, []v1.Subject{
+		{Name: masters.Name, Kind: masters.Kind},
+		{Name: admin.Name, Kind: admin.Kind},
+		{Name: dev.Name, Kind: dev.Kind},
+	})
+	sa.ResolveRoleRef(editor, BindingRef{Name: "editors", Kind: "ClusterRoleBinding"}, []v1.Subject{
+		{Name: masters.Name, Kind: masters.Kind},
+		{Name: dev.Name, Kind: dev.Kind},
+	})
+	sa.ResolveRoleRef(localAdmin, BindingRef{Name: "local", Kind: "RoleBinding", Namespace: "ns"}, []v1.Subject{
+		{Name: dev.Name, Kind: dev.Kind},
+	})
+
+	sa.ExcludeMasters()
+
+	assert.Equal(t, map[SubjectRef]sets.String{
+		dev: sets.NewString("get", "list"),
+	}, sa.Get())
+	assert.Equal(t, map[BindingRef]sets.String{
+		{Name: "editors", Kind: "ClusterRoleBinding"}:         sets.NewString("get"),
+		{Name: "local", Kind: "RoleBinding", Namespace: "ns"}: sets.NewString("list"),
+	}, sa.Bindings(dev))
+}
+
+func TestSubjectAccess_Tree(t *testing.T) {
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "db-password")
+	sa.subjectToVerbs[SubjectRef{Name: "alice", Kind: "User"}] = sets.NewString("get", "list")
+	sa.subjectToVerbs[SubjectRef{Name: "ci", Kind: "ServiceAccount", Namespace: "build"}] = sets.NewString("get")
+
+	tree := sa.Tree([]string{"get", "list", "delete"})
+
+	expected := &printer.Tree{}
+	secret := expected.Add("core").Add("secrets/db-password", printer.None)
+	get := secret.Add("get", printer.Up)
+	get.Add("User/alice", printer.None)
+	get.Add("ServiceAccount/build/ci", printer.None)
+	secret.Add("list", printer.Up).Add("User/alice", printer.None)
+	secret.Add("delete", printer.Down)
+	assert.Equal(t, expected, tree)
+}
+
+func TestSubjectAccess_MatchRules_nonResourceURLs(t *testing.T) {
+	role := RoleRef{Name: "role", Kind: "ClusterRole"}
+
+	tests := []struct {
+		name     string
+		path     string
+		rule     v1.PolicyRule
+		expected map[RoleRef]sets.String
+	}{
+		{
+			name:     "exact match",
+			path:     "/metrics",
+			rule:     v1.PolicyRule{NonResourceURLs: []string{"/healthz", "/metrics"}, Verbs: []string{"get"}},
+			expected: map[RoleRef]sets.String{role: sets.NewString("get")},
+		},
+		{
+			name:     "no match",
+			path:     "/metrics",
+			rule:     v1.PolicyRule{NonResourceURLs: []string{"/metrics/cadvisor"}, Verbs: []string{"get"}},
+			expected: map[RoleRef]sets.String{},
+		},
+		{
+			name:     "prefix wildcard",
+			path:     "/metrics/cadvisor",
+			rule:     v1.PolicyRule{NonResourceURLs: []string{"/metrics*"}, Verbs: []string{"get"}},
+			expected: map[RoleRef]sets.String{role: sets.NewString("get")},
+		},
+		{
+			name:     "wildcard does not match other prefix",
+			path:     "/healthz",
+			rule:     v1.PolicyRule{NonResourceURLs: []string{"/metrics*"}, Verbs: []string{"get"}},
+			expected: map[RoleRef]sets.String{},
+		},
+		{
+			name:     "all URLs and verbs",
+			path:     "/version",
+			rule:     v1.PolicyRule{NonResourceURLs: []string{"*"}, Verbs: []string{"*"}},
+			expected: map[RoleRef]sets.String{role: sets.NewString(constants.NonResourceVerbs...)},
+		},
+		{
+			name:     "resource rule is ignored",
+			path:     "/metrics",
+			rule:     v1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			expected: map[RoleRef]sets.String{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sa := NewNonResourceSubjectAccess(test.path)
+			sa.MatchRules(role, test.rule)
+			assert.Equal(t, test.expected, sa.roleToVerbs)
+		})
+	}
+}
+
+func TestSubjectAccess_ExpandGroups(t *testing.T) {
+	developers := RoleRef{Name: "developer", Kind: "ClusterRole"}
+	ops := RoleRef{Name: "ops", Kind: "ClusterRole"}
+	direct := RoleRef{Name: "reader", Kind: "ClusterRole"}
+
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[developers] = sets.NewString("get", "list")
+	sa.roleToVerbs[ops] = sets.NewString("delete")
+	sa.roleToVerbs[direct] = sets.NewString("get")
+	devBinding := BindingRef{Name: "developers", Kind: "ClusterRoleBinding"}
+	sa.ResolveRoleRef(developers, devBinding, []v1.Subject{{Name: "developers", Kind: "Group"}})
+	sa.ResolveRoleRef(ops, BindingRef{Name: "ops", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "ops", Kind: "Group"}})
+	sa.ResolveRoleRef(direct, BindingRef{Name: "alice", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "alice", Kind: "User"}})
+
+	sa.ExpandGroups(GroupMembers{
+		"developers": {"alice", "system:serviceaccount:ci:deployer"},
+		"ops":        {"alice"},
+		"unbound":    {"bob"},
+	})
+
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	deployer := SubjectRef{Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "developers", Kind: "Group"}: sets.NewString("get", "list"),
+		{Name: "ops", Kind: "Group"}:        sets.NewString("delete"),
+		alice:                               sets.NewString("get", "list", "delete"),
+		deployer:                            sets.NewString("get", "list"),
+	}, sa.Get())
+	assert.Equal(t, sets.NewString("get", "list"), sa.Bindings(deployer)[devBinding])
+	assert.Equal(t, map[string]sets.String{
+		"developers": sets.NewString("get"),
+		"ops":        sets.NewString("delete"),
+	}, sa.Inherited(alice, []string{"get", "delete"}))
+
+	table := sa.Table([]string{"get", "delete"}, TableOptions{Wide: true})
+	assert.Equal(t, []string{"alice (via group developers [get], group ops [delete])", "User", "", "no", "cluster-wide"}, table.Rows[0].Intro)
+	assert.Equal(t, []string{"deployer (via group developers [get])", "ServiceAccount", "ci", "no", "cluster-wide"}, table.Rows[1].Intro)
+}
+
+func TestSubjectAccess_EffectiveIdentities(t *testing.T) {
+	developers := RoleRef{Name: "developer", Kind: "ClusterRole"}
+	direct := RoleRef{Name: "reader", Kind: "ClusterRole"}
+
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[developers] = sets.NewString("list")
+	sa.roleToVerbs[direct] = sets.NewString("get")
+	sa.ResolveRoleRef(developers, BindingRef{Name: "developers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "developers", Kind: "Group"}, {Name: "external", Kind: "Group"}})
+	sa.ResolveRoleRef(direct, BindingRef{Name: "alice", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "alice", Kind: "User"}})
+
+	sa.EffectiveIdentities(GroupMembers{"developers": {"alice", "bob"}})
+
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "external", Kind: "Group"}: sets.NewString("list"),
+		{Name: "alice", Kind: "User"}:     sets.NewString("get", "list"),
+		{Name: "bob", Kind: "User"}:       sets.NewString("list"),
+	}, sa.Get())
+
+	table := sa.Table([]string{"get", "list"}, TableOptions{Sources: true})
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "SOURCES", "GET", "LIST"}, table.Headers)
+	assert.Equal(t, []string{"alice", "User", "", "direct, group developers"}, table.Rows[0].Intro)
+	assert.Equal(t, []string{"bob", "User", "", "group developers"}, table.Rows[1].Intro)
+	assert.Equal(t, []string{"external", "Group", "", "direct"}, table.Rows[2].Intro)
+}
+
+func TestSubjectAccess_Lines(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Group: "apps", Resource: "deployments"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get", "list")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "alice", Kind: "User"}})
+	sa.ResolveRoleRef(reader, BindingRef{Name: "ci", Kind: "RoleBinding", Namespace: "ci"}, []v1.Subject{{Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}})
+
+	assert.Equal(t, []string{
+		"alice\tUser\tget\tapps/deployments\t*",
+		"ci:deployer\tServiceAccount\tget\tapps/deployments\tci",
+	}, sa.Lines([]string{"get", "delete"}))
+
+	core := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "token")
+	core.roleToVerbs[reader] = sets.NewString("get")
+	core.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "ops", Kind: "Group"}})
+	assert.Equal(t, []string{"ops\tGroup\tget\t/secrets/token\t*"}, core.Lines([]string{"get"}))
+}
+
+func TestSubjectAccess_LongRecords(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Group: "apps", Resource: "deployments"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get", "list")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "alice", Kind: "User"}})
+	sa.ResolveRoleRef(reader, BindingRef{Name: "ci", Kind: "RoleBinding", Namespace: "ci"}, []v1.Subject{{Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}})
+
+	assert.Equal(t, [][]string{
+		{"alice", "User", "", "deployments", "apps", "get", "true"},
+		{"alice", "User", "", "deployments", "apps", "delete", "false"},
+		{"deployer", "ServiceAccount", "ci", "deployments", "apps", "get", "true"},
+		{"deployer", "ServiceAccount", "ci", "deployments", "apps", "delete", "false"},
+	}, sa.LongRecords([]string{"get", "delete"}))
+
+	metrics := NewSubjectAccess(schema.GroupResource{}, "")
+	metrics.NonResourceURL = "/metrics"
+	metrics.roleToVerbs[reader] = sets.NewString("get")
+	metrics.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "prometheus", Kind: "User"}})
+	assert.Equal(t, [][]string{{"prometheus", "User", "", "/metrics", "", "get", "true"}}, metrics.LongRecords([]string{"get"}))
+}
+
+func TestSubjectAccess_CSVRecords(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get", "list")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "alice", Kind: "User"}})
+	sa.ResolveRoleRef(reader, BindingRef{Name: "ci", Kind: "RoleBinding", Namespace: "ci"}, []v1.Subject{{Name: "deployer", Kind: "ServiceAccount", Namespace: "ci"}})
+
+	assert.Equal(t, [][]string{
+		{"user:alice", "allowed", "denied"},
+		{"sa:ci:deployer", "allowed", "denied"},
+	}, sa.CSVRecords([]string{"get", "delete"}))
+}
+
+func TestParseSubjectNormalizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		subject  SubjectRef
+		expected string
+	}{
+		{
+			name:     "none",
+			input:    []string{"none"},
+			subject:  SubjectRef{Name: "Alice@corp", Kind: "User"},
+			expected: "Alice@corp",
+		},
+		{
+			name:     "lowercase",
+			input:    []string{"lowercase"},
+			subject:  SubjectRef{Name: "Alice@Corp", Kind: "User"},
+			expected: "alice@corp",
+		},
+		{
+			name:     "strip-domain",
+			input:    []string{"strip-domain"},
+			subject:  SubjectRef{Name: "Alice@corp", Kind: "User"},
+			expected: "Alice",
+		},
+		{
+			name:     "combined",
+			input:    []string{"lowercase", "strip-domain"},
+			subject:  SubjectRef{Name: "Developers@corp", Kind: "Group"},
+			expected: "developers",
+		},
+		{
+			name:     "service-accounts are unchanged",
+			input:    []string{"lowercase"},
+			subject:  SubjectRef{Name: "Deployer", Kind: "ServiceAccount", Namespace: "ci"},
+			expected: "Deployer",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalize, err := ParseSubjectNormalizer(test.input)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, normalize(test.subject).Name)
+		})
+	}
+
+	_, err := ParseSubjectNormalizer([]string{"uppercase"})
+	assert.EqualError(t, err, `unexpected subject normalizer "uppercase", must be one of none, lowercase, strip-domain`)
+}
+
+func TestSubjectAccess_ResolveRoleRef_normalized(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	writer := RoleRef{Name: "writer", Kind: "ClusterRole"}
+
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.Normalize, _ = ParseSubjectNormalizer([]string{"lowercase", "strip-domain"})
+	sa.roleToVerbs[reader] = sets.NewString("get")
+	sa.roleToVerbs[writer] = sets.NewString("update")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "a", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "Alice", Kind: "User"}})
+	sa.ResolveRoleRef(writer, BindingRef{Name: "b", Kind: "ClusterRoleBinding"}, []v1.Subject{{Name: "alice@corp", Kind: "User"}})
+
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	assert.Equal(t, map[SubjectRef]sets.String{alice: sets.NewString("get", "update")}, sa.Get())
+	assert.Len(t, sa.Bindings(alice), 2)
+
+	sa.RetainSubjects(SubjectFilter{Name: "ALICE@corp"})
+	assert.Equal(t, []SubjectRef{alice}, sa.Subjects())
+}
+
+func TestSubjectAccess_RetainExceeding(t *testing.T) {
+	admin := RoleRef{Name: "admin", Kind: "ClusterRole"}
+	edit := RoleRef{Name: "edit", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[admin] = sets.NewString("get", "list", "delete", "escalate")
+	sa.roleToVerbs[edit] = sets.NewString("get", "list")
+	sa.ResolveRoleRef(admin, BindingRef{Name: "admins", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "alice"}})
+	sa.ResolveRoleRef(edit, BindingRef{Name: "editors", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "bob"}})
+
+	baseline := sets.NewString("get", "list")
+	verbs := []string{"get", "list", "delete"}
+	sa.RetainExceeding(baseline, verbs)
+	assert.Equal(t, map[SubjectRef]sets.String{
+		{Name: "alice", Kind: "User"}: sets.NewString("get", "list", "delete", "escalate"),
+	}, sa.Get())
+	assert.Equal(t, []string{"delete"}, sa.Exceeding(SubjectRef{Name: "alice", Kind: "User"}, baseline, verbs))
+
+	table := sa.Table(verbs, TableOptions{Baseline: baseline, Roles: true})
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "GET", "LIST", "DELETE", "EXCEEDS", "ROLES"}, table.Headers)
+	assert.Equal(t, []string{"delete", "ClusterRole/admin(get,list,delete)"}, table.Rows[0].Outro)
+}
+
+func TestParseRoleRef(t *testing.T) {
+	r, err := ParseRoleRef("clusterrole/edit")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleRef{Name: "edit", Kind: "ClusterRole"}, r)
+	r, err = ParseRoleRef("Role/deployer")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleRef{Name: "deployer", Kind: "Role"}, r)
+
+	for _, s := range []string{"edit", "clusterrole/", "rolebinding/edit"} {
+		_, err := ParseRoleRef(s)
+		assert.EqualError(t, err, fmt.Sprintf("unexpected role %q, must be clusterrole/<name> or role/<name>", s))
+	}
+}
+
+func TestSubjectAccess_ProvenanceTable(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	editor := RoleRef{Name: "editor", Kind: "Role"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get", "list")
+	sa.roleToVerbs[editor] = sets.NewString("update")
+	alice := v1.Subject{Kind: "User", Name: "alice"}
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{alice})
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "RoleBinding", Namespace: "prod"}, []v1.Subject{alice})
+	sa.ResolveRoleRef(editor, BindingRef{Name: "editors", Kind: "RoleBinding", Namespace: "dev"}, []v1.Subject{alice})
+
+	table := sa.ProvenanceTable(SubjectRef{Name: "alice", Kind: "User"}, []string{"get", "update"})
+	assert.Equal(t, []string{"BINDING", "NAMESPACE", "ROLE", "GET", "UPDATE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"ClusterRoleBinding/readers", "*", "ClusterRole/reader"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+		{Intro: []string{"RoleBinding/editors", "dev", "Role/editor"}, Entries: []printer.Outcome{printer.Down, printer.Up}},
+		{Intro: []string{"RoleBinding/readers", "prod", "ClusterRole/reader"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+	}, table.Rows)
+
+	assert.Empty(t, sa.ProvenanceTable(SubjectRef{Name: "alice", Kind: "User"}, []string{"delete"}).Rows)
+}
+
+func TestSubjectAccess_Roles(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	editor := RoleRef{Name: "editor", Kind: "Role"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get", "list")
+	sa.roleToVerbs[editor] = sets.NewString("get", "update")
+	alice := v1.Subject{Kind: "User", Name: "alice"}
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{alice})
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "RoleBinding", Namespace: "prod"}, []v1.Subject{alice})
+	sa.ResolveRoleRef(editor, BindingRef{Name: "editors", Kind: "RoleBinding", Namespace: "dev"}, []v1.Subject{alice})
+
+	verbs := []string{"update", "get"}
+	assert.Equal(t, []RoleGrant{
+		{Name: "reader", Kind: "ClusterRole", Verbs: []string{"get"}},
+		{Name: "editor", Kind: "Role", Verbs: []string{"update", "get"}},
+	}, sa.Roles(SubjectRef{Name: "alice", Kind: "User"}, verbs))
+	assert.Equal(t, sa.Roles(SubjectRef{Name: "alice", Kind: "User"}, verbs), sa.Rows(verbs)[0].Roles)
+
+	table := sa.Table(verbs, TableOptions{Roles: true})
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "UPDATE", "GET", "ROLES"}, table.Headers)
+	assert.Equal(t, []string{"ClusterRole/reader(get),Role/editor(update,get)"}, table.Rows[0].Outro)
+
+	assert.Empty(t, sa.Roles(SubjectRef{Name: "alice", Kind: "User"}, []string{"delete"}))
+}
+
+func TestSubjectAccess_Table_subjectPrefix(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{
+		{Kind: "User", Name: "alice"},
+		{Kind: "Group", Name: "devs"},
+		{Kind: "ServiceAccount", Name: "ci", Namespace: "build"},
+	})
+
+	tests := []struct {
+		prefix   string
+		headers  []string
+		expected [][]string
+	}{
+		{
+			prefix:   "column",
+			headers:  []string{"NAME", "KIND", "SA-NAMESPACE", "GET"},
+			expected: [][]string{{"alice", "User", ""}, {"ci", "ServiceAccount", "build"}, {"devs", "Group", ""}},
+		},
+		{
+			prefix:   "abbrev",
+			headers:  []string{"NAME", "SA-NAMESPACE", "GET"},
+			expected: [][]string{{"U:alice", ""}, {"SA:ci", "build"}, {"G:devs", ""}},
+		},
+		{
+			prefix:   "emoji",
+			headers:  []string{"NAME", "SA-NAMESPACE", "GET"},
+			expected: [][]string{{"👤 alice", ""}, {"🤖 ci", "build"}, {"👥 devs", ""}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.prefix, func(t *testing.T) {
+			table := sa.Table([]string{"get"}, TableOptions{SubjectPrefix: test.prefix})
+			assert.Equal(t, test.headers, table.Headers)
+			var intros [][]string
+			for _, row := range table.Rows {
+				intros = append(intros, row.Intro)
+			}
+			assert.Equal(t, test.expected, intros)
+		})
+	}
+}
+
+func TestSubjectAccess_Table_sortByAccess(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	admin := RoleRef{Name: "admin", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get")
+	sa.roleToVerbs[admin] = sets.NewString("get", "delete")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "carol"}, {Kind: "User", Name: "alice"}})
+	sa.ResolveRoleRef(admin, BindingRef{Name: "admins", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "bob"}})
+
+	var names []string
+	for _, row := range sa.Table([]string{"get", "delete"}, TableOptions{SortBy: "access"}).Rows {
+		names = append(names, row.Intro[0])
+	}
+	assert.Equal(t, []string{"alice", "carol", "bob"}, names)
+}
+
+func TestSubjectAccess_serviceAccountsOfSameName(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	sa.roleToVerbs[reader] = sets.NewString("get")
+	sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{
+		{Kind: "ServiceAccount", Name: "default", Namespace: "team-b"},
+		{Kind: "ServiceAccount", Name: "default", Namespace: "team-a"},
+		{Kind: "User", Name: "default"},
+	})
+
+	assert.Equal(t, []SubjectRef{
+		{Name: "default", Kind: "ServiceAccount", Namespace: "team-a"},
+		{Name: "default", Kind: "ServiceAccount", Namespace: "team-b"},
+		{Name: "default", Kind: "User"},
+	}, sa.Subjects())
+
+	table := sa.Table([]string{"get"}, TableOptions{})
+	assert.Equal(t, []string{"default", "ServiceAccount", "team-a"}, table.Rows[0].Intro)
+	assert.Equal(t, []string{"default", "ServiceAccount", "team-b"}, table.Rows[1].Intro)
+	assert.Equal(t, []string{"default", "User", ""}, table.Rows[2].Intro)
+}
+
+func TestSubjectAccess_RetainOnly(t *testing.T) {
+	reader := RoleRef{Name: "reader", Kind: "ClusterRole"}
+	admin := RoleRef{Name: "admin", Kind: "ClusterRole"}
+	newAccess := func() *SubjectAccess {
+		sa := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+		sa.roleToVerbs[reader] = sets.NewString("get")
+		sa.roleToVerbs[admin] = sets.NewString("get", "delete")
+		sa.ResolveRoleRef(reader, BindingRef{Name: "readers", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "alice"}})
+		sa.ResolveRoleRef(admin, BindingRef{Name: "admins", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "bob"}})
+		return sa
+	}
+
+	denied := newAccess()
+	denied.RetainOnly([]string{"get", "delete"}, Denied)
+	assert.Equal(t, []SubjectRef{{Name: "alice", Kind: "User"}}, denied.Subjects())
+
+	allowed := newAccess()
+	allowed.RetainOnly([]string{"delete"}, Allowed)
+	assert.Equal(t, []SubjectRef{{Name: "bob", Kind: "User"}}, allowed.Subjects())
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package result
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// VerbWeights rate how much privilege a verb grants. Reading weighs least,
+// modifying more, and the verbs which escalate privileges most. Verbs without
+// a weight do not count.
+var VerbWeights = map[string]int{
+	"get":              1,
+	"list":             2,
+	"watch":            2,
+	"create":           3,
+	"update":           3,
+	"patch":            3,
+	"delete":           3,
+	"deletecollection": 4,
+	"bind":             5,
+	"escalate":         5,
+	"impersonate":      5,
+}
+
+// SubjectScore is the privilege score of a subject over several resources.
+type SubjectScore struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Namespace is only set for service-accounts.
+	Namespace string `json:"namespace,omitempty"`
+	// Score is the sum of the weights of the granted verbs on all resources.
+	Score int `json:"score"`
+	// Resources are the resources on which the subject is granted any verb.
+	Resources []string `json:"resources"`
+}
+
+// ScoreSubjects sums the weights of the verbs which every subject is granted
+// on the resources of the given subject accesses. The scores are sorted with
+// the most privileged subject first, and by subject for equal scores.
+func ScoreSubjects(accesses []*SubjectAccess) []SubjectScore {
+	scores := make(map[SubjectRef]*SubjectScore)
+	for _, sa := range accesses {
+		for s, granted := range sa.subjectToVerbs {
+			points := 0
+			for _, v := range granted.List() {
+				points += VerbWeights[v]
+			}
+			if points == 0 {
+				continue
+			}
+			score, ok := scores[s]
+			if !ok {
+				score = &SubjectScore{Name: s.Name, Kind: s.Kind, Namespace: s.Namespace}
+				scores[s] = score
+			}
+			score.Score += points
+			score.Resources = append(score.Resources, sa.GroupResource.String())
+		}
+	}
+
+	ranked := make([]SubjectScore, 0, len(scores))
+	for _, score := range scores {
+		sort.Strings(score.Resources)
+		ranked = append(ranked, *score)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return ranked
+}
+
+// SubjectScoresTable renders the ranked subjects as a leaderboard.
+func SubjectScoresTable(scores []SubjectScore) *printer.Table {
+	p := printer.TableWithHeaders([]string{"RANK", "NAME", "KIND", "SA-NAMESPACE", "SCORE", "RESOURCES"})
+	for i, s := range scores {
+		p.AddRow([]string{strconv.Itoa(i + 1), s.Name, s.Kind, s.Namespace, strconv.Itoa(s.Score), strings.Join(s.Resources, ",")})
+	}
+	return p
+}
+
+// SubjectScoresCSVHeader is the header of SubjectScoresCSVRecords.
+var SubjectScoresCSVHeader = []string{"rank", "name", "kind", "namespace", "score", "resources"}
+
+// SubjectScoresCSVRecords returns one CSV record per ranked subject, in the
+// columns of SubjectScoresCSVHeader.
+func SubjectScoresCSVRecords(scores []SubjectScore) [][]string {
+	records := make([][]string, 0, len(scores))
+	for i, s := range scores {
+		records = append(records, []string{strconv.Itoa(i + 1), s.Name, s.Kind, s.Namespace, strconv.Itoa(s.Score), strings.Join(s.Resources, " ")})
+	}
+	return records
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package result
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestScoreSubjects(t *testing.T) {
+	alice := SubjectRef{Name: "alice", Kind: "User"}
+	bob := SubjectRef{Name: "bob", Kind: "User"}
+	ci := SubjectRef{Name: "ci", Kind: "ServiceAccount", Namespace: "build"}
+	secrets := NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	secrets.subjectToVerbs[alice] = sets.NewString("get", "list")
+	secrets.subjectToVerbs[ci] = sets.NewString("get", "list", "delete")
+	deployments := NewSubjectAccess(schema.GroupResource{Group: "apps", Resource: "deployments"}, "")
+	deployments.subjectToVerbs[alice] = sets.NewString("get")
+	deployments.subjectToVerbs[bob] = sets.NewString("list", "get")
+	deployments.subjectToVerbs[ci] = sets.NewString("approve")
+
+	scores := ScoreSubjects([]*SubjectAccess{secrets, deployments})
+	assert.Equal(t, []SubjectScore{
+		{Name: "ci", Kind: "ServiceAccount", Namespace: "build", Score: 6, Resources: []string{"secrets"}},
+		{Name: "alice", Kind: "User", Score: 4, Resources: []string{"deployments.apps", "secrets"}},
+		{Name: "bob", Kind: "User", Score: 3, Resources: []string{"deployments.apps"}},
+	}, scores)
+
+	table := SubjectScoresTable(scores[:2])
+	assert.Equal(t, []string{"RANK", "NAME", "KIND", "SA-NAMESPACE", "SCORE", "RESOURCES"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"1", "ci", "ServiceAccount", "build", "6", "secrets"}},
+		{Intro: []string{"2", "alice", "User", "", "4", "deployments.apps,secrets"}},
+	}, table.Rows)
+	assert.Equal(t, []string{"2", "alice", "User", "", "4", "deployments.apps secrets"}, SubjectScoresCSVRecords(scores)[1])
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// LoadRiskTags reads the sensitivity levels of resources from a YAML or JSON
+// file, which maps resource names to levels:
+//
+//	secrets: critical
+//	configmaps: medium
+//	deployments.apps: high
+func LoadRiskTags(path string) (result.RiskTags, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read risk tags")
+	}
+	var tags result.RiskTags
+	if err := yaml.UnmarshalStrict(data, &tags); err != nil {
+		return nil, errors.Wrapf(err, "parse risk tags from %s", path)
+	}
+	return tags, nil
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRiskTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("secrets: critical\nconfigmaps: medium\n"), 0o600))
+
+	tags, err := LoadRiskTags(path)
+	require.NoError(t, err)
+	assert.Equal(t, result.RiskTags{"secrets": "critical", "configmaps": "medium"}, tags)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("secrets: [critical]\n"), 0o600))
+	_, err = LoadRiskTags(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	v1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/klog/v2"
+)
+
+// CheckResourceAccessFromRules determines the access rights for the given
+// GroupResources and verbs from a single SelfSubjectRulesReview, instead of one
+// SelfSubjectAccessReview per resource and verb. The rules review must be
+// scoped to a namespace, so the rules in the default namespace are used for
+// cluster scope.
+func CheckResourceAccessFromRules(ctx context.Context, ssrr authv1.SelfSubjectRulesReviewInterface, grs []GroupResource, verbs []string, namespace *string, resourceName string) (result.ResourceAccess, error) {
+	rules, err := reviewRules(ctx, ssrr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	res := result.NewResultAccumulator()
+	for _, gr := range grs {
+		allowedVerbs := sets.NewString(gr.APIResource.Verbs...)
+		access := make(map[string]result.Access)
+		for _, v := range verbs {
+			switch {
+			case !allowedVerbs.Has(v):
+				access[v] = result.NotApplicable
+			case rulesAllow(rules, gr.APIGroup, gr.APIResource.Name, resourceName, v):
+				access[v] = result.Allowed
+			default:
+				access[v] = result.Denied
+			}
+		}
+		res.AddResource(gr.fullName(), access)
+	}
+	return res.Result(), nil
+}
+
+// reviewRules returns the resource rules of the current user from a
+// SelfSubjectRulesReview in the given namespace, or in the default namespace
+// for cluster scope.
+func reviewRules(ctx context.Context, ssrr authv1.SelfSubjectRulesReviewInterface, namespace *string) ([]v1.ResourceRule, error) {
+	ns := metav1.NamespaceDefault
+	if namespace != nil && *namespace != "" {
+		ns = *namespace
+	}
+
+	req := v1.SelfSubjectRulesReview{
+		Spec: v1.SelfSubjectRulesReviewSpec{Namespace: ns},
+	}
+	countAccessReview()
+	resp, err := ssrr.Create(ctx, &req, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status.Incomplete {
+		klog.Warningf("incomplete result: the rules review does not cover all authorizers: %s", resp.Status.EvaluationError)
+	}
+	return resp.Status.ResourceRules, nil
+}
+
+// rulesAllow tells whether any of the rules grants the verb on all objects of
+// the resource, or on the object with the given name. Rules which are
+// restricted to resourceNames only count for the named objects.
+func rulesAllow(rules []v1.ResourceRule, group, resource, name, verb string) bool {
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 && (name == "" || !sets.NewString(rule.ResourceNames...).Has(name)) {
+			continue
+		}
+		if ruleMatches(rule.APIGroups, group) && ruleMatches(rule.Resources, resource) && ruleMatches(rule.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func ruleMatches(entries []string, target string) bool {
+	for _, e := range entries {
+		if e == "*" || e == target {
+			return true
+		}
+	}
+	return false
+}
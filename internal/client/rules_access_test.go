@@ -0,0 +1,68 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/authorization/v1/fake"
+	authTesting "k8s.io/client-go/testing"
+)
+
+func TestCheckResourceAccessFromRules(t *testing.T) {
+	ctx := context.Background()
+	namespace := "prod"
+
+	fakeAuthClient := &fake.FakeAuthorizationV1{Fake: &authTesting.Fake{}}
+	fakeAuthClient.Fake.AddReactor("create", "selfsubjectrulesreviews",
+		func(action authTesting.Action) (handled bool, ret runtime.Object, err error) {
+			review := action.(authTesting.CreateAction).GetObject().(*v1.SelfSubjectRulesReview)
+			assert.Equal(t, namespace, review.Spec.Namespace)
+			review.Status.ResourceRules = []v1.ResourceRule{
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+				{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"*"}},
+				{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"one"}},
+			}
+			return true, review, nil
+		})
+
+	grs := []GroupResource{
+		toGroupResource("", "pods", "get", "list", "delete"),
+		toGroupResource("apps", "deployments", "get", "list", "delete"),
+		toGroupResource("", "secrets", "get", "delete"),
+	}
+	ra, err := CheckResourceAccessFromRules(ctx, fakeAuthClient.SelfSubjectRulesReviews(), grs, []string{"list", "delete"}, &namespace, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, result.ResourceAccess{
+		"pods":             {"list": result.Allowed, "delete": result.Denied},
+		"deployments.apps": {"list": result.Allowed, "delete": result.Allowed},
+		"secrets":          {"list": result.NotApplicable, "delete": result.Denied},
+	}, ra)
+
+	ra, err = CheckResourceAccessFromRules(ctx, fakeAuthClient.SelfSubjectRulesReviews(), grs[2:], []string{"get", "delete"}, &namespace, "one")
+	require.NoError(t, err)
+	assert.Equal(t, result.ResourceAccess{
+		"secrets": {"get": result.Denied, "delete": result.Allowed},
+	}, ra, "rules with resourceNames grant access to the named object")
+}
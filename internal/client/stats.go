@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import "sync/atomic"
+
+// ScanStats counts the API calls during a scan.
+type ScanStats struct {
+	// AccessReviews is the number of created (Self)SubjectAccessReviews.
+	AccessReviews int64 `json:"accessReviews"`
+	// Lists is the number of List calls for RBAC objects.
+	Lists int64 `json:"lists"`
+	// CacheHits is the number of discovery requests which were answered from
+	// the discovery cache instead of the API server.
+	CacheHits int64 `json:"cacheHits"`
+	// Parallelism is the concurrency which --auto-parallelism chose, if any.
+	Parallelism int64 `json:"parallelism,omitempty"`
+}
+
+// stats is only accessed atomically.
+var stats ScanStats
+
+// Stats returns the API call counts since program start.
+func Stats() ScanStats {
+	return ScanStats{
+		AccessReviews: atomic.LoadInt64(&stats.AccessReviews),
+		Lists:         atomic.LoadInt64(&stats.Lists),
+		CacheHits:     atomic.LoadInt64(&stats.CacheHits),
+		Parallelism:   atomic.LoadInt64(&stats.Parallelism),
+	}
+}
+
+func countAccessReview() { atomic.AddInt64(&stats.AccessReviews, 1) }
+
+func countList() { atomic.AddInt64(&stats.Lists, 1) }
+
+func countCacheHits(n int) { atomic.AddInt64(&stats.CacheHits, int64(n)) }
+
+func recordParallelism(n int) { atomic.StoreInt64(&stats.Parallelism, int64(n)) }
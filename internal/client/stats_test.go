@@ -0,0 +1,46 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	before := Stats()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			countAccessReview()
+			countAccessReview()
+			countList()
+			countCacheHits(3)
+		}()
+	}
+	wg.Wait()
+
+	after := Stats()
+	assert.Equal(t, int64(10), after.AccessReviews-before.AccessReviews)
+	assert.Equal(t, int64(5), after.Lists-before.Lists)
+	assert.Equal(t, int64(15), after.CacheHits-before.CacheHits)
+}
@@ -18,11 +18,17 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/corneliusweig/rakkess/internal/client/result"
 	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	"k8s.io/klog/v2"
 )
@@ -33,8 +39,10 @@ var (
 )
 
 const (
-	clusterRoleName = "ClusterRole"
-	roleName        = "Role"
+	clusterRoleName        = "ClusterRole"
+	roleName               = "Role"
+	clusterRoleBindingName = "ClusterRoleBinding"
+	roleBindingName        = "RoleBinding"
 )
 
 // GetSubjectAccess determines subjects with access to the given resource.
@@ -48,13 +56,22 @@ func GetSubjectAccess(ctx context.Context, opts *options.RakkessOptions, gr sche
 	isNamespace := namespace != nil && *namespace != ""
 
 	sa := result.NewSubjectAccess(gr, resourceName)
+	if sa.Normalize, err = result.ParseSubjectNormalizer(opts.SubjectNormalizer); err != nil {
+		return nil, err
+	}
+	listOpts := listOptions(opts)
+	bindingListOpts, err := bindingListOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	since := changedSince(opts)
 
-	if err := fetchMatchingClusterRoles(ctx, rbacClient, sa); err != nil {
+	if err := fetchMatchingClusterRoles(ctx, rbacClient, sa, listOpts); err != nil {
 		if !isNamespace {
 			return nil, err
 		}
 		klog.Warningf("incomplete result: %s", err)
-	} else if err := resolveClusterRoleBindings(ctx, rbacClient, sa); err != nil {
+	} else if err := resolveClusterRoleBindings(ctx, rbacClient, sa, bindingListOpts, since); err != nil {
 		if !isNamespace {
 			return nil, err
 		}
@@ -63,54 +80,270 @@ func GetSubjectAccess(ctx context.Context, opts *options.RakkessOptions, gr sche
 
 	if !isNamespace {
 		klog.V(2).Infof("Skipping roles and rolebindings because namespace is missing")
-		return sa, nil
+	} else {
+		if err := fetchMatchingRoles(ctx, rbacClient, sa, *namespace, listOpts); err != nil {
+			return nil, err
+		}
+		if err := resolveRoleBindings(ctx, rbacClient, sa, *namespace, bindingListOpts, since); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := retainLabeledServiceAccounts(ctx, opts, sa); err != nil {
+		return nil, err
+	}
+	return sa, nil
+}
+
+// GetBaselineVerbs evaluates the rules of the given (Cluster)Role and returns
+// the verbs which it grants on the resource. A Role is looked up in the
+// namespace given by --namespace.
+func GetBaselineVerbs(ctx context.Context, opts *options.RakkessOptions, gr schema.GroupResource, resourceName string, r result.RoleRef) (sets.String, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []rbacv1.PolicyRule
+	if r.Kind == clusterRoleName {
+		role, err := rbacClient.ClusterRoles().Get(ctx, r.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "get baseline role")
+		}
+		rules = role.Rules
+	} else {
+		namespace := opts.ConfigFlags.Namespace
+		if namespace == nil || *namespace == "" {
+			return nil, fmt.Errorf("baseline Role %s requires --namespace", r.Name)
+		}
+		role, err := rbacClient.Roles(*namespace).Get(ctx, r.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "get baseline role")
+		}
+		rules = role.Rules
+	}
+
+	sa := result.NewSubjectAccess(gr, resourceName)
+	for _, rule := range rules {
+		sa.MatchRules(r, rule)
 	}
+	return sa.RoleVerbs(r), nil
+}
 
-	if err := fetchMatchingRoles(ctx, rbacClient, sa, *namespace); err != nil {
+// GetNonResourceSubjectAccess determines subjects with access to the given
+// non-resource URL path. Only ClusterRoleBindings are considered, because
+// RoleBindings cannot grant access to non-resource URLs.
+func GetNonResourceSubjectAccess(ctx context.Context, opts *options.RakkessOptions, path string) (*result.SubjectAccess, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
 		return nil, err
 	}
-	if err := resolveRoleBindings(ctx, rbacClient, sa, *namespace); err != nil {
+
+	sa := result.NewNonResourceSubjectAccess(path)
+	if sa.Normalize, err = result.ParseSubjectNormalizer(opts.SubjectNormalizer); err != nil {
+		return nil, err
+	}
+	listOpts := listOptions(opts)
+	bindingListOpts, err := bindingListOptions(opts)
+	if err != nil {
 		return nil, err
 	}
+	since := changedSince(opts)
 
+	if err := fetchMatchingClusterRoles(ctx, rbacClient, sa, listOpts); err != nil {
+		return nil, err
+	}
+	if err := resolveClusterRoleBindings(ctx, rbacClient, sa, bindingListOpts, since); err != nil {
+		return nil, err
+	}
 	return sa, nil
 }
 
-func resolveRoleBindings(ctx context.Context, cli clientv1.RoleBindingsGetter, sa *result.SubjectAccess, namespace string) error {
+// GetSubjectAccessAllNamespaces determines subjects with access to the given
+// resource in all namespaces. The access granted by ClusterRoleBindings is
+// stored under the empty namespace.
+func GetSubjectAccessAllNamespaces(ctx context.Context, opts *options.RakkessOptions, gr schema.GroupResource, resourceName string) (result.NamespacedSubjectAccess, error) {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	normalize, err := result.ParseSubjectNormalizer(opts.SubjectNormalizer)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := listOptions(opts)
+	bindingListOpts, err := bindingListOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	since := changedSince(opts)
+
+	klog.V(2).Infof("fetching clusterRoles")
+	countList()
+	clusterRoles, err := rbacClient.ClusterRoles().List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	// RoleBindings may refer to ClusterRoles, so every namespace needs them
+	newAccess := func() *result.SubjectAccess {
+		sa := result.NewSubjectAccess(gr, resourceName)
+		sa.Normalize = normalize
+		for _, role := range clusterRoles.Items {
+			r := result.RoleRef{Name: role.Name, Kind: clusterRoleName}
+			for _, rule := range role.Rules {
+				sa.MatchRules(r, rule)
+			}
+		}
+		return sa
+	}
+
+	cluster := newAccess()
+	if err := resolveClusterRoleBindings(ctx, rbacClient, cluster, bindingListOpts, since); err != nil {
+		return nil, err
+	}
+	access := result.NamespacedSubjectAccess{"": cluster}
+	inNamespace := func(ns string) *result.SubjectAccess {
+		sa, ok := access[ns]
+		if !ok {
+			sa = newAccess()
+			access[ns] = sa
+		}
+		return sa
+	}
+
+	klog.V(2).Infof("fetching roles in all namespaces")
+	countList()
+	roles, err := rbacClient.Roles(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles.Items {
+		sa := inNamespace(role.Namespace)
+		r := result.RoleRef{Name: role.Name, Kind: roleName}
+		for _, rule := range role.Rules {
+			sa.MatchRules(r, rule)
+		}
+	}
+
+	klog.V(2).Infof("fetching RoleBindings in all namespaces")
+	countList()
+	roleBindings, err := rbacClient.RoleBindings(metav1.NamespaceAll).List(ctx, bindingListOpts)
+	if err != nil {
+		return nil, err
+	}
+	for i, rb := range roleBindings.Items {
+		if !changedAfter(&roleBindings.Items[i], since) {
+			continue
+		}
+		r := result.RoleRef{Name: rb.RoleRef.Name, Kind: rb.RoleRef.Kind}
+		b := result.BindingRef{Name: rb.Name, Kind: roleBindingName, Namespace: rb.Namespace}
+		inNamespace(rb.Namespace).ResolveRoleRef(r, b, rb.Subjects)
+	}
+
+	parts := make([]*result.SubjectAccess, 0, len(access))
+	for _, sa := range access {
+		parts = append(parts, sa)
+	}
+	if err := retainLabeledServiceAccounts(ctx, opts, parts...); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// retainLabeledServiceAccounts removes all subjects except the service-accounts
+// whose labels match --subject-labels. Users and groups have no labels and are
+// removed as well. The service-accounts of all namespaces are listed, because
+// ClusterRoleBindings and RoleBindings may refer to any namespace.
+func retainLabeledServiceAccounts(ctx context.Context, opts *options.RakkessOptions, access ...*result.SubjectAccess) error {
+	if opts.SubjectLabelSelector == "" {
+		return nil
+	}
+	selector, err := labels.Parse(opts.SubjectLabelSelector)
+	if err != nil {
+		return errors.Wrap(err, "parse subject label selector")
+	}
+	saClient, err := getServiceAccountsClient(opts)
+	if err != nil {
+		return err
+	}
+	listOpts := listOptions(opts)
+	listOpts.LabelSelector = selector.String()
+
+	klog.V(2).Infof("fetching ServiceAccounts with labels %s in all namespaces", listOpts.LabelSelector)
+	countList()
+	serviceAccounts, err := saClient.ServiceAccounts(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return errors.Wrap(err, "list service-accounts")
+	}
+	selected := make(map[result.SubjectRef]bool, len(serviceAccounts.Items))
+	for _, sa := range serviceAccounts.Items {
+		selected[result.SubjectRef{Name: sa.Name, Kind: rbacv1.ServiceAccountKind, Namespace: sa.Namespace}] = true
+	}
+	for _, sa := range access {
+		sa.RetainServiceAccounts(selected)
+	}
+	return nil
+}
+
+// resolveRoleBindings stores the access granted by the RoleBindings in the
+// namespace. Bindings which did not change after since are skipped, unless
+// since is zero.
+func resolveRoleBindings(ctx context.Context, cli clientv1.RoleBindingsGetter, sa *result.SubjectAccess, namespace string, listOpts metav1.ListOptions, since time.Time) error {
 	klog.V(2).Infof("fetching RoleBindings for namespace %s", namespace)
-	roleBindings, err := cli.RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	countList()
+	roleBindings, err := cli.RoleBindings(namespace).List(ctx, listOpts)
 	if err != nil {
 		return err
 	}
-	for _, rb := range roleBindings.Items {
+	for i, rb := range roleBindings.Items {
+		if !changedAfter(&roleBindings.Items[i], since) {
+			continue
+		}
 		r := result.RoleRef{
 			Name: rb.RoleRef.Name,
 			Kind: rb.RoleRef.Kind,
 		}
-		sa.ResolveRoleRef(r, rb.Subjects)
+		b := result.BindingRef{
+			Name:      rb.Name,
+			Kind:      roleBindingName,
+			Namespace: namespace,
+		}
+		sa.ResolveRoleRef(r, b, rb.Subjects)
 	}
 	return nil
 }
 
-func resolveClusterRoleBindings(ctx context.Context, cli clientv1.ClusterRoleBindingsGetter, sa *result.SubjectAccess) error {
+// resolveClusterRoleBindings stores the access granted by the
+// ClusterRoleBindings. Bindings which did not change after since are skipped,
+// unless since is zero.
+func resolveClusterRoleBindings(ctx context.Context, cli clientv1.ClusterRoleBindingsGetter, sa *result.SubjectAccess, listOpts metav1.ListOptions, since time.Time) error {
 	klog.V(2).Infof("fetching ClusterRoleBindings")
-	clusterRoleBindings, err := cli.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	countList()
+	clusterRoleBindings, err := cli.ClusterRoleBindings().List(ctx, listOpts)
 	if err != nil {
 		return err
 	}
-	for _, crb := range clusterRoleBindings.Items {
+	for i, crb := range clusterRoleBindings.Items {
+		if !changedAfter(&clusterRoleBindings.Items[i], since) {
+			continue
+		}
 		r := result.RoleRef{
 			Name: crb.RoleRef.Name,
 			Kind: crb.RoleRef.Kind,
 		}
-		sa.ResolveRoleRef(r, crb.Subjects)
+		b := result.BindingRef{
+			Name: crb.Name,
+			Kind: clusterRoleBindingName,
+		}
+		sa.ResolveRoleRef(r, b, crb.Subjects)
 	}
 	return nil
 }
 
-func fetchMatchingClusterRoles(ctx context.Context, rbacClient clientv1.ClusterRolesGetter, sa *result.SubjectAccess) error {
+func fetchMatchingClusterRoles(ctx context.Context, rbacClient clientv1.ClusterRolesGetter, sa *result.SubjectAccess, listOpts metav1.ListOptions) error {
 	klog.V(2).Infof("fetching clusterRoles")
-	roleList, err := rbacClient.ClusterRoles().List(ctx, metav1.ListOptions{})
+	countList()
+	roleList, err := rbacClient.ClusterRoles().List(ctx, listOpts)
 	if err != nil {
 		return err
 	}
@@ -127,9 +360,10 @@ func fetchMatchingClusterRoles(ctx context.Context, rbacClient clientv1.ClusterR
 	return nil
 }
 
-func fetchMatchingRoles(ctx context.Context, rbacClient clientv1.RolesGetter, sa *result.SubjectAccess, namespace string) error {
+func fetchMatchingRoles(ctx context.Context, rbacClient clientv1.RolesGetter, sa *result.SubjectAccess, namespace string, listOpts metav1.ListOptions) error {
 	klog.V(2).Infof("fetching roles for namespace %s", namespace)
-	roleList, err := rbacClient.Roles(namespace).List(ctx, metav1.ListOptions{})
+	countList()
+	roleList, err := rbacClient.Roles(namespace).List(ctx, listOpts)
 	if err != nil {
 		return err
 	}
@@ -146,8 +380,35 @@ func fetchMatchingRoles(ctx context.Context, rbacClient clientv1.RolesGetter, sa
 	return nil
 }
 
+// listOptions pins List calls to the configured resourceVersion, so that all
+// RBAC objects are listed from the same consistent snapshot.
+func listOptions(opts *options.RakkessOptions) metav1.ListOptions {
+	if opts.ResourceVersion == "" {
+		return metav1.ListOptions{}
+	}
+	return metav1.ListOptions{
+		ResourceVersion:      opts.ResourceVersion,
+		ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+	}
+}
+
+// bindingListOptions restricts the List calls for (Cluster)RoleBindings to the
+// bindings which match the configured label selector.
+func bindingListOptions(opts *options.RakkessOptions) (metav1.ListOptions, error) {
+	listOpts := listOptions(opts)
+	if opts.BindingLabelSelector == "" {
+		return listOpts, nil
+	}
+	selector, err := labels.Parse(opts.BindingLabelSelector)
+	if err != nil {
+		return metav1.ListOptions{}, errors.Wrap(err, "parse binding label selector")
+	}
+	listOpts.LabelSelector = selector.String()
+	return listOpts, nil
+}
+
 func getRbacClientImpl(o *options.RakkessOptions) (clientv1.RbacV1Interface, error) {
-	restConfig, err := o.ConfigFlags.ToRESTConfig()
+	restConfig, err := o.ToRESTConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -24,12 +24,15 @@ import (
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/options"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corefake "k8s.io/client-go/kubernetes/typed/core/v1/fake"
 	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	"k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
 	k8stesting "k8s.io/client-go/testing"
@@ -127,7 +130,7 @@ func TestGetSubjectAccess(t *testing.T) {
 			roles:               roles("", "configmaps", v1.VerbAll),
 			roleBindings:        roleBindings(testRoleName, roleName, "test-user"),
 			expected: map[result.SubjectRef]sets.String{
-				{Name: "test-user", Kind: subjectKind}: sets.NewString(constants.ValidVerbs...),
+				{Name: "test-user", Kind: subjectKind}: sets.NewString(constants.ValidVerbs...).Insert(constants.SpecialVerbs...),
 			},
 		},
 		{
@@ -138,7 +141,7 @@ func TestGetSubjectAccess(t *testing.T) {
 			clusterRoles:        clusterRoles("", "configmaps", v1.VerbAll),
 			clusterRoleBindings: clusterRoleBindings("test-user"),
 			expected: map[result.SubjectRef]sets.String{
-				{Name: "test-user", Kind: subjectKind}: sets.NewString(constants.ValidVerbs...),
+				{Name: "test-user", Kind: subjectKind}: sets.NewString(constants.ValidVerbs...).Insert(constants.SpecialVerbs...),
 			},
 		},
 	}
@@ -185,6 +188,206 @@ func TestGetSubjectAccess(t *testing.T) {
 	}
 }
 
+func TestGetSubjectAccessAllNamespaces(t *testing.T) {
+	ctx := context.Background()
+	reader := v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}
+
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("list", "clusterroles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleList{Items: []v1.ClusterRole{
+				{ObjectMeta: metav1.ObjectMeta{Name: "secret-reader"}, Rules: []v1.PolicyRule{reader}},
+			}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleBindingList{Items: []v1.ClusterRoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "readers"},
+					RoleRef:    v1.RoleRef{Kind: "ClusterRole", Name: "secret-reader"},
+					Subjects:   []v1.Subject{{Kind: "User", Name: "alice"}},
+				},
+			}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "roles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			assert.Equal(t, metav1.NamespaceAll, action.GetNamespace())
+			return true, &v1.RoleList{Items: []v1.Role{
+				{ObjectMeta: metav1.ObjectMeta{Name: "getter", Namespace: "a"}, Rules: []v1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}},
+				// same name, but no access in namespace b
+				{ObjectMeta: metav1.ObjectMeta{Name: "getter", Namespace: "b"}},
+			}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "rolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.RoleBindingList{Items: []v1.RoleBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "getter", Namespace: "a"},
+					RoleRef:    v1.RoleRef{Kind: "Role", Name: "getter"},
+					Subjects:   []v1.Subject{{Kind: "User", Name: "bob"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "getter", Namespace: "b"},
+					RoleRef:    v1.RoleRef{Kind: "Role", Name: "getter"},
+					Subjects:   []v1.Subject{{Kind: "User", Name: "bob"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "b"},
+					RoleRef:    v1.RoleRef{Kind: "ClusterRole", Name: "secret-reader"},
+					Subjects:   []v1.Subject{{Kind: "ServiceAccount", Name: "ci", Namespace: "b"}},
+				},
+			}}, nil
+		})
+
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	defer func() { getRbacClient = getRbacClientImpl }()
+
+	opts := &options.RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{}}
+	access, err := GetSubjectAccessAllNamespaces(ctx, opts, schema.GroupResource{Resource: "secrets"}, "")
+	assert.NoError(t, err)
+	assert.Len(t, access, 3)
+	assert.Equal(t, map[result.SubjectRef]sets.String{{Name: "alice", Kind: "User"}: sets.NewString("get", "list")}, access[""].Get())
+	assert.Equal(t, map[result.SubjectRef]sets.String{{Name: "bob", Kind: "User"}: sets.NewString("get")}, access["a"].Get())
+	assert.Equal(t, map[result.SubjectRef]sets.String{{Name: "ci", Kind: "ServiceAccount", Namespace: "b"}: sets.NewString("get", "list")}, access["b"].Get())
+}
+
+func TestGetSubjectAccessBindingLabelSelector(t *testing.T) {
+	ctx := context.Background()
+	namespace := roleNamespace
+
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("list", "clusterroles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleList{Items: clusterRoles("", "secrets", "get")}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			bindings := clusterRoleBindings("platform-admin")
+			bindings[0].Labels = map[string]string{"team": "platform"}
+			return true, &v1.ClusterRoleBindingList{Items: append(bindings, clusterRoleBindings("unlabeled")...)}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "roles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			assert.Empty(t, action.(k8stesting.ListAction).GetListRestrictions().Labels.String(), "roles must not be filtered")
+			return true, &v1.RoleList{Items: roles("", "secrets", "list")}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "rolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			bindings := roleBindings(testRoleName, roleName, "platform-dev")
+			bindings[0].Labels = map[string]string{"team": "platform"}
+			other := roleBindings(testRoleName, roleName, "other-dev")
+			other[0].Labels = map[string]string{"team": "other"}
+			return true, &v1.RoleBindingList{Items: append(bindings, other...)}, nil
+		})
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	defer func() { getRbacClient = getRbacClientImpl }()
+
+	opts := &options.RakkessOptions{
+		ConfigFlags:          &genericclioptions.ConfigFlags{Namespace: &namespace},
+		BindingLabelSelector: "team=platform",
+	}
+	sa, err := GetSubjectAccess(ctx, opts, schema.GroupResource{Resource: "secrets"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[result.SubjectRef]sets.String{
+		{Name: "platform-admin", Kind: subjectKind}: sets.NewString("get"),
+		{Name: "platform-dev", Kind: subjectKind}:   sets.NewString("list"),
+	}, sa.Get())
+
+	opts.BindingLabelSelector = "team in (platform"
+	_, err = GetSubjectAccess(ctx, opts, schema.GroupResource{Resource: "secrets"}, "")
+	assert.Error(t, err)
+}
+
+func TestGetBaselineVerbs(t *testing.T) {
+	ctx := context.Background()
+	namespace := roleNamespace
+
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("get", "clusterroles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &clusterRoles("", "secrets", "get", "list")[0], nil
+		})
+	fakeRbacClient.Fake.AddReactor("get", "roles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			assert.Equal(t, roleNamespace, action.GetNamespace())
+			return true, &roles("", "configmaps", "*")[0], nil
+		})
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	defer func() { getRbacClient = getRbacClientImpl }()
+
+	opts := &options.RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{}}
+	verbs, err := GetBaselineVerbs(ctx, opts, schema.GroupResource{Resource: "secrets"}, "", result.RoleRef{Name: testClusterRoleName, Kind: clusterRoleName})
+	assert.NoError(t, err)
+	assert.Equal(t, sets.NewString("get", "list"), verbs)
+
+	_, err = GetBaselineVerbs(ctx, opts, schema.GroupResource{Resource: "secrets"}, "", result.RoleRef{Name: testRoleName, Kind: roleName})
+	assert.EqualError(t, err, "baseline Role some-role requires --namespace")
+
+	opts.ConfigFlags.Namespace = &namespace
+	verbs, err = GetBaselineVerbs(ctx, opts, schema.GroupResource{Resource: "secrets"}, "", result.RoleRef{Name: testRoleName, Kind: roleName})
+	assert.NoError(t, err)
+	assert.Empty(t, verbs)
+}
+
+func TestGetSubjectAccessSubjectLabelSelector(t *testing.T) {
+	ctx := context.Background()
+	namespace := ""
+
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("list", "clusterroles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleList{Items: clusterRoles("", "secrets", "get")}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			bindings := clusterRoleBindings("alice")
+			bindings[0].Subjects = append(bindings[0].Subjects,
+				v1.Subject{Kind: v1.ServiceAccountKind, Name: "deployer", Namespace: "platform"},
+				v1.Subject{Kind: v1.ServiceAccountKind, Name: "deployer", Namespace: "other"},
+			)
+			return true, &v1.ClusterRoleBindingList{Items: bindings}, nil
+		})
+	fakeCoreClient := &corefake.FakeCoreV1{Fake: &k8stesting.Fake{}}
+	fakeCoreClient.Fake.AddReactor("list", "serviceaccounts",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			assert.Equal(t, metav1.NamespaceAll, action.GetNamespace())
+			assert.Equal(t, "team=platform", action.(k8stesting.ListAction).GetListRestrictions().Labels.String())
+			return true, &corev1.ServiceAccountList{Items: []corev1.ServiceAccount{
+				{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "platform", Labels: map[string]string{"team": "platform"}}},
+			}}, nil
+		})
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	getServiceAccountsClient = func(*options.RakkessOptions) (typedcorev1.ServiceAccountsGetter, error) {
+		return fakeCoreClient, nil
+	}
+	defer func() {
+		getRbacClient = getRbacClientImpl
+		getServiceAccountsClient = getServiceAccountsClientImpl
+	}()
+
+	opts := &options.RakkessOptions{
+		ConfigFlags:          &genericclioptions.ConfigFlags{Namespace: &namespace},
+		SubjectLabelSelector: "team=platform",
+	}
+	sa, err := GetSubjectAccess(ctx, opts, schema.GroupResource{Resource: "secrets"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[result.SubjectRef]sets.String{
+		{Name: "deployer", Kind: v1.ServiceAccountKind, Namespace: "platform"}: sets.NewString("get"),
+	}, sa.Get(), "users and unlabeled service-accounts are removed")
+
+	opts.SubjectLabelSelector = "team in (platform"
+	_, err = GetSubjectAccess(ctx, opts, schema.GroupResource{Resource: "secrets"}, "")
+	assert.Error(t, err)
+}
+
 func clusterRoles(apiGroup, resource string, verbs ...string) []v1.ClusterRole {
 	return []v1.ClusterRole{
 		{
@@ -257,3 +460,11 @@ func roleBindings(role, kind string, subjects ...string) []v1.RoleBinding {
 		},
 	}
 }
+
+func TestListOptions(t *testing.T) {
+	assert.Equal(t, metav1.ListOptions{}, listOptions(&options.RakkessOptions{}))
+	assert.Equal(t, metav1.ListOptions{
+		ResourceVersion:      "4711",
+		ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+	}, listOptions(&options.RakkessOptions{ResourceVersion: "4711"}))
+}
@@ -0,0 +1,389 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/klog/v2"
+)
+
+// roleKey identifies a Role or ClusterRole. Unlike result.RoleRef, it includes
+// the namespace, because the index holds Roles of several namespaces.
+type roleKey struct {
+	result.RoleRef
+	Namespace string
+}
+
+// indexedBinding is a (Cluster)RoleBinding as far as the index needs it.
+type indexedBinding struct {
+	role     roleKey
+	subjects []rbacv1.Subject
+}
+
+// SubjectAccessIndex maintains the subject access to a resource incrementally.
+// Every (Cluster)RoleBinding contributes a partial SubjectAccess, and the index
+// records which bindings refer to which role. A changed role only re-evaluates
+// the bindings which refer to it, and a changed binding only re-evaluates itself.
+type SubjectAccessIndex struct {
+	gr            schema.GroupResource
+	resourceName  string
+	normalize     result.SubjectNormalizer
+	roles         map[roleKey][]rbacv1.PolicyRule
+	bindings      map[result.BindingRef]indexedBinding
+	byRole        map[roleKey]map[result.BindingRef]bool
+	contributions map[result.BindingRef]*result.SubjectAccess
+}
+
+// NewSubjectAccessIndex creates an empty index for the given resource.
+func NewSubjectAccessIndex(gr schema.GroupResource, resourceName string, normalize result.SubjectNormalizer) *SubjectAccessIndex {
+	return &SubjectAccessIndex{
+		gr:            gr,
+		resourceName:  resourceName,
+		normalize:     normalize,
+		roles:         make(map[roleKey][]rbacv1.PolicyRule),
+		bindings:      make(map[result.BindingRef]indexedBinding),
+		byRole:        make(map[roleKey]map[result.BindingRef]bool),
+		contributions: make(map[result.BindingRef]*result.SubjectAccess),
+	}
+}
+
+// Apply updates the index with an event of a Role, ClusterRole, RoleBinding,
+// or ClusterRoleBinding. Other objects and bookmarks are ignored.
+func (idx *SubjectAccessIndex) Apply(eventType watch.EventType, obj runtime.Object) {
+	deleted := eventType == watch.Deleted
+	switch o := obj.(type) {
+	case *rbacv1.ClusterRole:
+		idx.setRole(roleKey{RoleRef: result.RoleRef{Name: o.Name, Kind: clusterRoleName}}, o.Rules, deleted)
+	case *rbacv1.Role:
+		idx.setRole(roleKey{RoleRef: result.RoleRef{Name: o.Name, Kind: roleName}, Namespace: o.Namespace}, o.Rules, deleted)
+	case *rbacv1.ClusterRoleBinding:
+		b := result.BindingRef{Name: o.Name, Kind: clusterRoleBindingName}
+		idx.setBinding(b, boundRole(o.RoleRef, ""), o.Subjects, deleted)
+	case *rbacv1.RoleBinding:
+		b := result.BindingRef{Name: o.Name, Kind: roleBindingName, Namespace: o.Namespace}
+		idx.setBinding(b, boundRole(o.RoleRef, o.Namespace), o.Subjects, deleted)
+	}
+}
+
+// boundRole returns the key of the role referenced by a binding in namespace.
+// ClusterRoles are not namespaced, even if a RoleBinding refers to them.
+func boundRole(ref rbacv1.RoleRef, namespace string) roleKey {
+	if ref.Kind == clusterRoleName {
+		namespace = ""
+	}
+	return roleKey{RoleRef: result.RoleRef{Name: ref.Name, Kind: ref.Kind}, Namespace: namespace}
+}
+
+func (idx *SubjectAccessIndex) setRole(r roleKey, rules []rbacv1.PolicyRule, deleted bool) {
+	if deleted {
+		delete(idx.roles, r)
+	} else {
+		idx.roles[r] = rules
+	}
+	for b := range idx.byRole[r] {
+		idx.evaluate(b)
+	}
+}
+
+func (idx *SubjectAccessIndex) setBinding(b result.BindingRef, r roleKey, subjects []rbacv1.Subject, deleted bool) {
+	if old, ok := idx.bindings[b]; ok {
+		delete(idx.byRole[old.role], b)
+		if len(idx.byRole[old.role]) == 0 {
+			delete(idx.byRole, old.role)
+		}
+	}
+	if deleted {
+		delete(idx.bindings, b)
+		delete(idx.contributions, b)
+		return
+	}
+
+	idx.bindings[b] = indexedBinding{role: r, subjects: subjects}
+	if idx.byRole[r] == nil {
+		idx.byRole[r] = make(map[result.BindingRef]bool)
+	}
+	idx.byRole[r][b] = true
+	idx.evaluate(b)
+}
+
+// evaluate computes the access which the binding b contributes.
+func (idx *SubjectAccessIndex) evaluate(b result.BindingRef) {
+	binding := idx.bindings[b]
+	sa := result.NewSubjectAccess(idx.gr, idx.resourceName)
+	sa.Normalize = idx.normalize
+	for _, rule := range idx.roles[binding.role] {
+		sa.MatchRules(binding.role.RoleRef, rule)
+	}
+	sa.ResolveRoleRef(binding.role.RoleRef, b, binding.subjects)
+	if sa.Empty() {
+		delete(idx.contributions, b)
+		return
+	}
+	idx.contributions[b] = sa
+}
+
+// Result combines the contributions of all bindings into the full subject access.
+func (idx *SubjectAccessIndex) Result() *result.SubjectAccess {
+	if len(idx.contributions) == 0 {
+		sa := result.NewSubjectAccess(idx.gr, idx.resourceName)
+		sa.Normalize = idx.normalize
+		return sa
+	}
+	parts := make([]*result.SubjectAccess, 0, len(idx.contributions))
+	for _, sa := range idx.contributions {
+		parts = append(parts, sa)
+	}
+	return result.MergeSubjectAccess(parts...)
+}
+
+// rbacSource lists and watches one kind of RBAC objects.
+type rbacSource struct {
+	kind  string
+	list  func(context.Context, metav1.ListOptions) ([]runtime.Object, string, error)
+	watch func(context.Context, metav1.ListOptions) (watch.Interface, error)
+}
+
+// rbacSources returns the RBAC objects which determine the subject access.
+// As in GetSubjectAccess, Roles and RoleBindings are only considered with a namespace.
+func rbacSources(cli clientv1.RbacV1Interface, namespace string, bindingSelector string) []rbacSource {
+	sources := []rbacSource{
+		{
+			kind: clusterRoleName,
+			list: func(ctx context.Context, o metav1.ListOptions) ([]runtime.Object, string, error) {
+				l, err := cli.ClusterRoles().List(ctx, o)
+				if err != nil {
+					return nil, "", err
+				}
+				objs := make([]runtime.Object, 0, len(l.Items))
+				for i := range l.Items {
+					objs = append(objs, &l.Items[i])
+				}
+				return objs, l.ResourceVersion, nil
+			},
+			watch: cli.ClusterRoles().Watch,
+		},
+		{
+			kind: clusterRoleBindingName,
+			list: func(ctx context.Context, o metav1.ListOptions) ([]runtime.Object, string, error) {
+				o.LabelSelector = bindingSelector
+				l, err := cli.ClusterRoleBindings().List(ctx, o)
+				if err != nil {
+					return nil, "", err
+				}
+				objs := make([]runtime.Object, 0, len(l.Items))
+				for i := range l.Items {
+					objs = append(objs, &l.Items[i])
+				}
+				return objs, l.ResourceVersion, nil
+			},
+			watch: func(ctx context.Context, o metav1.ListOptions) (watch.Interface, error) {
+				o.LabelSelector = bindingSelector
+				return cli.ClusterRoleBindings().Watch(ctx, o)
+			},
+		},
+	}
+	if namespace == "" {
+		return sources
+	}
+	return append(sources,
+		rbacSource{
+			kind: roleName,
+			list: func(ctx context.Context, o metav1.ListOptions) ([]runtime.Object, string, error) {
+				l, err := cli.Roles(namespace).List(ctx, o)
+				if err != nil {
+					return nil, "", err
+				}
+				objs := make([]runtime.Object, 0, len(l.Items))
+				for i := range l.Items {
+					objs = append(objs, &l.Items[i])
+				}
+				return objs, l.ResourceVersion, nil
+			},
+			watch: cli.Roles(namespace).Watch,
+		},
+		rbacSource{
+			kind: roleBindingName,
+			list: func(ctx context.Context, o metav1.ListOptions) ([]runtime.Object, string, error) {
+				o.LabelSelector = bindingSelector
+				l, err := cli.RoleBindings(namespace).List(ctx, o)
+				if err != nil {
+					return nil, "", err
+				}
+				objs := make([]runtime.Object, 0, len(l.Items))
+				for i := range l.Items {
+					objs = append(objs, &l.Items[i])
+				}
+				return objs, l.ResourceVersion, nil
+			},
+			watch: func(ctx context.Context, o metav1.ListOptions) (watch.Interface, error) {
+				o.LabelSelector = bindingSelector
+				return cli.RoleBindings(namespace).Watch(ctx, o)
+			},
+		},
+	)
+}
+
+// sourceEvent is a watch event of the source with the given index.
+type sourceEvent struct {
+	source int
+	event  watch.Event
+	closed bool
+}
+
+// watchDebounce is how long changes are collected before the subject access
+// is reported again, so that a burst of events, e.g. from applying a whole
+// manifest, triggers a single recompute.
+var watchDebounce = 500 * time.Millisecond
+
+// WatchSubjectAccess lists the RBAC objects once and then watches them, so
+// that the subject access to the given resource is maintained incrementally.
+// onChange is called with the full subject access after the initial list, and
+// once for all events which change RBAC objects within watchDebounce.
+// Bookmarks only advance the resourceVersion from which a watch is restarted
+// when the API server closes it. If the resourceVersion has expired,
+// everything is listed again.
+func WatchSubjectAccess(ctx context.Context, opts *options.RakkessOptions, gr schema.GroupResource, resourceName string, onChange func(*result.SubjectAccess) error) error {
+	rbacClient, err := getRbacClient(opts)
+	if err != nil {
+		return err
+	}
+	normalize, err := result.ParseSubjectNormalizer(opts.SubjectNormalizer)
+	if err != nil {
+		return err
+	}
+	bindingListOpts, err := bindingListOptions(opts)
+	if err != nil {
+		return err
+	}
+	var namespace string
+	if ns := opts.ConfigFlags.Namespace; ns != nil {
+		namespace = *ns
+	}
+	sources := rbacSources(rbacClient, namespace, bindingListOpts.LabelSelector)
+
+	for {
+		err := watchSources(ctx, sources, NewSubjectAccessIndex(gr, resourceName, normalize), onChange)
+		if !apierrors.IsResourceExpired(err) && !apierrors.IsGone(err) {
+			return err
+		}
+		klog.V(1).Infof("Listing all RBAC objects again: %s", err)
+	}
+}
+
+// watchSources fills the index from the sources and keeps it up to date,
+// until the context is cancelled or a watch fails.
+func watchSources(ctx context.Context, sources []rbacSource, idx *SubjectAccessIndex, onChange func(*result.SubjectAccess) error) error {
+	resourceVersions := make([]string, len(sources))
+	for i, src := range sources {
+		klog.V(2).Infof("listing %ss", src.kind)
+		countList()
+		objs, rv, err := src.list(ctx, metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "list %ss", src.kind)
+		}
+		for _, obj := range objs {
+			idx.Apply(watch.Added, obj)
+		}
+		resourceVersions[i] = rv
+	}
+	if err := onChange(idx.Result()); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan sourceEvent)
+	start := func(i int) error {
+		klog.V(2).Infof("watching %ss from resourceVersion %s", sources[i].kind, resourceVersions[i])
+		w, err := sources[i].watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersions[i], AllowWatchBookmarks: true})
+		if err != nil {
+			return errors.Wrapf(err, "watch %ss", sources[i].kind)
+		}
+		go func() {
+			defer w.Stop()
+			for e := range w.ResultChan() {
+				select {
+				case events <- sourceEvent{source: i, event: e}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case events <- sourceEvent{source: i, closed: true}:
+			case <-ctx.Done():
+			}
+		}()
+		return nil
+	}
+	for i := range sources {
+		if err := start(i); err != nil {
+			return err
+		}
+	}
+
+	var pending <-chan time.Time
+	for {
+		var e sourceEvent
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-pending:
+			pending = nil
+			if err := onChange(idx.Result()); err != nil {
+				return err
+			}
+			continue
+		case e = <-events:
+		}
+
+		switch {
+		case e.closed:
+			// the API server closes watches after a timeout, resume where it stopped
+			if err := start(e.source); err != nil {
+				return err
+			}
+			continue
+		case e.event.Type == watch.Error:
+			return errors.Wrapf(apierrors.FromObject(e.event.Object), "watch %ss", sources[e.source].kind)
+		}
+
+		if accessor, err := meta.Accessor(e.event.Object); err == nil {
+			resourceVersions[e.source] = accessor.GetResourceVersion()
+		}
+		if e.event.Type == watch.Bookmark {
+			continue
+		}
+		klog.V(2).Infof("%s %s event", sources[e.source].kind, e.event.Type)
+		idx.Apply(e.event.Type, e.event.Object)
+		if pending == nil {
+			pending = time.After(watchDebounce)
+		}
+	}
+}
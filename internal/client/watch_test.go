@@ -0,0 +1,202 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func secretsRole(name, namespace string, verbs ...string) *v1.Role {
+	return &v1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Rules:      []v1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: verbs}},
+	}
+}
+
+func userBinding(name, namespace, roleKind, roleName, user string) *v1.RoleBinding {
+	return &v1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		RoleRef:    v1.RoleRef{Kind: roleKind, Name: roleName},
+		Subjects:   []v1.Subject{{Kind: "User", Name: user}},
+	}
+}
+
+func TestSubjectAccessIndex(t *testing.T) {
+	alice := result.SubjectRef{Name: "alice", Kind: "User"}
+	bob := result.SubjectRef{Name: "bob", Kind: "User"}
+	idx := NewSubjectAccessIndex(schema.GroupResource{Resource: "secrets"}, "", nil)
+
+	// bindings may arrive before their role
+	idx.Apply(watch.Added, userBinding("alice-reads", "prod", "Role", "reader", "alice"))
+	assert.Empty(t, idx.Result().Get())
+
+	idx.Apply(watch.Added, secretsRole("reader", "prod", "get"))
+	idx.Apply(watch.Added, secretsRole("reader", "dev", "get", "list", "delete"))
+	idx.Apply(watch.Added, &v1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret-admin"},
+		Rules:      []v1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "delete"}}},
+	})
+	idx.Apply(watch.Added, userBinding("bob-admin", "prod", "ClusterRole", "secret-admin", "bob"))
+	assert.Equal(t, map[result.SubjectRef]sets.String{
+		alice: sets.NewString("get"),
+		bob:   sets.NewString("get", "delete"),
+	}, idx.Result().Get())
+
+	// a changed role re-evaluates the bindings which refer to it
+	idx.Apply(watch.Modified, secretsRole("reader", "prod", "get", "list"))
+	assert.Equal(t, sets.NewString("get", "list"), idx.Result().Get()[alice])
+
+	// a binding which now refers to another role no longer grants the old one
+	idx.Apply(watch.Modified, userBinding("bob-admin", "prod", "Role", "reader", "bob"))
+	assert.Equal(t, sets.NewString("get", "list"), idx.Result().Get()[bob])
+	idx.Apply(watch.Modified, &v1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "secret-admin"}})
+	assert.Equal(t, sets.NewString("get", "list"), idx.Result().Get()[bob])
+
+	idx.Apply(watch.Deleted, userBinding("alice-reads", "prod", "Role", "reader", "alice"))
+	idx.Apply(watch.Deleted, secretsRole("reader", "prod"))
+	assert.Empty(t, idx.Result().Get())
+	assert.Equal(t, "secrets", idx.Result().GroupResource.Resource)
+}
+
+// fakeWatchRbacClient lists a Role reader in prod, which grants get on
+// secrets, and serves the given watches for roles and rolebindings. It returns
+// the watched resources.
+func fakeWatchRbacClient(t *testing.T, roleWatch, bindingWatch watch.Interface) *[]string {
+	fakeRbacClient := &fake.FakeRbacV1{Fake: &k8stesting.Fake{}}
+	fakeRbacClient.Fake.AddReactor("list", "clusterroles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "clusterrolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.ClusterRoleBindingList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "roles",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.RoleList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: []v1.Role{*secretsRole("reader", "prod", "get")}}, nil
+		})
+	fakeRbacClient.Fake.AddReactor("list", "rolebindings",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.RoleBindingList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil
+		})
+	var watched []string
+	fakeRbacClient.Fake.AddWatchReactor("*", func(action k8stesting.Action) (handled bool, ret watch.Interface, err error) {
+		watchAction := action.(k8stesting.WatchAction)
+		assert.Equal(t, "1", watchAction.GetWatchRestrictions().ResourceVersion)
+		watched = append(watched, action.GetResource().Resource)
+		switch action.GetResource().Resource {
+		case "roles":
+			return true, roleWatch, nil
+		case "rolebindings":
+			return true, bindingWatch, nil
+		}
+		return true, watch.NewFake(), nil
+	})
+	getRbacClient = func(*options.RakkessOptions) (clientv1.RbacV1Interface, error) {
+		return fakeRbacClient, nil
+	}
+	return &watched
+}
+
+func TestWatchSubjectAccess(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	roleWatch := watch.NewFake()
+	bindingWatch := watch.NewFake()
+	watched := fakeWatchRbacClient(t, roleWatch, bindingWatch)
+	defer func() { getRbacClient = getRbacClientImpl }()
+
+	var updates []map[result.SubjectRef]sets.String
+
+	namespace := "prod"
+	opts := &options.RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{Namespace: &namespace}}
+	err := WatchSubjectAccess(ctx, opts, schema.GroupResource{Resource: "secrets"}, "", func(sa *result.SubjectAccess) error {
+		updates = append(updates, sa.Get())
+		// send the next event only after the previous one was applied
+		switch len(updates) {
+		case 1:
+			go bindingWatch.Add(userBinding("alice-reads", "prod", "Role", "reader", "alice"))
+		case 2:
+			go roleWatch.Modify(secretsRole("reader", "prod", "get", "list"))
+		case 3:
+			cancel()
+		}
+		return nil
+	})
+	require.Equal(t, context.Canceled, err)
+	assert.ElementsMatch(t, []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"}, *watched)
+
+	alice := result.SubjectRef{Name: "alice", Kind: "User"}
+	assert.Equal(t, []map[result.SubjectRef]sets.String{
+		{},
+		{alice: sets.NewString("get")},
+		{alice: sets.NewString("get", "list")},
+	}, updates)
+}
+
+func TestWatchSubjectAccess_debounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	roleWatch := watch.NewFake()
+	bindingWatch := watch.NewFake()
+	fakeWatchRbacClient(t, roleWatch, bindingWatch)
+	defer func() { getRbacClient = getRbacClientImpl }()
+
+	var updates []map[result.SubjectRef]sets.String
+
+	namespace := "prod"
+	opts := &options.RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{Namespace: &namespace}}
+	err := WatchSubjectAccess(ctx, opts, schema.GroupResource{Resource: "secrets"}, "", func(sa *result.SubjectAccess) error {
+		updates = append(updates, sa.Get())
+		switch len(updates) {
+		case 1:
+			// a burst of events is reported once
+			go func() {
+				bindingWatch.Add(userBinding("alice-reads", "prod", "Role", "reader", "alice"))
+				roleWatch.Modify(secretsRole("reader", "prod", "get", "list"))
+			}()
+		case 2:
+			cancel()
+		}
+		return nil
+	})
+	require.Equal(t, context.Canceled, err)
+
+	alice := result.SubjectRef{Name: "alice", Kind: "User"}
+	assert.Equal(t, []map[result.SubjectRef]sets.String{
+		{},
+		{alice: sets.NewString("get", "list")},
+	}, updates)
+}
@@ -18,13 +18,193 @@ package constants
 
 // Common or shared flags
 const (
-	FlagVerbs          = "verbs"
-	FlagServiceAccount = "sa"
-	FlagOutput         = "output"
-	FlagVerbosity      = "verbosity"
-	FlagDiffWith       = "diff-with"
+	FlagVerbs                      = "verbs"
+	FlagServiceAccount             = "sa"
+	FlagOutput                     = "output"
+	FlagVerbosity                  = "verbosity"
+	FlagDiffWith                   = "diff-with"
+	FlagOutputFile                 = "output-file"
+	FlagPreferredOnly              = "preferred-only"
+	FlagMinVerbs                   = "min-verbs"
+	FlagReviewer                   = "reviewer"
+	FlagCompress                   = "compress"
+	FlagSubject                    = "subject"
+	FlagIgnoreMasters              = "ignore-masters"
+	FlagResource                   = "resource"
+	FlagResourceVersion            = "resource-version"
+	FlagStats                      = "stats"
+	FlagResourceAnnotationSelector = "resource-annotation-selector"
+	FlagNonResourceURLs            = "non-resource-urls"
+	FlagGroupMembers               = "group-members"
+	FlagFromManifests              = "from-manifests"
+	FlagRequireAllowed             = "require-allowed"
+	FlagFailIfAllowed              = "fail-if-allowed"
+	FlagTokenFile                  = "token-file"
+	FlagSubjectNormalizer          = "subject-normalizer"
+	FlagName                       = "name"
+	FlagNoSAR                      = "no-sar"
+	FlagSubjectPrefix              = "subject-prefix"
+	FlagReportOrphanSubjects       = "report-orphan-subjects"
+	FlagAllNamespaces              = "all-namespaces"
+	FlagNamespaceColumnPosition    = "namespace-column-position"
+	FlagRBACOnly                   = "rbac-only"
+	FlagSpec                       = "spec"
+	FlagConfig                     = "config"
+	FlagEffectiveIdentity          = "effective-identity"
+	FlagChangedSince               = "changed-since"
+	FlagBindTo                     = "bind-to"
+	FlagBaseline                   = "baseline"
+	FlagBindingLabelSelector       = "binding-label-selector"
+	FlagAsNode                     = "as-node"
+	FlagExplainDeny                = "explain-deny"
+	FlagIntersect                  = "intersect"
+	FlagUnion                      = "union"
+	FlagSeparateTables             = "separate-tables"
+	FlagMyNamespaces               = "my-namespaces"
+	FlagDescribe                   = "describe"
+	FlagDescribeFile               = "describe-file"
+	FlagParallelism                = "parallelism"
+	FlagMaxConcurrency             = "max-concurrency"
+	FlagAutoParallelism            = "auto-parallelism"
+	FlagAssumeVerbsSupported       = "assume-verbs-supported"
+	FlagRiskTags                   = "risk-tags"
+	FlagPreset                     = "preset"
+	FlagSubjectKind                = "subject-kind"
+	FlagAsUID                      = "as-uid"
+	FlagNoPager                    = "no-pager"
+	FlagPager                      = "pager"
+	FlagExceeds                    = "exceeds"
+	FlagPaths                      = "paths"
+	FlagWatch                      = "watch"
+	FlagSubresource                = "subresource"
+	FlagVerbsAllOf                 = "verbs-all-of"
+	FlagVerbsAnyOf                 = "verbs-any-of"
+	FlagAPIGroup                   = "api-group"
+	FlagTop                        = "top"
+	FlagAsOther                    = "as-other"
+	FlagAsOtherGroup               = "as-other-group"
+	FlagShowEqual                  = "show-equal"
+	FlagAllowedOnly                = "allowed-only"
+	FlagNoCache                    = "no-cache"
+	FlagExitCode                   = "exit-code"
+	FlagNoProgress                 = "no-progress"
+	FlagTimeout                    = "timeout"
+	FlagSortBy                     = "sort-by"
+	FlagOnly                       = "only"
+	FlagContexts                   = "contexts"
+	FlagAllowCustomVerbs           = "allow-custom-verbs"
+	FlagSummary                    = "summary"
+	FlagHideNotApplicable          = "hide-not-applicable"
+	FlagGroupByAPI                 = "group-by-api"
+	FlagShowRoles                  = "show-roles"
+	FlagForceColors                = "force-colors"
+	FlagSubjectLabels              = "subject-labels"
+	FlagReportSummary              = "report-summary"
+	FlagMaxRetries                 = "max-retries"
 )
 
+// Output formats
+const (
+	OutputIconTable     = "icon-table"
+	OutputASCIITable    = "ascii-table"
+	OutputSQLite        = "sqlite"
+	OutputWide          = "wide"
+	OutputJSON          = "json"
+	OutputYAML          = "yaml"
+	OutputTree          = "tree"
+	OutputJUnit         = "junit"
+	OutputDigest        = "digest"
+	OutputLines         = "lines"
+	OutputGitHubComment = "github-comment"
+	OutputCSVLong       = "csv-long"
+	OutputProtobuf      = "protobuf"
+	OutputCSV           = "csv"
+	OutputMarkdown      = "markdown"
+	OutputHTML          = "html"
+	OutputList          = "list"
+)
+
+// Subject normalizers
+const (
+	NormalizeNone        = "none"
+	NormalizeLowercase   = "lowercase"
+	NormalizeStripDomain = "strip-domain"
+)
+
+// Subject prefixes
+const (
+	SubjectPrefixColumn = "column"
+	SubjectPrefixAbbrev = "abbrev"
+	SubjectPrefixEmoji  = "emoji"
+)
+
+// Positions of the namespace column
+const (
+	NamespaceColumnFirst = "first"
+	NamespaceColumnLast  = "last"
+)
+
+// Orders of the matrix rows
+const (
+	SortByName   = "name"
+	SortByGroup  = "group"
+	SortByAccess = "access"
+)
+
+// Filters of the matrix rows
+const (
+	OnlyAllowed = "allowed"
+	OnlyDenied  = "denied"
+)
+
+// Presets of the resource subcommand
+const (
+	PresetDefaultSA = "default-sa"
+)
+
+// CompressGzip is the only supported output compression.
+const CompressGzip = "gzip"
+
+// SystemRolePrefix is the name prefix of the default ClusterRoles which are
+// maintained by kubernetes itself.
+const SystemRolePrefix = "system:"
+
+// MastersGroup is the group which is bound to ClusterAdminRole in every cluster.
+const MastersGroup = "system:masters"
+
+// NodeUserPrefix is the user name prefix of node identities, which is
+// followed by the node name.
+const NodeUserPrefix = "system:node:"
+
+// NodesGroup is the group of all node identities.
+const NodesGroup = "system:nodes"
+
+// AccessDescription is a human-friendly description of a combination of verbs.
+type AccessDescription struct {
+	Description string   `json:"description"`
+	Verbs       []string `json:"verbs"`
+}
+
+// DefaultMaxConcurrency is the number of access reviews which are sent
+// concurrently, unless --max-concurrency says otherwise.
+const DefaultMaxConcurrency = 20
+
+// DefaultMaxRetries is the number of times an access review is retried after a
+// transient error, unless --max-retries says otherwise.
+const DefaultMaxRetries = 3
+
+// MaxRetriesLimit is the largest value which --max-retries accepts. With the
+// capped backoff, more retries would only stall a scan against a failing API
+// server.
+const MaxRetriesLimit = 10
+
+// MaxAutoParallelism caps the parallelism which --auto-parallelism chooses,
+// so that small clusters are not overwhelmed.
+const MaxAutoParallelism = 32
+
+// ClusterAdminRole is the ClusterRole which grants full access to all resources.
+const ClusterAdminRole = "cluster-admin"
+
 var (
 	// ValidVerbs is the list of allowed actions on kubernetes resources.
 	// Sort order aligned along CRUD.
@@ -39,9 +219,118 @@ var (
 		"deletecollection",
 	}
 
+	// SpecialVerbs are verbs which RBAC only knows for specific resources,
+	// such as impersonate on users. A wildcard verb in a rule grants these as well.
+	SpecialVerbs = []string{
+		"bind",
+		"escalate",
+		"impersonate",
+		"use",
+		"approve",
+		"sign",
+	}
+
+	// NonResourceVerbs are the verbs which apply to non-resource URLs.
+	NonResourceVerbs = []string{
+		"get",
+		"head",
+		"post",
+		"put",
+		"patch",
+		"delete",
+	}
+
 	// ValidOutputFormats is the list of valid formats for the result table.
 	ValidOutputFormats = []string{
-		"icon-table",
-		"ascii-table",
+		OutputIconTable,
+		OutputASCIITable,
+		OutputSQLite,
+		OutputWide,
+		OutputJSON,
+		OutputYAML,
+		OutputTree,
+		OutputJUnit,
+		OutputDigest,
+		OutputLines,
+		OutputGitHubComment,
+		OutputCSVLong,
+		OutputProtobuf,
+		OutputCSV,
+		OutputMarkdown,
+		OutputHTML,
+		OutputList,
+	}
+
+	// SubjectNormalizers are the built-in normalizations of subject names.
+	SubjectNormalizers = []string{
+		NormalizeNone,
+		NormalizeLowercase,
+		NormalizeStripDomain,
+	}
+
+	// SubjectPrefixes are the ways to show the kind of a subject.
+	SubjectPrefixes = []string{
+		SubjectPrefixColumn,
+		SubjectPrefixAbbrev,
+		SubjectPrefixEmoji,
+	}
+
+	// ResourceSortOrders are the orders of the rows of the access matrix.
+	ResourceSortOrders = []string{SortByGroup, SortByName, SortByAccess}
+
+	// SubjectSortOrders are the orders of the rows of the subject matrix.
+	SubjectSortOrders = []string{SortByName, SortByAccess}
+
+	// NodeResources are the resources which the kubelet reads or writes, and
+	// which are checked when reviewing the access of a node identity.
+	NodeResources = []string{
+		"nodes",
+		"pods",
+		"secrets",
+		"configmaps",
+		"serviceaccounts",
+		"services",
+		"endpoints",
+		"events",
+		"persistentvolumeclaims",
+		"persistentvolumes",
+		"leases.coordination.k8s.io",
+		"certificatesigningrequests.certificates.k8s.io",
+		"csinodes.storage.k8s.io",
+		"csidrivers.storage.k8s.io",
+		"volumeattachments.storage.k8s.io",
+		"runtimeclasses.node.k8s.io",
+	}
+
+	// DefaultNonResourceURLs are the non-resource URLs which are checked by
+	// the non-resource-urls subcommand, unless other paths are given.
+	DefaultNonResourceURLs = []string{
+		"/api",
+		"/apis",
+		"/healthz",
+		"/livez",
+		"/readyz",
+		"/version",
+		"/metrics",
+		"/openapi/v2",
+	}
+
+	// AccessDescriptions translate the allowed verbs into phrases for
+	// non-experts. Out of the descriptions whose verbs, restricted to the
+	// reviewed verbs, equal the allowed verbs, the one with the fewest verbs is used.
+	AccessDescriptions = []AccessDescription{
+		{Description: "full control", Verbs: ValidVerbs},
+		{Description: "read-write", Verbs: []string{"create", "get", "list", "watch", "update", "patch"}},
+		{Description: "read-only", Verbs: []string{"get", "list", "watch"}},
+	}
+
+	// BuiltinClusterRoles are the well-known user-facing ClusterRoles which
+	// come with every cluster. Together with all ClusterRoles prefixed by
+	// SystemRolePrefix, they are considered built-in.
+	BuiltinClusterRoles = []string{
+		"cluster-admin",
+		"admin",
+		"edit",
+		"view",
 	}
 )
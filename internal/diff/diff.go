@@ -76,3 +76,176 @@ func Diff(left, right result.ResourceAccess, verbs []string) *printer.Table {
 
 	return p
 }
+
+// CompareAccess compares the access matrices of two identities and produces a
+// printer with one row per resource where their access differs. Verbs which
+// only the right identity is allowed are marked as Gained, verbs which only the
+// left identity is allowed as Lost, and verbs where one review failed as Err.
+// Other verbs show the access which both have. With showEqual, resources with
+// identical access are also included.
+func CompareAccess(left, right result.ResourceAccess, verbs []string, showEqual bool) *printer.Table {
+	headers := []string{"NAME"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+
+	names := make([]string, 0, len(left))
+	for name := range left {
+		names = append(names, name)
+	}
+	for name := range right {
+		if _, ok := left[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	p := printer.TableWithHeaders(headers)
+	for _, name := range names {
+		l, r := left[name], right[name]
+		equal := true
+		outcomes := make([]printer.Outcome, 0, len(verbs))
+		for _, verb := range verbs {
+			o := compareOutcome(accessOf(l, verb), accessOf(r, verb))
+			if o == printer.Gained || o == printer.Lost || o == printer.Err {
+				equal = false
+			}
+			outcomes = append(outcomes, o)
+		}
+		if !equal || showEqual {
+			p.AddRow([]string{name}, outcomes...)
+		}
+	}
+	return p
+}
+
+// CompareContexts compares the access matrices of several kube-contexts and
+// produces a printer with one row per resource and verb where the access is
+// not the same in all contexts. Every context has its own column.
+func CompareContexts(contexts []string, access []result.ResourceAccess, verbs []string) *printer.Table {
+	headers := append([]string{"NAME", "VERB"}, contexts...)
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, ra := range access {
+		for name := range ra {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	p := printer.TableWithHeaders(headers)
+	for _, name := range names {
+		for _, verb := range verbs {
+			first := accessOf(access[0][name], verb)
+			equal := true
+			outcomes := make([]printer.Outcome, 0, len(access))
+			for _, ra := range access {
+				a := accessOf(ra[name], verb)
+				if a != first {
+					equal = false
+				}
+				outcomes = append(outcomes, contextOutcome(a))
+			}
+			if !equal {
+				p.AddRow([]string{name, verb}, outcomes...)
+			}
+		}
+	}
+	return p
+}
+
+func contextOutcome(a result.Access) printer.Outcome {
+	switch a {
+	case result.Allowed:
+		return printer.Up
+	case result.Denied:
+		return printer.Down
+	case result.RequestErr:
+		return printer.Err
+	}
+	return printer.None
+}
+
+// accessOf returns the access for the verb, where resources which are only
+// served for one identity count as not applicable for the other.
+func accessOf(access map[string]result.Access, verb string) result.Access {
+	if a, ok := access[verb]; ok {
+		return a
+	}
+	return result.NotApplicable
+}
+
+func compareOutcome(l, r result.Access) printer.Outcome {
+	switch {
+	case l == r && l == result.Allowed:
+		return printer.Up
+	case l == r && l == result.Denied:
+		return printer.Down
+	case l == result.RequestErr || r == result.RequestErr:
+		return printer.Err
+	case r == result.Allowed:
+		return printer.Gained
+	case l == result.Allowed:
+		return printer.Lost
+	}
+	// denied on one side and not applicable on the other
+	return printer.None
+}
+
+// SubjectDiff takes two subject access results and produces a printer that
+// contains only the subjects whose verbs differ. Verbs which are only granted in
+// right are marked as Up, and verbs which are only granted in left as Down.
+func SubjectDiff(left, right *result.SubjectAccess, verbs []string) *printer.Table {
+	headers := []string{"NAME", "KIND", "SA-NAMESPACE"}
+	for _, v := range verbs {
+		headers = append(headers, strings.ToUpper(v))
+	}
+	p := printer.TableWithHeaders(headers)
+
+	l, r := left.Get(), right.Get()
+	subjects := make([]result.SubjectRef, 0, len(l)+len(r))
+	for s := range l {
+		subjects = append(subjects, s)
+	}
+	for s := range r {
+		if _, ok := l[s]; !ok {
+			subjects = append(subjects, s)
+		}
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		if subjects[i].Name != subjects[j].Name {
+			return subjects[i].Name < subjects[j].Name
+		}
+		if subjects[i].Kind != subjects[j].Kind {
+			return subjects[i].Kind < subjects[j].Kind
+		}
+		return subjects[i].Namespace < subjects[j].Namespace
+	})
+
+	for _, s := range subjects {
+		skip := true
+		var outcomes []printer.Outcome
+		for _, verb := range verbs {
+			ll, rr := l[s].Has(verb), r[s].Has(verb)
+			var o printer.Outcome
+			if ll != rr {
+				skip = false
+				if ll {
+					o = printer.Down
+				} else {
+					o = printer.Up
+				}
+			}
+			outcomes = append(outcomes, o)
+		}
+		if !skip {
+			p.AddRow([]string{s.Name, s.Kind, s.Namespace}, outcomes...)
+		}
+	}
+
+	return p
+}
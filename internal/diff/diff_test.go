@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func subjectAccess(grants map[string][]string) *result.SubjectAccess {
+	sa := result.NewSubjectAccess(schema.GroupResource{Resource: "secrets"}, "")
+	for user, verbs := range grants {
+		r := result.RoleRef{Name: user + "-role", Kind: "Role"}
+		sa.MatchRules(r, v1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: verbs})
+		sa.ResolveRoleRef(r, result.BindingRef{Name: user, Kind: "RoleBinding"}, []v1.Subject{{Name: user, Kind: "User"}})
+	}
+	return sa
+}
+
+func TestCompareAccess(t *testing.T) {
+	left := result.ResourceAccess{
+		"pods":             {"get": result.Allowed, "list": result.Allowed, "delete": result.Denied},
+		"secrets":          {"get": result.Allowed, "list": result.Denied, "delete": result.Denied},
+		"deployments.apps": {"get": result.Allowed, "list": result.RequestErr, "delete": result.Denied},
+		"only-left":        {"get": result.Allowed, "list": result.Denied, "delete": result.Denied},
+	}
+	right := result.ResourceAccess{
+		"pods":             {"get": result.Allowed, "list": result.Allowed, "delete": result.Denied},
+		"secrets":          {"get": result.Denied, "list": result.Allowed, "delete": result.Denied},
+		"deployments.apps": {"get": result.Allowed, "list": result.Allowed, "delete": result.Denied},
+		"only-right":       {"get": result.NotApplicable, "list": result.Allowed, "delete": result.Denied},
+	}
+	verbs := []string{"get", "list", "delete"}
+
+	table := CompareAccess(left, right, verbs, false)
+	assert.Equal(t, []string{"NAME", "GET", "LIST", "DELETE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"deployments.apps"}, Entries: []printer.Outcome{printer.Up, printer.Err, printer.Down}},
+		{Intro: []string{"only-left"}, Entries: []printer.Outcome{printer.Lost, printer.None, printer.None}},
+		{Intro: []string{"only-right"}, Entries: []printer.Outcome{printer.None, printer.Gained, printer.None}},
+		{Intro: []string{"secrets"}, Entries: []printer.Outcome{printer.Lost, printer.Gained, printer.Down}},
+	}, table.Rows)
+
+	table = CompareAccess(left, right, verbs, true)
+	assert.Len(t, table.Rows, 5)
+	assert.Equal(t, printer.Row{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Up, printer.Up, printer.Down}}, table.Rows[3])
+}
+
+func TestCompareContexts(t *testing.T) {
+	staging := result.ResourceAccess{
+		"pods":    {"get": result.Allowed, "delete": result.Denied},
+		"secrets": {"get": result.Allowed, "delete": result.Denied},
+	}
+	prod := result.ResourceAccess{
+		"pods":      {"get": result.Allowed, "delete": result.Denied},
+		"secrets":   {"get": result.Denied, "delete": result.RequestErr},
+		"only-prod": {"get": result.Allowed, "delete": result.Denied},
+	}
+
+	table := CompareContexts([]string{"staging", "prod"}, []result.ResourceAccess{staging, prod}, []string{"get", "delete"})
+
+	assert.Equal(t, []string{"NAME", "VERB", "staging", "prod"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"only-prod", "get"}, Entries: []printer.Outcome{printer.None, printer.Up}},
+		{Intro: []string{"only-prod", "delete"}, Entries: []printer.Outcome{printer.None, printer.Down}},
+		{Intro: []string{"secrets", "get"}, Entries: []printer.Outcome{printer.Up, printer.Down}},
+		{Intro: []string{"secrets", "delete"}, Entries: []printer.Outcome{printer.Down, printer.Err}},
+	}, table.Rows)
+}
+
+func TestSubjectDiff(t *testing.T) {
+	left := subjectAccess(map[string][]string{
+		"same":      {"get", "list"},
+		"only-left": {"get"},
+		"changed":   {"get", "delete"},
+	})
+	right := subjectAccess(map[string][]string{
+		"same":       {"get", "list"},
+		"only-right": {"list"},
+		"changed":    {"get", "list"},
+	})
+
+	table := SubjectDiff(left, right, []string{"get", "list", "delete"})
+
+	assert.Equal(t, []string{"NAME", "KIND", "SA-NAMESPACE", "GET", "LIST", "DELETE"}, table.Headers)
+	assert.Equal(t, []printer.Row{
+		{Intro: []string{"changed", "User", ""}, Entries: []printer.Outcome{printer.None, printer.Up, printer.Down}},
+		{Intro: []string{"only-left", "User", ""}, Entries: []printer.Outcome{printer.Down, printer.None, printer.None}},
+		{Intro: []string{"only-right", "User", ""}, Entries: []printer.Outcome{printer.None, printer.Up, printer.None}},
+	}, table.Rows)
+}
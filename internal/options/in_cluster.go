@@ -0,0 +1,70 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// kubeConfig loads the client configuration from the kubeconfig. Without a
+// kubeconfig, client-go falls back to the in-cluster configuration of the
+// pod's service-account, e.g. for a CronJob. That fallback ignores --as and
+// --as-group, so that they are applied here.
+func (o *RakkessOptions) kubeConfig() (*rest.Config, error) {
+	if !o.hasKubeConfig() {
+		if os.Getenv(serviceHostEnv) == "" {
+			return nil, fmt.Errorf("no kubeconfig found, and not running in a cluster (%s is not set)", serviceHostEnv)
+		}
+		klog.V(2).Info("No kubeconfig found, using the in-cluster configuration")
+		restConfig, err := o.ConfigFlags.ToRESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		return o.withImpersonation(restConfig), nil
+	}
+	klog.V(2).Info("Using the kubeconfig")
+	return o.ConfigFlags.ToRESTConfig()
+}
+
+// serviceHostEnv is set by kubernetes in every pod.
+const serviceHostEnv = "KUBERNETES_SERVICE_HOST"
+
+// hasKubeConfig tells whether a kubeconfig with a cluster or a server is
+// given. Otherwise, client-go falls back to the in-cluster configuration.
+func (o *RakkessOptions) hasKubeConfig() bool {
+	if s := o.ConfigFlags.APIServer; s != nil && *s != "" {
+		return true
+	}
+	raw, err := o.ConfigFlags.ToRawKubeConfigLoader().RawConfig()
+	return err != nil || len(raw.Clusters) > 0
+}
+
+// withImpersonation impersonates the user and groups of the ConfigFlags.
+func (o *RakkessOptions) withImpersonation(c *rest.Config) *rest.Config {
+	c = rest.CopyConfig(c)
+	if user := o.ConfigFlags.Impersonate; user != nil && *user != "" {
+		c.Impersonate.UserName = *user
+	}
+	if groups := o.ConfigFlags.ImpersonateGroup; groups != nil && len(*groups) > 0 {
+		c.Impersonate.Groups = *groups
+	}
+	return c
+}
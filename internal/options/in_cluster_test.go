@@ -0,0 +1,56 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+func TestHasKubeConfig(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	o := &RakkessOptions{ConfigFlags: genericclioptions.NewConfigFlags(false)}
+	assert.False(t, o.hasKubeConfig())
+
+	server := "https://example.com"
+	o.ConfigFlags.APIServer = &server
+	assert.True(t, o.hasKubeConfig(), "an explicit server takes precedence")
+}
+
+func TestKubeConfig_noConfig(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv(serviceHostEnv, "")
+
+	o := &RakkessOptions{ConfigFlags: genericclioptions.NewConfigFlags(false)}
+	_, err := o.kubeConfig()
+	assert.EqualError(t, err, "no kubeconfig found, and not running in a cluster (KUBERNETES_SERVICE_HOST is not set)")
+}
+
+func TestWithImpersonation(t *testing.T) {
+	user, groups := "alice", []string{"developers"}
+	o := &RakkessOptions{ConfigFlags: &genericclioptions.ConfigFlags{Impersonate: &user, ImpersonateGroup: &groups}}
+	original := &rest.Config{Host: "https://10.0.0.1"}
+
+	c := o.withImpersonation(original)
+	assert.Equal(t, rest.ImpersonationConfig{UserName: "alice", Groups: []string{"developers"}}, c.Impersonate)
+	assert.Empty(t, original.Impersonate.UserName, "the config must not be modified")
+}
@@ -19,23 +19,97 @@ package options
 import (
 	"bytes"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	v1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 )
 
 // RakkessOptions holds all user configuration options.
 type RakkessOptions struct {
-	ConfigFlags      *genericclioptions.ConfigFlags
-	Verbs            []string
-	AsServiceAccount string
-	OutputFormat     string
-	Streams          *genericclioptions.IOStreams
+	ConfigFlags                *genericclioptions.ConfigFlags
+	Verbs                      []string
+	AsServiceAccount           string
+	OutputFormat               string
+	OutputFile                 string
+	Compress                   string
+	PreferredOnly              bool
+	MinVerbs                   int
+	Subject                    []string
+	IgnoreMasters              bool
+	ResourceVersion            string
+	Stats                      bool
+	ResourceAnnotationSelector string
+	GroupMembersFile           string
+	RequireAllowed             []string
+	FailIfAllowed              []string
+	TokenFile                  string
+	SubjectNormalizer          []string
+	NoSAR                      bool
+	SubjectPrefix              string
+	ReportOrphanSubjects       bool
+	AllNamespaces              bool
+	NamespaceColumnPosition    string
+	RBACOnly                   bool
+	SpecFile                   string
+	EffectiveIdentity          bool
+	Exceeds                    string
+	VerbsAllOf                 []string
+	VerbsAnyOf                 []string
+	ShowRoles                  bool
+	ForceColors                bool
+	ChangedSince               time.Duration
+	BindingLabelSelector       string
+	SubjectLabelSelector       string
+	AsNode                     string
+	ExplainDeny                bool
+	Intersect                  bool
+	Union                      bool
+	SeparateTables             bool
+	MyNamespaces               bool
+	Describe                   bool
+	DescribeFile               string
+	MaxConcurrency             int
+	MaxRetries                 int
+	AutoParallelism            bool
+	AssumeVerbsSupported       bool
+	RiskTagsFile               string
+	SubjectKinds               []string
+	ImpersonateUID             string
+	NoPager                    bool
+	Pager                      bool
+	Watch                      bool
+	Subresources               []string
+	APIGroups                  []string
+	AllowedOnly                bool
+	NoCache                    bool
+	DiscoverVerbs              bool
+	ResourceName               string
+	Resources                  []string
+	ResourcePatterns           []string
+	ExitCode                   bool
+	NoProgress                 bool
+	Timeout                    time.Duration
+	SortBy                     string
+	Only                       string
+	Contexts                   []string
+	AllowCustomVerbs           bool
+	ReportSummary              bool
+	Summary                    bool
+	HideNotApplicable          bool
+	GroupByAPI                 bool
+	Streams                    *genericclioptions.IOStreams
+	// RESTConfig replaces the client configuration from ConfigFlags, if set.
+	// The kubeconfig is not read then, which is how the library uses rakkess.
+	RESTConfig *rest.Config
 }
 
 // NewRakkessOptions creates RakkessOptions with defaults.
@@ -62,7 +136,7 @@ func NewTestRakkessOptions() (*RakkessOptions, *bytes.Buffer, *bytes.Buffer, *by
 
 // GetAuthClient creates a client for SelfSubjectAccessReviews with high queries per second.
 func (o *RakkessOptions) GetAuthClient() (v1.SelfSubjectAccessReviewInterface, error) {
-	restConfig, err := o.ConfigFlags.ToRESTConfig()
+	restConfig, err := o.ToRESTConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +148,98 @@ func (o *RakkessOptions) GetAuthClient() (v1.SelfSubjectAccessReviewInterface, e
 	return authClient.SelfSubjectAccessReviews(), nil
 }
 
-// DiscoveryClient creates a kubernetes discovery client.
+// GetRulesReviewClient creates a client for SelfSubjectRulesReviews.
+func (o *RakkessOptions) GetRulesReviewClient() (v1.SelfSubjectRulesReviewInterface, error) {
+	restConfig, err := o.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return v1.NewForConfigOrDie(restConfig).SelfSubjectRulesReviews(), nil
+}
+
+// ToRESTConfig creates the client configuration from RESTConfig or from the
+// ConfigFlags. It impersonates ImpersonateUID in addition to the user and
+// groups of the ConfigFlags.
+func (o *RakkessOptions) ToRESTConfig() (*rest.Config, error) {
+	var restConfig *rest.Config
+	if o.RESTConfig != nil {
+		restConfig = rest.CopyConfig(o.RESTConfig)
+	} else {
+		var err error
+		if restConfig, err = o.kubeConfig(); err != nil {
+			return nil, err
+		}
+	}
+	if o.ImpersonateUID != "" {
+		uid := o.ImpersonateUID
+		restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &uidRoundTripper{uid: uid, delegate: rt}
+		})
+	}
+	return restConfig, nil
+}
+
+// uidRoundTripper sets the Impersonate-Uid header, which the impersonation
+// config of this client-go version does not know yet.
+type uidRoundTripper struct {
+	uid      string
+	delegate http.RoundTripper
+}
+
+func (rt *uidRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(impersonateUIDHeader, rt.uid)
+	return rt.delegate.RoundTrip(req)
+}
+
+const impersonateUIDHeader = "Impersonate-Uid"
+
+// Impersonation describes the impersonated identity, or returns the empty
+// string if the current user is not impersonating anybody.
+func (o *RakkessOptions) Impersonation() string {
+	var user string
+	if o.ConfigFlags.Impersonate != nil {
+		user = *o.ConfigFlags.Impersonate
+	}
+	var groups []string
+	if o.ConfigFlags.ImpersonateGroup != nil {
+		groups = *o.ConfigFlags.ImpersonateGroup
+	}
+	if user == "" && len(groups) == 0 {
+		return ""
+	}
+	identity := fmt.Sprintf("user %q", user)
+	if len(groups) > 0 {
+		identity += fmt.Sprintf(" in groups %v", groups)
+	}
+	if o.ImpersonateUID != "" {
+		identity += fmt.Sprintf(" with uid %q", o.ImpersonateUID)
+	}
+	return identity
+}
+
+// ValidateImpersonateUID checks that ImpersonateUID is only given together with
+// a user to impersonate, as the API server requires.
+func (o *RakkessOptions) ValidateImpersonateUID() error {
+	if o.ImpersonateUID == "" {
+		return nil
+	}
+	if o.ConfigFlags.Impersonate == nil || *o.ConfigFlags.Impersonate == "" {
+		return fmt.Errorf("--%s requires --as or --%s", constants.FlagAsUID, constants.FlagServiceAccount)
+	}
+	return nil
+}
+
+// DiscoveryClient creates a kubernetes discovery client. With RESTConfig,
+// discovery is only cached in memory.
 func (o *RakkessOptions) DiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	if o.RESTConfig != nil {
+		client, err := discovery.NewDiscoveryClientForConfig(rest.CopyConfig(o.RESTConfig))
+		if err != nil {
+			return nil, err
+		}
+		return memory.NewMemCacheClient(client), nil
+	}
 	return o.ConfigFlags.ToDiscoveryClient()
 }
 
@@ -95,6 +259,24 @@ func (o *RakkessOptions) ExpandServiceAccount() error {
 	return nil
 }
 
+// ExpandNode impersonates the node identity with the name AsNode, which is
+// the user system:node:<name> in the group system:nodes.
+func (o *RakkessOptions) ExpandNode() error {
+	if o.AsNode == "" {
+		return nil
+	}
+	if o.AsServiceAccount != "" {
+		return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagAsNode, constants.FlagServiceAccount)
+	}
+
+	impersonate := constants.NodeUserPrefix + o.AsNode
+	groups := []string{constants.NodesGroup, "system:authenticated"}
+	klog.V(2).Infof("Impersonating as %s in groups %v", impersonate, groups)
+	o.ConfigFlags.Impersonate = &impersonate
+	o.ConfigFlags.ImpersonateGroup = &groups
+	return nil
+}
+
 func (o *RakkessOptions) namespacedServiceAccount() (string, error) {
 	if strings.Contains(o.AsServiceAccount, ":") {
 		return o.AsServiceAccount, nil
@@ -107,11 +289,23 @@ func (o *RakkessOptions) namespacedServiceAccount() (string, error) {
 	return "", fmt.Errorf("serviceAccounts are namespaced, either provide --namespace or fully qualify the serviceAccount: '<namespace>:%s'", o.AsServiceAccount)
 }
 
-// ExpandVerbs expands wildcard verbs `*` and `all`.
+// ExpandVerbs expands wildcard verbs `*` and `all` to all verbs in canonical
+// order. Explicitly given verbs keep their order, so that the columns appear
+// as typed. Duplicate verbs are dropped. For the shorthands, DiscoverVerbs is
+// set, so that the access matrix checks the verbs which discovery lists instead.
 func (o *RakkessOptions) ExpandVerbs() {
+	seen := make(map[string]bool, len(o.Verbs))
+	var verbs []string
 	for _, verb := range o.Verbs {
 		if verb == "*" || verb == "all" {
-			o.Verbs = constants.ValidVerbs
+			o.DiscoverVerbs = true
+			o.Verbs = append([]string(nil), constants.ValidVerbs...)
+			return
+		}
+		if !seen[verb] {
+			seen[verb] = true
+			verbs = append(verbs, verb)
 		}
 	}
+	o.Verbs = verbs
 }
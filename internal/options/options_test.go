@@ -17,6 +17,7 @@ limitations under the License.
 package options
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/corneliusweig/rakkess/internal/constants"
@@ -40,6 +41,11 @@ func TestRakkessOptions_ExpandVerbs(t *testing.T) {
 			input:    []string{"*"},
 			expected: constants.ValidVerbs,
 		},
+		{
+			name:     "all shorthand",
+			input:    []string{"all"},
+			expected: constants.ValidVerbs,
+		},
 		{
 			name:     "wildcard mixed with other verbs",
 			input:    []string{"list", "*", "get"},
@@ -50,6 +56,21 @@ func TestRakkessOptions_ExpandVerbs(t *testing.T) {
 			input:    []string{"list", "get"},
 			expected: []string{"list", "get"},
 		},
+		{
+			name:     "explicit order is kept",
+			input:    []string{"patch", "delete", "get", "create"},
+			expected: []string{"patch", "delete", "get", "create"},
+		},
+		{
+			name:     "duplicates are dropped",
+			input:    []string{"get", "list", "get"},
+			expected: []string{"get", "list"},
+		},
+		{
+			name:     "duplicates keep their first position",
+			input:    []string{"delete", "get", "delete", "create"},
+			expected: []string{"delete", "get", "create"},
+		},
 	}
 
 	for _, test := range tests {
@@ -62,6 +83,27 @@ func TestRakkessOptions_ExpandVerbs(t *testing.T) {
 	}
 }
 
+func TestRakkessOptions_ExpandVerbs_discoverVerbs(t *testing.T) {
+	opts := &RakkessOptions{Verbs: []string{"all"}}
+	opts.ExpandVerbs()
+	assert.True(t, opts.DiscoverVerbs)
+
+	opts = &RakkessOptions{Verbs: []string{"*"}}
+	opts.ExpandVerbs()
+	assert.True(t, opts.DiscoverVerbs, "* is the same as all")
+
+	opts = &RakkessOptions{Verbs: []string{"get", "list"}}
+	opts.ExpandVerbs()
+	assert.False(t, opts.DiscoverVerbs)
+}
+
+func TestRakkessOptions_ExpandVerbs_copiesValidVerbs(t *testing.T) {
+	opts := &RakkessOptions{Verbs: []string{"*"}}
+	opts.ExpandVerbs()
+	opts.Verbs[0] = "changed"
+	assert.Equal(t, "create", constants.ValidVerbs[0])
+}
+
 func TestRakkessOptions_ExpandServiceAccount(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -114,3 +156,50 @@ func TestRakkessOptions_ExpandServiceAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestRakkessOptions_ExpandNode(t *testing.T) {
+	opts := &RakkessOptions{ConfigFlags: genericclioptions.NewConfigFlags(false)}
+	assert.NoError(t, opts.ExpandNode())
+	assert.Empty(t, *opts.ConfigFlags.Impersonate)
+
+	opts.AsNode = "worker-1"
+	assert.NoError(t, opts.ExpandNode())
+	assert.Equal(t, "system:node:worker-1", *opts.ConfigFlags.Impersonate)
+	assert.Equal(t, []string{"system:nodes", "system:authenticated"}, *opts.ConfigFlags.ImpersonateGroup)
+
+	opts.AsServiceAccount = "kube-system:default"
+	assert.Error(t, opts.ExpandNode())
+}
+
+func TestRakkessOptions_Impersonation(t *testing.T) {
+	opts := &RakkessOptions{ConfigFlags: genericclioptions.NewConfigFlags(false)}
+	assert.Empty(t, opts.Impersonation())
+	assert.NoError(t, opts.ValidateImpersonateUID())
+
+	opts.ImpersonateUID = "1234"
+	assert.Error(t, opts.ValidateImpersonateUID())
+
+	*opts.ConfigFlags.Impersonate = "alice"
+	*opts.ConfigFlags.ImpersonateGroup = []string{"devs"}
+	assert.NoError(t, opts.ValidateImpersonateUID())
+	assert.Equal(t, `user "alice" in groups [devs] with uid "1234"`, opts.Impersonation())
+}
+
+func TestUIDRoundTripper(t *testing.T) {
+	var got string
+	rt := &uidRoundTripper{uid: "1234", delegate: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get(impersonateUIDHeader)
+		return &http.Response{}, nil
+	})}
+	req, _ := http.NewRequest(http.MethodGet, "https://cluster", nil)
+	_, err := rt.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", got)
+	assert.Empty(t, req.Header.Get(impersonateUIDHeader))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
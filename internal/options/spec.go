@@ -0,0 +1,141 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// Spec describes a complete audit query, so that it can be versioned and
+// repeated. Every field corresponds to a command-line flag.
+type Spec struct {
+	Verbs                      []string `json:"verbs,omitempty"`
+	Resources                  []string `json:"resources,omitempty"`
+	APIGroups                  []string `json:"apiGroups,omitempty"`
+	Namespace                  string   `json:"namespace,omitempty"`
+	AllNamespaces              *bool    `json:"allNamespaces,omitempty"`
+	ServiceAccount             string   `json:"serviceAccount,omitempty"`
+	Subjects                   []string `json:"subjects,omitempty"`
+	SubjectKinds               []string `json:"subjectKinds,omitempty"`
+	ResourceAnnotationSelector string   `json:"resourceAnnotationSelector,omitempty"`
+	PreferredOnly              *bool    `json:"preferredOnly,omitempty"`
+	IgnoreMasters              *bool    `json:"ignoreMasters,omitempty"`
+	MinVerbs                   *int     `json:"minVerbs,omitempty"`
+	RequireAllowed             []string `json:"requireAllowed,omitempty"`
+	FailIfAllowed              []string `json:"failIfAllowed,omitempty"`
+	Output                     string   `json:"output,omitempty"`
+	OutputFile                 string   `json:"outputFile,omitempty"`
+}
+
+// specValue is a spec field in the form of a flag value.
+type specValue struct {
+	field, flag, value string
+}
+
+// values lists the fields which are set in the spec, ordered as declared.
+func (s *Spec) values() []specValue {
+	var values []specValue
+	str := func(field, flag, v string) {
+		if v != "" {
+			values = append(values, specValue{field, flag, v})
+		}
+	}
+	slice := func(field, flag string, v []string) {
+		if v != nil {
+			values = append(values, specValue{field, flag, strings.Join(v, ",")})
+		}
+	}
+	boolean := func(field, flag string, v *bool) {
+		if v != nil {
+			values = append(values, specValue{field, flag, strconv.FormatBool(*v)})
+		}
+	}
+
+	slice("verbs", constants.FlagVerbs, s.Verbs)
+	slice("apiGroups", constants.FlagAPIGroup, s.APIGroups)
+	str("namespace", "namespace", s.Namespace)
+	boolean("allNamespaces", constants.FlagAllNamespaces, s.AllNamespaces)
+	str("serviceAccount", constants.FlagServiceAccount, s.ServiceAccount)
+	slice("subjects", constants.FlagSubject, s.Subjects)
+	slice("subjectKinds", constants.FlagSubjectKind, s.SubjectKinds)
+	str("resourceAnnotationSelector", constants.FlagResourceAnnotationSelector, s.ResourceAnnotationSelector)
+	boolean("preferredOnly", constants.FlagPreferredOnly, s.PreferredOnly)
+	boolean("ignoreMasters", constants.FlagIgnoreMasters, s.IgnoreMasters)
+	if s.MinVerbs != nil {
+		values = append(values, specValue{"minVerbs", constants.FlagMinVerbs, strconv.Itoa(*s.MinVerbs)})
+	}
+	slice("requireAllowed", constants.FlagRequireAllowed, s.RequireAllowed)
+	slice("failIfAllowed", constants.FlagFailIfAllowed, s.FailIfAllowed)
+	str("output", constants.FlagOutput, s.Output)
+	str("outputFile", constants.FlagOutputFile, s.OutputFile)
+	return values
+}
+
+// LoadSpec reads an audit spec from the given YAML or JSON file.
+// Unknown fields are rejected.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read spec")
+	}
+	var spec Spec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, errors.Wrapf(err, "parse spec %s", path)
+	}
+	return &spec, nil
+}
+
+// ExpandSpec applies the audit spec in SpecFile to the flags of the running
+// command. Flags given on the command-line take precedence over the spec,
+// which in turn takes precedence over the flag defaults. The resources of the
+// spec are kept in Resources, unless they are given as arguments.
+func (o *RakkessOptions) ExpandSpec(flags *pflag.FlagSet) error {
+	if o.SpecFile == "" {
+		return nil
+	}
+	spec, err := LoadSpec(o.SpecFile)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range spec.values() {
+		flag := flags.Lookup(v.flag)
+		if flag == nil {
+			return fmt.Errorf("spec %s: field %q is not supported by this command", o.SpecFile, v.field)
+		}
+		if flag.Changed {
+			klog.V(2).Infof("Flag --%s overrides field %q of the spec", v.flag, v.field)
+			continue
+		}
+		if err := flags.Set(v.flag, v.value); err != nil {
+			return errors.Wrapf(err, "spec %s: invalid value for field %q", o.SpecFile, v.field)
+		}
+	}
+	if spec.Resources != nil {
+		o.Resources = spec.Resources
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		args          []string
+		expectedVerbs []string
+		expectedNs    string
+		expectedOut   string
+		expectedRes   []string
+		expectedAPIs  []string
+		expectedErr   string
+	}{
+		{
+			name:          "spec values",
+			spec:          "verbs: [get, list]\nnamespace: prod\noutput: ascii-table\n",
+			expectedVerbs: []string{"get", "list"},
+			expectedNs:    "prod",
+			expectedOut:   "ascii-table",
+		},
+		{
+			name:          "flags take precedence",
+			spec:          "verbs: [get, list]\nnamespace: prod\n",
+			args:          []string{"--verbs=delete", "-n=dev"},
+			expectedVerbs: []string{"delete"},
+			expectedNs:    "dev",
+			expectedOut:   "icon-table",
+		},
+		{
+			name:          "resources and api groups",
+			spec:          "resources: [pods, deployments.apps]\napiGroups: [core, apps]\n",
+			args:          []string{"--api-group=apps"},
+			expectedVerbs: []string{"list"},
+			expectedOut:   "icon-table",
+			expectedRes:   []string{"pods", "deployments.apps"},
+			expectedAPIs:  []string{"apps"},
+		},
+		{
+			name:        "unknown field",
+			spec:        "verb: [get]\n",
+			expectedErr: `unknown field "verb"`,
+		},
+		{
+			name:        "wrong type",
+			spec:        "verbs: get\n",
+			expectedErr: "cannot unmarshal string",
+		},
+		{
+			name:        "field without flag",
+			spec:        "subjects: [user:alice]\n",
+			expectedErr: `field "subjects" is not supported by this command`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			specFile := filepath.Join(t.TempDir(), "spec.yaml")
+			require.NoError(t, ioutil.WriteFile(specFile, []byte(test.spec), 0o600))
+
+			opts, _, _, _ := NewTestRakkessOptions()
+			opts.SpecFile = specFile
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.StringSliceVar(&opts.Verbs, constants.FlagVerbs, []string{"list"}, "")
+			flags.StringVarP(&opts.OutputFormat, constants.FlagOutput, "o", "icon-table", "")
+			flags.StringSliceVar(&opts.APIGroups, constants.FlagAPIGroup, nil, "")
+			opts.ConfigFlags.AddFlags(flags)
+			require.NoError(t, flags.Parse(test.args))
+
+			err := opts.ExpandSpec(flags)
+			if test.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedVerbs, opts.Verbs)
+			assert.Equal(t, test.expectedNs, *opts.ConfigFlags.Namespace)
+			assert.Equal(t, test.expectedOut, opts.OutputFormat)
+			assert.Equal(t, test.expectedRes, opts.Resources)
+			assert.Equal(t, test.expectedAPIs, opts.APIGroups)
+		})
+	}
+}
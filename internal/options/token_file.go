@@ -0,0 +1,107 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"k8s.io/klog/v2"
+)
+
+// ExpandTokenFile configures the clients to authenticate with the bearer token
+// in TokenFile instead of the credentials from the kubeconfig. The token is
+// re-read as it rotates, and whenever the API server rejects it.
+func (o *RakkessOptions) ExpandTokenFile() {
+	if o.TokenFile == "" {
+		return
+	}
+	klog.V(2).Infof("Authenticating with token from %s", o.TokenFile)
+	source := transport.NewCachedFileTokenSource(o.TokenFile)
+	o.ConfigFlags.WrapConfigFn = func(c *rest.Config) *rest.Config {
+		return withTokenSource(c, source)
+	}
+}
+
+// withTokenSource replaces all credentials of the config by the token source.
+func withTokenSource(c *rest.Config, source transport.ResettableTokenSource) *rest.Config {
+	c = rest.CopyConfig(c)
+	c.BearerToken = ""
+	c.BearerTokenFile = ""
+	c.Username = ""
+	c.Password = ""
+	c.AuthProvider = nil
+	c.ExecProvider = nil
+	c.TLSClientConfig.CertFile = ""
+	c.TLSClientConfig.KeyFile = ""
+	c.TLSClientConfig.CertData = nil
+	c.TLSClientConfig.KeyData = nil
+	c.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &tokenRoundTripper{source: source, base: rt}
+	})
+	return c
+}
+
+// tokenRoundTripper sets the bearer token from the token source. When a
+// request is unauthorized, the token may have been rotated in the meantime,
+// so the token is reloaded and the request is retried once.
+type tokenRoundTripper struct {
+	source transport.ResettableTokenSource
+	base   http.RoundTripper
+}
+
+func (t *tokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.roundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	t.source.ResetTokenOlderThan(start)
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil // the request body cannot be replayed
+	}
+
+	retry := net.CloneRequest(req)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	klog.V(2).Infof("Request to %s was unauthorized, retrying with reloaded token", req.URL.Path)
+	return t.roundTrip(retry)
+}
+
+func (t *tokenRoundTripper) roundTrip(req *http.Request) (*http.Response, error) {
+	tok, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	req = net.CloneRequest(req)
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return t.base.RoundTrip(req)
+}
+
+func (t *tokenRoundTripper) WrappedRoundTripper() http.RoundTripper { return t.base }
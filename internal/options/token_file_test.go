@@ -0,0 +1,67 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+func TestWithTokenSource(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("old\n"), 0o600))
+
+	valid := "old"
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		auth := r.Header.Get("Authorization")
+		seen = append(seen, auth+" "+string(body))
+		if auth != "Bearer "+valid {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	config := withTokenSource(&rest.Config{Host: server.URL, BearerToken: "kubeconfig"}, transport.NewCachedFileTokenSource(tokenFile))
+	assert.Empty(t, config.BearerToken)
+	rt, err := rest.TransportFor(config)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rt}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("first"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// rotate the token, the cached one is now rejected
+	valid = "new"
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("new\n"), 0o600))
+
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("second"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer old first", "Bearer old second", "Bearer new second"}, seen)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+)
+
+// outputWriter opens the configured output destination. The parent directories
+// of the output file are created if needed. When compression is requested, or
+// the output file ends in .gz, the output is gzip compressed. Output for a
+// terminal goes through a pager, unless --no-pager is given or the matrix is
+// watched. With --pager, the output always goes through the pager. The
+// returned writer must be closed to flush all data.
+func outputWriter(opts *options.RakkessOptions) (io.WriteCloser, error) {
+	if opts.OutputFile == "" && opts.Compress == "" {
+		return stdoutWriter(opts), nil
+	}
+	var out io.WriteCloser = nopCloser{opts.Streams.Out}
+	if opts.OutputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.OutputFile), 0755); err != nil {
+			return nil, errors.Wrap(err, "create output directory")
+		}
+		f, err := os.Create(opts.OutputFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "create output file")
+		}
+		out = f
+	}
+	if opts.Compress == constants.CompressGzip || strings.HasSuffix(opts.OutputFile, ".gz") {
+		return &gzipWriter{Writer: gzip.NewWriter(out), underlying: out}, nil
+	}
+	return out, nil
+}
+
+// stdoutWriter writes to the standard output, through a pager on a terminal
+// as for outputWriter.
+func stdoutWriter(opts *options.RakkessOptions) io.WriteCloser {
+	if opts.Pager {
+		return &pagerWriter{terminal: opts.Streams.Out, always: true}
+	}
+	if !opts.NoPager && !opts.Watch && isTerminal(opts.Streams.Out) {
+		return &pagerWriter{terminal: opts.Streams.Out}
+	}
+	return nopCloser{opts.Streams.Out}
+}
+
+// gzipWriter closes the underlying writer after the gzip stream is finished.
+type gzipWriter struct {
+	*gzip.Writer
+	underlying io.Closer
+}
+
+func (w *gzipWriter) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		w.underlying.Close()
+		return errors.Wrap(err, "finish gzip stream")
+	}
+	return w.underlying.Close()
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// unwrap returns the standard output stream for uncompressed output to stdout,
+// so that printers can detect whether they write to a terminal.
+func unwrap(w io.WriteCloser) io.Writer {
+	if nc, ok := w.(nopCloser); ok {
+		return nc.Writer
+	}
+	return w
+}
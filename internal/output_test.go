@@ -0,0 +1,202 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestOutputWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		compress   string
+		compressed bool
+	}{
+		{
+			name: "plain file",
+			file: "out.txt",
+		},
+		{
+			name:       "gz suffix",
+			file:       "out.txt.gz",
+			compressed: true,
+		},
+		{
+			name:       "explicit gzip",
+			file:       "out.txt",
+			compress:   "gzip",
+			compressed: true,
+		},
+		{
+			name: "missing parent directories",
+			file: "reports/2026/out.txt",
+		},
+		{
+			name:       "gzip to stdout",
+			compress:   "gzip",
+			compressed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			opts := &options.RakkessOptions{
+				Compress: test.compress,
+				Streams:  &genericclioptions.IOStreams{Out: stdout},
+			}
+			if test.file != "" {
+				opts.OutputFile = filepath.Join(t.TempDir(), test.file)
+			}
+
+			out, err := outputWriter(opts)
+			require.NoError(t, err)
+			_, err = out.Write([]byte("payload"))
+			require.NoError(t, err)
+			require.NoError(t, out.Close())
+
+			written := stdout.Bytes()
+			if test.file != "" {
+				written, err = ioutil.ReadFile(opts.OutputFile)
+				require.NoError(t, err)
+			}
+			if test.compressed {
+				r, err := gzip.NewReader(bytes.NewReader(written))
+				require.NoError(t, err)
+				written, err = ioutil.ReadAll(r)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, "payload", string(written))
+		})
+	}
+}
+
+func TestRenderStructured(t *testing.T) {
+	rows := result.ResourceAccess{
+		"secrets":          {"get": result.Allowed, "list": result.NotApplicable},
+		"deployments.apps": {"get": result.Denied, "list": result.RequestErr},
+	}.Rows([]string{"get", "list"}, "")
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: constants.OutputYAML,
+			expected: `- access:
+    get: allowed
+    list: n/a
+  apiGroup: ""
+  permissiveness: 1
+  resource: secrets
+- access:
+    get: denied
+    list: error
+  apiGroup: apps
+  permissiveness: 0
+  resource: deployments
+`,
+		},
+		{
+			format: constants.OutputJSON,
+			expected: `[
+  {
+    "resource": "secrets",
+    "apiGroup": "",
+    "access": {
+      "get": "allowed",
+      "list": "n/a"
+    },
+    "permissiveness": 1
+  },
+  {
+    "resource": "deployments",
+    "apiGroup": "apps",
+    "access": {
+      "get": "denied",
+      "list": "error"
+    },
+    "permissiveness": 0
+  }
+]
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			opts := &options.RakkessOptions{
+				OutputFormat: test.format,
+				Streams:      &genericclioptions.IOStreams{Out: stdout},
+			}
+			require.NoError(t, RenderStructured(opts, rows))
+			assert.Equal(t, test.expected, stdout.String())
+		})
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	opts := &options.RakkessOptions{Streams: &genericclioptions.IOStreams{Out: stdout}}
+
+	records := [][]string{{"doe, john", "User", "", "secrets", "", "get", "true"}}
+	require.NoError(t, RenderCSV(opts, result.CSVLongHeader, records))
+	assert.Equal(t, "subject,kind,namespace,resource,group,verb,allowed\n\"doe, john\",User,,secrets,,get,true\n", stdout.String())
+}
+
+func TestRenderJUnitMatrix(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	opts := &options.RakkessOptions{
+		OutputFormat: constants.OutputJUnit,
+		OutputFile:   filepath.Join(t.TempDir(), "report.xml"),
+		Compress:     constants.CompressGzip,
+		Streams:      &genericclioptions.IOStreams{Out: stdout},
+	}
+
+	table := result.ResourceAccess{"pods": {"get": result.Allowed}}.Table([]string{"get"})
+	require.NoError(t, RenderJUnitMatrix(opts, table))
+	assert.Contains(t, stdout.String(), "pods")
+	assert.NoFileExists(t, opts.OutputFile, "the report is written by Assert")
+}
+
+func TestCheckRestricted(t *testing.T) {
+	opts := &options.RakkessOptions{}
+	assert.NoError(t, CheckRestricted(opts, []string{"get:pods"}), "only fails with --exit-code")
+
+	opts.ExitCode = true
+	assert.NoError(t, CheckRestricted(opts, nil))
+	assert.EqualError(t, CheckRestricted(opts, []string{"get:pods", "list:secrets"}), "2 checked verbs are denied or could not be reviewed: get:pods, list:secrets")
+
+	var many []string
+	for i := 0; i < 12; i++ {
+		many = append(many, "get:pods")
+	}
+	assert.Contains(t, CheckRestricted(opts, many).Error(), "get:pods, and 2 more")
+}
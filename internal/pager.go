@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+	"k8s.io/klog/v2"
+)
+
+// defaultPager is used if the PAGER environment variable is not set. The -R
+// flag keeps the color escapes.
+const defaultPager = "less -R"
+
+var (
+	// for testing
+	terminalHeight = terminalHeightImpl
+	runPager       = runPagerImpl
+)
+
+// pagerWriter collects the output for a terminal. When it is closed, output
+// which is taller than the terminal is shown through the pager, and shorter
+// output is written to the terminal directly. If always is set, all output
+// is shown through the pager.
+type pagerWriter struct {
+	bytes.Buffer
+	terminal io.Writer
+	always   bool
+}
+
+// Terminal tells printers to keep colors, because the output ends up on a
+// terminal.
+func (w *pagerWriter) Terminal() bool {
+	return true
+}
+
+func (w *pagerWriter) Close() error {
+	height, ok := terminalHeight(w.terminal)
+	if !w.always && (!ok || bytes.Count(w.Bytes(), []byte("\n")) < height) {
+		_, err := w.WriteTo(w.terminal)
+		return errors.Wrap(err, "write output")
+	}
+	if err := runPager(w.Bytes(), w.terminal); err != nil {
+		klog.V(2).Infof("Writing output without pager: %s", err)
+		_, err := w.WriteTo(w.terminal)
+		return errors.Wrap(err, "write output")
+	}
+	return nil
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func terminalHeightImpl(w io.Writer) (int, bool) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	_, height, err := term.GetSize(int(f.Fd()))
+	return height, err == nil && height > 0
+}
+
+// runPagerImpl shows the output through the pager from the PAGER environment
+// variable, or through less -R by default.
+func runPagerImpl(output []byte, terminal io.Writer) error {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = strings.Fields(defaultPager)
+	}
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = bytes.NewReader(output)
+	cmd.Stdout = terminal
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		pagerErr error
+		always   bool
+		paged    bool
+	}{
+		{
+			name:   "fits on the terminal",
+			output: "one\ntwo\n",
+		},
+		{
+			name:   "taller than the terminal",
+			output: "one\ntwo\nthree\n",
+			paged:  true,
+		},
+		{
+			name:   "forced pager",
+			output: "one\n",
+			always: true,
+			paged:  true,
+		},
+		{
+			name:     "pager fails",
+			output:   "one\ntwo\nthree\n",
+			pagerErr: errors.New("exec: \"less\": executable file not found in $PATH"),
+		},
+	}
+
+	defer func(h func(io.Writer) (int, bool), p func([]byte, io.Writer) error) {
+		terminalHeight, runPager = h, p
+	}(terminalHeight, runPager)
+	terminalHeight = func(io.Writer) (int, bool) { return 3, true }
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var paged string
+			runPager = func(output []byte, _ io.Writer) error {
+				if test.pagerErr != nil {
+					return test.pagerErr
+				}
+				paged = string(output)
+				return nil
+			}
+			terminal := &bytes.Buffer{}
+			w := &pagerWriter{terminal: terminal, always: test.always}
+
+			_, err := w.Write([]byte(test.output))
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+
+			if test.paged {
+				assert.Equal(t, test.output, paged)
+				assert.Empty(t, terminal.String())
+			} else {
+				assert.Empty(t, paged)
+				assert.Equal(t, test.output, terminal.String())
+			}
+		})
+	}
+}
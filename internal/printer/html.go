@@ -0,0 +1,162 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HTMLReport describes the context of an access review, which is shown in the
+// header of the HTML report.
+type HTMLReport struct {
+	Context   string
+	Namespace string
+	Generated time.Time
+}
+
+type htmlCell struct {
+	Text  string
+	Class string
+}
+
+type htmlRow struct {
+	Cells     []htmlCell
+	Heading   bool
+	Highlight bool
+}
+
+type htmlTable struct {
+	Title   string
+	Headers []string
+	Rows    []htmlRow
+}
+
+// htmlTemplate renders a self-contained page. The styles are inline, so that
+// the report can be shared as a single file. html/template escapes all names.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Access review</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; }
+th { background: #f0f0f0; text-align: left; }
+td.allowed { background: #c8e6c9; color: #1b5e20; text-align: center; }
+td.denied { background: #ffcdd2; color: #b71c1c; text-align: center; }
+td.error { background: #e1bee7; color: #4a148c; text-align: center; }
+td.na { background: #fafafa; color: #999; text-align: center; }
+td.gained { background: #c8e6c9; color: #1b5e20; text-align: center; }
+td.lost { background: #ffcdd2; color: #b71c1c; text-align: center; }
+tr.highlight td:first-child { font-weight: bold; color: #b71c1c; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+</style>
+</head>
+<body>
+<h1>Access review</h1>
+<dl>
+<dt>Context</dt><dd>{{.Context}}</dd>
+<dt>Namespace</dt><dd>{{.Namespace}}</dd>
+<dt>Generated</dt><dd>{{.Generated}}</dd>
+</dl>
+{{- range .Tables}}
+{{- if .Title}}
+<h2>{{.Title}}</h2>
+{{- end}}
+<table>
+{{- if .Headers}}
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{- end}}
+{{- range .Rows}}
+{{- if .Heading}}
+<tr>{{range .Cells}}<th>{{.Text}}</th>{{end}}</tr>
+{{- else}}
+<tr{{if .Highlight}} class="highlight"{{end}}>{{range .Cells}}<td{{if .Class}} class="{{.Class}}"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{- end}}
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderHTML writes the tables as a self-contained HTML page, with the report
+// context in the header. Outcomes are shown in colored cells.
+func RenderHTML(out io.Writer, report HTMLReport, tables ...*Table) error {
+	data := struct {
+		Context   string
+		Namespace string
+		Generated string
+		Tables    []htmlTable
+	}{
+		Context:   report.Context,
+		Namespace: report.Namespace,
+		Generated: report.Generated.Format(time.RFC3339),
+	}
+	for _, t := range tables {
+		data.Tables = append(data.Tables, t.htmlTable())
+	}
+	return errors.Wrap(htmlTemplate.Execute(out, data), "render html")
+}
+
+func (p *Table) htmlTable() htmlTable {
+	t := htmlTable{Title: p.Title, Headers: p.Headers}
+	for _, row := range p.Rows {
+		if row.blank() {
+			continue
+		}
+		r := htmlRow{Heading: row.Heading, Highlight: row.Highlight}
+		for _, s := range row.Intro {
+			r.Cells = append(r.Cells, htmlCell{Text: s})
+		}
+		if !row.Heading {
+			for _, e := range row.Entries {
+				r.Cells = append(r.Cells, htmlCell{Text: asciiAccessCode(e), Class: htmlOutcomeClass(e)})
+			}
+		}
+		for _, s := range row.Outro {
+			r.Cells = append(r.Cells, htmlCell{Text: s})
+		}
+		t.Rows = append(t.Rows, r)
+	}
+	return t
+}
+
+// htmlOutcomeClass maps the outcome to the CSS class of its cell.
+func htmlOutcomeClass(o Outcome) string {
+	switch o {
+	case Up:
+		return "allowed"
+	case Down:
+		return "denied"
+	case Err:
+		return "error"
+	case Gained:
+		return "gained"
+	case Lost:
+		return "lost"
+	default:
+		return "na"
+	}
+}
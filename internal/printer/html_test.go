@@ -0,0 +1,57 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	matrix := &Table{
+		Rows: []Row{
+			{Intro: []string{"core:", "GET", "LIST"}, Entries: []Outcome{None}, Heading: true},
+			{Intro: []string{"pods"}, Entries: []Outcome{Up, Down}},
+			{Intro: []string{" "}, Entries: []Outcome{None}},
+			{Intro: []string{"apps:", "GET", "LIST"}, Entries: []Outcome{None}, Heading: true},
+			{Intro: []string{"<script>"}, Entries: []Outcome{Err, None}, Outro: []string{"critical"}, Highlight: true},
+		},
+	}
+	subjects := &Table{
+		Title:   "secrets",
+		Headers: []string{"NAME", "KIND", "GET"},
+		Rows:    []Row{{Intro: []string{"alice", "User"}, Entries: []Outcome{Up}}},
+	}
+	report := HTMLReport{Context: "prod", Namespace: "team-a", Generated: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, RenderHTML(buf, report, matrix, subjects))
+
+	html := buf.String()
+	assert.Contains(t, html, "<dt>Context</dt><dd>prod</dd>")
+	assert.Contains(t, html, "<dt>Namespace</dt><dd>team-a</dd>")
+	assert.Contains(t, html, "<dt>Generated</dt><dd>2026-10-15T12:00:00Z</dd>")
+	assert.Contains(t, html, "<tr><th>core:</th><th>GET</th><th>LIST</th></tr>\n<tr><td>pods</td><td class=\"allowed\">yes</td><td class=\"denied\">no</td></tr>")
+	assert.Contains(t, html, "<tr class=\"highlight\"><td>&lt;script&gt;</td><td class=\"error\">ERR</td><td class=\"na\">n/a</td><td>critical</td></tr>")
+	assert.Contains(t, html, "<h2>secrets</h2>\n<table>\n<tr><th>NAME</th><th>KIND</th><th>GET</th></tr>\n<tr><td>alice</td><td>User</td><td class=\"allowed\">yes</td></tr>")
+	assert.NotContains(t, html, "<script>")
+	assert.NotContains(t, html, "<td> </td>")
+}
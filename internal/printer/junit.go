@@ -0,0 +1,71 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// JUnitSuite is a test suite in a JUnit XML report.
+type JUnitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is a single test case. It passed if Failure is nil.
+type JUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes why a test case failed.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// Add appends a test case and updates the counters. A non-empty failure
+// message marks the test case as failed.
+func (s *JUnitSuite) Add(name, failure string) {
+	c := JUnitCase{Name: name, ClassName: s.Name}
+	if failure != "" {
+		c.Failure = &JUnitFailure{Message: failure}
+		s.Failures++
+	}
+	s.Tests++
+	s.Cases = append(s.Cases, c)
+}
+
+// Render writes the test suite as indented XML document.
+func (s *JUnitSuite) Render(out io.Writer) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return errors.Wrap(err, "write xml header")
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return errors.Wrap(err, "encode junit report")
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnitSuite_Render(t *testing.T) {
+	suite := &JUnitSuite{Name: "rakkess"}
+	suite.Add("require-allowed list:pods", "")
+	suite.Add("fail-if-allowed get:secrets", "get secrets is allowed, expected denied")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, suite.Render(buf))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="rakkess" tests="2" failures="1">
+  <testcase name="require-allowed list:pods" classname="rakkess"></testcase>
+  <testcase name="fail-if-allowed get:secrets" classname="rakkess">
+    <failure message="get secrets is allowed, expected denied"></failure>
+  </testcase>
+</testsuite>
+`, buf.String())
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package printer
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
@@ -37,6 +38,10 @@ const (
 var (
 	isTerminal = isTerminalImpl
 	once       sync.Once
+
+	// ForceColors colors the output even if it is not written to a terminal,
+	// e.g. when it is piped or written to a file.
+	ForceColors bool
 )
 
 type Outcome uint8
@@ -46,13 +51,26 @@ const (
 	Up
 	Down
 	Err
+	// Gained marks access which only the second of two compared identities has.
+	Gained
+	// Lost marks access which only the first of two compared identities has.
+	Lost
 )
 
 type Row struct {
 	Intro   []string
 	Entries []Outcome
+	// Outro are trailing columns after the entries.
+	Outro []string
+	// Highlight marks the row in bold red on a terminal.
+	Highlight bool
+	// Heading marks a row which names the columns of the following rows,
+	// e.g. at the start of every API group in the access matrix.
+	Heading bool
 }
 type Table struct {
+	// Title is printed as a heading above the table, if set.
+	Title   string
 	Headers []string
 	Rows    []Row
 }
@@ -72,16 +90,134 @@ func (p *Table) AddRow(intro []string, outcomes ...Outcome) {
 }
 
 func (p *Table) Render(out io.Writer, outputFormat string) {
+	if outputFormat == "markdown" {
+		p.renderMarkdown(out)
+		return
+	}
 	once.Do(func() { initTerminal(out) })
 
 	conv := humanreadableAccessCode
-	if isTerminal(out) {
+	terminal := colors(out)
+	if terminal {
 		conv = colored(conv)
 	}
 	if outputFormat == "ascii-table" {
 		conv = asciiAccessCode
 	}
 
+	if p.Title != "" {
+		fmt.Fprintf(out, "%s:\n", p.Title)
+	}
+	p.write(out, conv, terminal)
+}
+
+// renderMarkdown prints the table as GitHub-flavored markdown. Outcomes are
+// shown as plain icons, because markdown cannot render colors, and their
+// columns are centered. Highlighted rows are printed in bold. Every heading
+// row starts a new markdown table, so that tables without headers, such as
+// the access matrix with one section per API group, are printed as sections.
+func (p *Table) renderMarkdown(out io.Writer) {
+	if p.Title != "" {
+		fmt.Fprintf(out, "%s:\n\n", p.Title)
+	}
+
+	started := false
+	startTable := func(headers []string, i int) {
+		if started {
+			fmt.Fprintln(out)
+		}
+		started = true
+		intro, entries := len(headers), 0
+		if next := p.nextDataRow(i); next != nil {
+			intro, entries = len(next.Intro), len(next.Entries)
+		}
+		separators := make([]string, len(headers))
+		for i := range separators {
+			separators[i] = "---"
+			if i >= intro && i < intro+entries {
+				separators[i] = ":---:"
+			}
+		}
+		fmt.Fprintf(out, "| %s |\n", joinMarkdownCells(headers))
+		fmt.Fprintf(out, "| %s |\n", strings.Join(separators, " | "))
+	}
+	if len(p.Headers) > 0 {
+		startTable(p.Headers, 0)
+	}
+
+	for i, row := range p.Rows {
+		switch {
+		case row.blank():
+			continue
+		case row.Heading:
+			startTable(row.Intro, i+1)
+			continue
+		case !started:
+			continue
+		}
+		cells := append([]string{}, row.Intro...)
+		if row.Highlight && len(cells) > 0 && cells[0] != "" {
+			cells[0] = "**" + cells[0] + "**"
+		}
+		for _, e := range row.Entries {
+			cells = append(cells, humanreadableAccessCode(e))
+		}
+		cells = append(cells, row.Outro...)
+		fmt.Fprintf(out, "| %s |\n", joinMarkdownCells(cells))
+	}
+}
+
+// nextDataRow returns the first row from index i on which is neither blank
+// nor a heading, or nil if there is none.
+func (p *Table) nextDataRow(i int) *Row {
+	for ; i < len(p.Rows); i++ {
+		if !p.Rows[i].Heading && !p.Rows[i].blank() {
+			return &p.Rows[i]
+		}
+	}
+	return nil
+}
+
+// blank tells whether the row only separates sections of the table.
+func (r Row) blank() bool {
+	for _, s := range r.Intro {
+		if strings.TrimSpace(s) != "" {
+			return false
+		}
+	}
+	for _, e := range r.Entries {
+		if e != None {
+			return false
+		}
+	}
+	return len(r.Outro) == 0
+}
+
+// joinMarkdownCells escapes pipes, which would otherwise end a cell.
+func joinMarkdownCells(cells []string) string {
+	escaped := make([]string, 0, len(cells))
+	for _, c := range cells {
+		escaped = append(escaped, strings.ReplaceAll(c, "|", "\\|"))
+	}
+	return strings.Join(escaped, " | ")
+}
+
+// ClearScreen moves the cursor to the top left corner of the terminal and
+// clears the screen, so that the next table replaces the previous one.
+func ClearScreen(out io.Writer) {
+	fmt.Fprint(out, "\033[H\033[2J")
+}
+
+// Lines renders the headers and rows of the table as aligned lines with
+// colored outcomes, as for a terminal. The first line holds the headers, if
+// any, and is followed by one line per row.
+func (p *Table) Lines() []string {
+	var buf bytes.Buffer
+	p.write(&buf, colored(humanreadableAccessCode), true)
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func (p *Table) write(out io.Writer, conv func(Outcome) string, terminal bool) {
 	w := tabwriter.NewWriter(out, 4, 8, 2, ' ', tabwriter.SmashEscape|tabwriter.StripEscape)
 	defer w.Flush()
 
@@ -99,10 +235,34 @@ func (p *Table) Render(out io.Writer, outputFormat string) {
 
 	// table body
 	for _, row := range p.Rows {
-		fmt.Fprintf(w, "%s", strings.Join(row.Intro, "\t"))
+		intro := strings.Join(row.Intro, "\t")
+		switch {
+		case !terminal || len(row.Intro) == 0:
+		case row.Highlight:
+			intro = fmt.Sprintf("\xff\033[1;%dm\xff%s\xff\033[0m\xff", red, row.Intro[0])
+			intro = strings.Join(append([]string{intro}, row.Intro[1:]...), "\t")
+		case row.Heading:
+			// the API group stands out from the verbs next to it
+			intro = fmt.Sprintf("\xff\033[1m\xff%s\xff\033[0m\xff", row.Intro[0])
+			intro = strings.Join(append([]string{intro}, row.Intro[1:]...), "\t")
+		}
+		fmt.Fprintf(w, "%s", intro)
+		if row.Heading {
+			// the heading names the columns of the entries below
+			fmt.Fprint(w, "\n")
+			continue
+		}
+		if row.blank() {
+			// a separator keeps the columns aligned, but shows no outcome
+			fmt.Fprint(w, strings.Repeat("\t", len(row.Entries))+"\n")
+			continue
+		}
 		for _, e := range row.Entries {
 			fmt.Fprintf(w, "\t%s", conv(e)) // FIXME
 		}
+		for _, o := range row.Outro {
+			fmt.Fprintf(w, "\t%s", o)
+		}
 		fmt.Fprint(w, "\n")
 	}
 }
@@ -117,24 +277,41 @@ func humanreadableAccessCode(o Outcome) string {
 		return "✖" // ✕
 	case Err:
 		return "ERR"
+	case Gained:
+		return "+"
+	case Lost:
+		return "-"
 	default:
 		panic("unknown access code")
 	}
 }
 
+// colors tells whether ANSI escape sequences may be written to out. Writers
+// which end up on a terminal, such as the input of a pager, may tell so with a
+// Terminal method.
+func colors(out io.Writer) bool {
+	if t, ok := out.(interface{ Terminal() bool }); ok && t.Terminal() {
+		return true
+	}
+	return ForceColors || isTerminal(out)
+}
+
 func colored(wrap func(Outcome) string) func(Outcome) string {
 	return func(o Outcome) string {
-		c := none
-		switch o {
-		case Up:
-			c = green
-		case Down:
-			c = red
-		case Err:
-			c = purple
-		}
-		return fmt.Sprintf("\xff\033[%dm\xff%s\xff\033[0m\xff", c, wrap(o))
+		return fmt.Sprintf("\xff\033[%dm\xff%s\xff\033[0m\xff", outcomeColor(o), wrap(o))
+	}
+}
+
+func outcomeColor(o Outcome) color {
+	switch o {
+	case Up, Gained:
+		return green
+	case Down, Lost:
+		return red
+	case Err:
+		return purple
 	}
+	return none
 }
 
 func asciiAccessCode(o Outcome) string {
@@ -147,6 +324,10 @@ func asciiAccessCode(o Outcome) string {
 		return "no"
 	case Err:
 		return "ERR"
+	case Gained:
+		return "+"
+	case Lost:
+		return "-"
 	default:
 		panic("unknown access code")
 	}
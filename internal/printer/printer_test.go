@@ -82,6 +82,31 @@ func TestPrintResults(t *testing.T) {
 			HEADER + "resource1  \033[35mERR\033[0m  \033[35mERR\033[0m\n",
 			HEADER + "resource1  ERR  ERR\n",
 		},
+		{
+			"highlighted row",
+			&Table{
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"resource1"}, Entries: []Outcome{Up, Down}, Highlight: true},
+				},
+			},
+			HEADER + "resource1  ✔    ✖\n",
+			HEADER + "\033[1;31mresource1\033[0m  \033[32m✔\033[0m    \033[31m✖\033[0m\n",
+			HEADER + "resource1  yes  no\n",
+		},
+		{
+			"with title",
+			&Table{
+				Title:   "secrets",
+				Headers: []string{"NAME", "GET", "LIST"},
+				Rows: []Row{
+					{Intro: []string{"resource1"}, Entries: []Outcome{Up, Down}},
+				},
+			},
+			"secrets:\n" + HEADER + "resource1  ✔    ✖\n",
+			"secrets:\n" + HEADER + "resource1  \033[32m✔\033[0m    \033[31m✖\033[0m\n",
+			"secrets:\n" + HEADER + "resource1  yes  no\n",
+		},
 		{
 			"single result, mixed",
 			&Table{
@@ -108,6 +133,18 @@ func TestPrintResults(t *testing.T) {
 			"",
 			"NAME       GET\nresource1  no\nresource2  yes\nresource3  ERR\n",
 		},
+		{
+			"trailing columns",
+			&Table{
+				Headers: []string{"NAME", "GET", "NAMESPACE"},
+				Rows: []Row{
+					{Intro: []string{"resource1"}, Entries: []Outcome{Up}, Outro: []string{"default"}},
+				},
+			},
+			"NAME       GET  NAMESPACE\nresource1  ✔    default\n",
+			"",
+			"NAME       GET  NAMESPACE\nresource1  yes  default\n",
+		},
 	}
 
 	for _, tc := range tests {
@@ -141,3 +178,151 @@ func TestPrintResults(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintResults_gainedAndLost(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"resource1"}, Entries: []Outcome{Gained, Lost}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, HEADER+"resource1  +    -\n", buf.String())
+
+	buf = &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, HEADER+"resource1  +    -\n", buf.String())
+}
+
+func TestPrintResults_markdown(t *testing.T) {
+	table := &Table{
+		Title:   "Access",
+		Headers: []string{"NAME", "GET", "LIST", "NOTE"},
+		Rows: []Row{
+			{Intro: []string{"resource1"}, Entries: []Outcome{Up, Down}, Outro: []string{"a|b"}},
+			{Intro: []string{"resource2"}, Entries: []Outcome{None, Err}, Highlight: true},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "markdown")
+	assert.Equal(t, `Access:
+
+| NAME | GET | LIST | NOTE |
+| --- | :---: | :---: | --- |
+| resource1 | ✔ | ✖ | a\|b |
+| **resource2** |  | ERR |
+`, buf.String())
+}
+
+func TestPrintResults_markdownSections(t *testing.T) {
+	table := &Table{
+		Rows: []Row{
+			{Intro: []string{"core:", "GET"}, Entries: []Outcome{None}, Heading: true},
+			{Intro: []string{"pods"}, Entries: []Outcome{Up}},
+			{Intro: []string{" "}, Entries: []Outcome{None}},
+			{Intro: []string{"apps:", "GET"}, Entries: []Outcome{None}, Heading: true},
+			{Intro: []string{"deployments"}, Entries: []Outcome{Down}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "markdown")
+	assert.Equal(t, `| core: | GET |
+| --- | :---: |
+| pods | ✔ |
+
+| apps: | GET |
+| --- | :---: |
+| deployments | ✖ |
+`, buf.String())
+}
+
+func TestTable_Lines(t *testing.T) {
+	table := &Table{
+		Title:   "ignored",
+		Headers: []string{"NAME", "GET"},
+		Rows: []Row{
+			{Intro: []string{"resource1"}, Entries: []Outcome{Up}},
+			{Intro: []string{"res2"}, Entries: []Outcome{Down}, Highlight: true},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"NAME       GET",
+		"resource1  \033[32m✔\033[0m",
+		"\033[1;31mres2\033[0m       \033[31m✖\033[0m",
+	}, table.Lines())
+}
+
+func TestPrintResults_highlightOnTerminal(t *testing.T) {
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	defer func() {
+		isTerminal = isTerminalImpl
+	}()
+
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"resource1"}, Entries: []Outcome{Up, Down}, Highlight: true},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, HEADER+"\033[1;31mresource1\033[0m  \033[32m✔\033[0m    \033[31m✖\033[0m\n", buf.String())
+
+	buf = &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, HEADER+"\033[1;31mresource1\033[0m  yes  no\n", buf.String())
+}
+
+func TestPrintResults_headingOnTerminal(t *testing.T) {
+	table := &Table{
+		Rows: []Row{
+			{Intro: []string{"apps:", "GET"}, Entries: []Outcome{None}, Heading: true},
+			{Intro: []string{"deployments"}, Entries: []Outcome{Up}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "ascii-table")
+	assert.Equal(t, "apps:        GET\ndeployments  yes\n", buf.String(), "headings have no entries")
+
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	defer func() {
+		isTerminal = isTerminalImpl
+	}()
+
+	buf = &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, "\033[1mapps:\033[0m        GET\ndeployments  \033[32m✔\033[0m\n", buf.String())
+}
+
+func TestPrintResults_forceColors(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "GET", "LIST"},
+		Rows: []Row{
+			{Intro: []string{"resource1"}, Entries: []Outcome{Up, Down}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, HEADER+"resource1  ✔    ✖\n", buf.String(), "no colors without a terminal")
+
+	ForceColors = true
+	defer func() {
+		ForceColors = false
+	}()
+
+	buf = &bytes.Buffer{}
+	table.Render(buf, "icon-table")
+	assert.Equal(t, HEADER+"resource1  \033[32m✔\033[0m    \033[31m✖\033[0m\n", buf.String())
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Node is an entry in a Tree. Leaf nodes usually carry an Outcome, which is
+// rendered in front of the label.
+type Node struct {
+	Label    string
+	Outcome  Outcome
+	Children []*Node
+}
+
+// Add appends a child node and returns it.
+func (n *Node) Add(label string, outcome Outcome) *Node {
+	child := &Node{Label: label, Outcome: outcome}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// Tree is a forest of nodes which is rendered with indentation.
+type Tree struct {
+	Roots []*Node
+}
+
+// Add appends a root node and returns it.
+func (t *Tree) Add(label string) *Node {
+	root := &Node{Label: label}
+	t.Roots = append(t.Roots, root)
+	return root
+}
+
+// Render prints the tree with two spaces of indentation per level. Outcomes
+// are colored when printing to a terminal.
+func (t *Tree) Render(out io.Writer) {
+	once.Do(func() { initTerminal(out) })
+
+	conv := humanreadableAccessCode
+	if colors(out) {
+		conv = func(o Outcome) string {
+			return fmt.Sprintf("\033[%dm%s\033[0m", outcomeColor(o), humanreadableAccessCode(o))
+		}
+	}
+
+	var render func(n *Node, depth int)
+	render = func(n *Node, depth int) {
+		indent := strings.Repeat("  ", depth)
+		if n.Outcome == None {
+			fmt.Fprintf(out, "%s%s\n", indent, n.Label)
+		} else {
+			fmt.Fprintf(out, "%s%s %s\n", indent, conv(n.Outcome), n.Label)
+		}
+		for _, c := range n.Children {
+			render(c, depth+1)
+		}
+	}
+	for _, r := range t.Roots {
+		render(r, 0)
+	}
+}
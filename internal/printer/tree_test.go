@@ -0,0 +1,46 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_Render(t *testing.T) {
+	tree := &Tree{}
+	pods := tree.Add("core").Add("pods", None)
+	pods.Add("get", Up)
+	pods.Add("delete", Down)
+	tree.Add("apps").Add("deployments", None).Add("list", Err)
+
+	want := "core\n  pods\n    ✔ get\n    ✖ delete\napps\n  deployments\n    ERR list\n"
+	wantColor := "core\n  pods\n    \033[32m✔\033[0m get\n    \033[31m✖\033[0m delete\napps\n  deployments\n    \033[35mERR\033[0m list\n"
+
+	buf := &bytes.Buffer{}
+	tree.Render(buf)
+	assert.Equal(t, want, buf.String())
+
+	isTerminal = func(io.Writer) bool { return true }
+	defer func() { isTerminal = isTerminalImpl }()
+	buf.Reset()
+	tree.Render(buf)
+	assert.Equal(t, wantColor, buf.String())
+}
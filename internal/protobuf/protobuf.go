@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package protobuf writes the access matrix in the protobuf wire format of the
+// messages in rakkess.proto. The messages are few and flat, so they are
+// encoded directly instead of through generated bindings.
+package protobuf
+
+import (
+	"io"
+	"math"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// accessValues maps the access of structured rows to the Access enum.
+var accessValues = map[string]uint64{
+	result.Allowed.String():       1,
+	result.Denied.String():        2,
+	result.NotApplicable.String(): 3,
+	result.RequestErr.String():    4,
+}
+
+// WriteResourceRows writes every row as a length-delimited ResourceRow
+// message. The access of every row is listed in the order of verbs.
+func WriteResourceRows(w io.Writer, rows []result.ResourceRow, verbs []string) error {
+	for _, row := range rows {
+		var b []byte
+		b = appendString(b, 1, row.Resource)
+		b = appendString(b, 2, row.APIGroup)
+		for _, v := range verbs {
+			a, ok := row.Access[v]
+			if !ok {
+				continue
+			}
+			var entry []byte
+			entry = appendString(entry, 1, v)
+			if value := accessValues[a]; value != 0 {
+				entry = protowire.AppendTag(entry, 2, protowire.VarintType)
+				entry = protowire.AppendVarint(entry, value)
+			}
+			b = protowire.AppendTag(b, 3, protowire.BytesType)
+			b = protowire.AppendBytes(b, entry)
+		}
+		if row.Permissiveness != 0 {
+			b = protowire.AppendTag(b, 4, protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, math.Float64bits(row.Permissiveness))
+		}
+		b = appendString(b, 5, row.Risk)
+		if err := writeDelimited(w, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSubjectRows writes every row as a length-delimited SubjectRow message.
+func WriteSubjectRows(w io.Writer, rows []result.SubjectRow) error {
+	for _, row := range rows {
+		var b []byte
+		b = appendString(b, 1, row.Name)
+		b = appendString(b, 2, row.Kind)
+		b = appendString(b, 3, row.Namespace)
+		b = appendStrings(b, 4, row.Verbs)
+		if row.ClusterWide {
+			b = protowire.AppendTag(b, 5, protowire.VarintType)
+			b = protowire.AppendVarint(b, protowire.EncodeBool(true))
+		}
+		b = appendStrings(b, 6, row.Namespaces)
+		b = appendStrings(b, 7, row.Exceeds)
+		for _, g := range row.Roles {
+			var grant []byte
+			grant = appendString(grant, 1, g.Name)
+			grant = appendString(grant, 2, g.Kind)
+			grant = appendStrings(grant, 3, g.Verbs)
+			b = protowire.AppendTag(b, 8, protowire.BytesType)
+			b = protowire.AppendBytes(b, grant)
+		}
+		if err := writeDelimited(w, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendString appends a string field, which is omitted if empty as in proto3.
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendStrings appends a repeated string field.
+func appendStrings(b []byte, num protowire.Number, ss []string) []byte {
+	for _, s := range ss {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	return b
+}
+
+func writeDelimited(w io.Writer, msg []byte) error {
+	_, err := w.Write(protowire.AppendBytes(nil, msg))
+	return errors.Wrap(err, "write protobuf")
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protobuf
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var scalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"double": descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"bool":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+}
+
+// loadSchema parses rakkess.proto into a file descriptor, so that the output
+// is decoded against the published schema. It only understands the subset of
+// the proto3 syntax which rakkess.proto uses: enums, and messages with
+// (repeated) scalar, enum, or message fields.
+func loadSchema(t *testing.T) protoreflect.FileDescriptor {
+	src, err := os.ReadFile("rakkess.proto")
+	require.NoError(t, err)
+	src = regexp.MustCompile(`//.*`).ReplaceAll(src, nil)
+	tokens := strings.Fields(regexp.MustCompile(`([{};=])`).ReplaceAllString(string(src), " $1 "))
+	next := func() string {
+		require.NotEmpty(t, tokens, "unexpected end of rakkess.proto")
+		tok := tokens[0]
+		tokens = tokens[1:]
+		return tok
+	}
+	expect := func(want string) {
+		require.Equal(t, want, next())
+	}
+	number := func() int32 {
+		n, err := strconv.Atoi(next())
+		require.NoError(t, err)
+		return int32(n)
+	}
+
+	file := &descriptorpb.FileDescriptorProto{Name: proto.String("rakkess.proto")}
+	enums := map[string]bool{}
+	var fields []*descriptorpb.FieldDescriptorProto
+	for len(tokens) > 0 {
+		switch tok := next(); tok {
+		case "syntax":
+			expect("=")
+			file.Syntax = proto.String(strings.Trim(next(), `"`))
+			expect(";")
+		case "package":
+			file.Package = proto.String(next())
+			expect(";")
+		case "enum":
+			enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(next())}
+			enums[enum.GetName()] = true
+			expect("{")
+			for tok := next(); tok != "}"; tok = next() {
+				expect("=")
+				enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(tok), Number: proto.Int32(number())})
+				expect(";")
+			}
+			file.EnumType = append(file.EnumType, enum)
+		case "message":
+			msg := &descriptorpb.DescriptorProto{Name: proto.String(next())}
+			expect("{")
+			for tok := next(); tok != "}"; tok = next() {
+				field := &descriptorpb.FieldDescriptorProto{Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+				if tok == "repeated" {
+					field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+					tok = next()
+				}
+				if typ, ok := scalarTypes[tok]; ok {
+					field.Type = typ.Enum()
+				} else {
+					field.TypeName = proto.String("." + file.GetPackage() + "." + tok)
+				}
+				field.Name = proto.String(next())
+				expect("=")
+				field.Number = proto.Int32(number())
+				expect(";")
+				msg.Field = append(msg.Field, field)
+				fields = append(fields, field)
+			}
+			file.MessageType = append(file.MessageType, msg)
+		default:
+			t.Fatalf("unexpected token %q in rakkess.proto", tok)
+		}
+	}
+	// enums and messages may be referenced before they are declared
+	for _, field := range fields {
+		if field.TypeName == nil {
+			continue
+		}
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		if enums[strings.TrimPrefix(field.GetTypeName(), "."+file.GetPackage()+".")] {
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+		}
+	}
+
+	fd, err := protodesc.NewFile(file, nil)
+	require.NoError(t, err)
+	return fd
+}
+
+// decodeDelimited decodes the length-delimited messages of the given type
+// and returns them as JSON.
+func decodeDelimited(t *testing.T, b []byte, name protoreflect.Name) []string {
+	desc := loadSchema(t).Messages().ByName(name)
+	require.NotNil(t, desc)
+	var messages []string
+	for len(b) > 0 {
+		raw, n := protowire.ConsumeBytes(b)
+		require.True(t, n > 0)
+		b = b[n:]
+		msg := dynamicpb.NewMessage(desc)
+		require.NoError(t, proto.Unmarshal(raw, msg))
+		require.Empty(t, msg.GetUnknown(), "fields which are not in rakkess.proto")
+		js, err := protojson.Marshal(msg)
+		require.NoError(t, err)
+		messages = append(messages, string(js))
+	}
+	return messages
+}
+
+func TestWriteResourceRows(t *testing.T) {
+	rows := result.ResourceAccess{
+		"pods":             {"get": result.Allowed, "list": result.Denied},
+		"deployments.apps": {"get": result.NotApplicable, "list": result.RequestErr},
+	}.Rows([]string{"list", "get"}, "name")
+	rows[1].Risk = "high"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, rows, []string{"list", "get"}))
+
+	messages := decodeDelimited(t, buf.Bytes(), "ResourceRow")
+	require.Len(t, messages, 2)
+	assert.JSONEq(t, `{
+		"resource": "deployments",
+		"apiGroup": "apps",
+		"access": [{"verb": "list", "access": "ERROR"}, {"verb": "get", "access": "NOT_APPLICABLE"}]
+	}`, messages[0])
+	assert.JSONEq(t, `{
+		"resource": "pods",
+		"access": [{"verb": "list", "access": "DENIED"}, {"verb": "get", "access": "ALLOWED"}],
+		"permissiveness": 0.5,
+		"risk": "high"
+	}`, messages[1])
+}
+
+func TestWriteSubjectRows(t *testing.T) {
+	rows := []result.SubjectRow{
+		{
+			Name:       "ci",
+			Kind:       "ServiceAccount",
+			Namespace:  "build",
+			Verbs:      []string{"get", "delete"},
+			Namespaces: []string{"build", "prod"},
+			Roles:      []result.RoleGrant{{Name: "edit", Kind: "ClusterRole", Verbs: []string{"get"}}},
+			Exceeds:    []string{"delete"},
+		},
+		{Name: "admin", Kind: "User", Verbs: []string{"get"}, ClusterWide: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSubjectRows(&buf, rows))
+
+	messages := decodeDelimited(t, buf.Bytes(), "SubjectRow")
+	require.Len(t, messages, 2)
+	assert.JSONEq(t, `{
+		"name": "ci",
+		"kind": "ServiceAccount",
+		"namespace": "build",
+		"verbs": ["get", "delete"],
+		"namespaces": ["build", "prod"],
+		"exceeds": ["delete"],
+		"roles": [{"name": "edit", "kind": "ClusterRole", "verbs": ["get"]}]
+	}`, messages[0])
+	assert.JSONEq(t, `{
+		"name": "admin",
+		"kind": "User",
+		"verbs": ["get"],
+		"clusterWide": true
+	}`, messages[1])
+}
@@ -19,13 +19,24 @@ package internal
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/corneliusweig/rakkess/internal/client"
 	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/diff"
 	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/corneliusweig/rakkess/internal/ui"
 	"github.com/corneliusweig/rakkess/internal/validation"
 	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/klog/v2"
 )
 
@@ -36,59 +47,1116 @@ func Resource(ctx context.Context, opts *options.RakkessOptions) (result.Resourc
 	if err := validation.Options(opts); err != nil {
 		return nil, err
 	}
+	if opts.OutputFormat == constants.OutputSQLite {
+		return nil, fmt.Errorf("output format %s is only supported by the resource subcommand", constants.OutputSQLite)
+	}
 
 	grs, err := client.FetchAvailableGroupResources(opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch available group resources")
 	}
 	klog.V(2).Info(grs)
+	discoverVerbs(opts, grs)
+
+	if err := checkImpersonation(ctx, opts); err != nil {
+		return nil, err
+	}
+	return checkResourceAccess(ctx, opts, grs, opts.ConfigFlags.Namespace)
+}
+
+// ResourceAllNamespaces determines the access rights of the current (or
+// impersonated) user to all namespaced resources in every namespace.
+func ResourceAllNamespaces(ctx context.Context, opts *options.RakkessOptions) (result.NamespacedResourceAccess, error) {
+	if err := validation.Options(opts); err != nil {
+		return nil, err
+	}
+	if opts.OutputFormat != constants.OutputIconTable && opts.OutputFormat != constants.OutputASCIITable && opts.OutputFormat != constants.OutputMarkdown && opts.OutputFormat != constants.OutputHTML {
+		return nil, fmt.Errorf("output format %s is not supported with --%s", opts.OutputFormat, constants.FlagAllNamespaces)
+	}
+	if namespace := opts.ConfigFlags.Namespace; namespace != nil && *namespace != "" {
+		return nil, fmt.Errorf("--%s cannot be combined with --namespace", constants.FlagAllNamespaces)
+	}
+	if opts.MyNamespaces && opts.NoSAR {
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", constants.FlagMyNamespaces, constants.FlagNoSAR)
+	}
+
+	grs, err := client.FetchAvailableGroupResources(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch available group resources")
+	}
+	grs = client.NamespacedOnly(grs)
+	discoverVerbs(opts, grs)
+
+	if err := checkImpersonation(ctx, opts); err != nil {
+		return nil, err
+	}
+	namespaces, err := client.ListNamespaces(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list namespaces")
+	}
+	if opts.MyNamespaces {
+		authClient, err := opts.GetAuthClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "get auth client")
+		}
+		if namespaces, err = client.AccessibleNamespaces(ctx, authClient, namespaces); err != nil {
+			return nil, errors.Wrap(err, "review namespace access")
+		}
+		klog.V(2).Infof("Restricting to accessible namespaces %v", namespaces)
+	}
+
+	ret := make(result.NamespacedResourceAccess, len(namespaces))
+	for _, ns := range namespaces {
+		ns := ns
+		access, err := checkResourceAccess(ctx, opts, grs, &ns)
+		if err != nil {
+			return nil, errors.Wrapf(err, "check access in namespace %s", ns)
+		}
+		ret[ns] = access
+	}
+	return ret, nil
+}
+
+// ResourceTable renders the access matrix. With --describe, the allowed verbs
+// are described in words, with --risk-tags, the risk level of every resource
+// is added, and with --summary, the allowed verbs are counted.
+func ResourceTable(opts *options.RakkessOptions, ra result.ResourceAccess) (*printer.Table, error) {
+	if opts.Describe {
+		descriptions, err := client.LoadAccessDescriptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		return ra.DescriptionTable(opts.Verbs, descriptions), nil
+	}
+	var tags result.RiskTags
+	if opts.RiskTagsFile != "" {
+		var err error
+		if tags, err = client.LoadRiskTags(opts.RiskTagsFile); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Summary {
+		return ra.SummaryTable(opts.Verbs, tags, opts.SortBy), nil
+	}
+	return ra.SortedTable(opts.Verbs, tags, opts.SortBy), nil
+}
+
+// ResourceRows returns the access matrix as structured rows, which are
+// annotated with the risk levels from --risk-tags.
+func ResourceRows(opts *options.RakkessOptions, ra result.ResourceAccess) ([]result.ResourceRow, error) {
+	rows := ra.Rows(opts.Verbs, opts.SortBy)
+	if opts.RiskTagsFile != "" {
+		tags, err := client.LoadRiskTags(opts.RiskTagsFile)
+		if err != nil {
+			return nil, err
+		}
+		tags.AnnotateRows(rows)
+	}
+	if opts.AllowedOnly {
+		rows = result.RetainAllowedRows(rows)
+	}
+	return rows, nil
+}
+
+// CompareAuthorizers determines the access rights of the current (or
+// impersonated) user with access reviews, which reflect all authorizers, and
+// from a rules review, which only reflects RBAC.
+func CompareAuthorizers(ctx context.Context, opts *options.RakkessOptions) (*result.AuthorizerComparison, error) {
+	if err := validation.Options(opts); err != nil {
+		return nil, err
+	}
+	if opts.OutputFormat != constants.OutputIconTable && opts.OutputFormat != constants.OutputASCIITable && opts.OutputFormat != constants.OutputWide {
+		return nil, fmt.Errorf("output format %s is not supported with --%s", opts.OutputFormat, constants.FlagRBACOnly)
+	}
+	if opts.NoSAR {
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", constants.FlagRBACOnly, constants.FlagNoSAR)
+	}
+
+	grs, err := client.FetchAvailableGroupResources(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch available group resources")
+	}
+	discoverVerbs(opts, grs)
+
+	authClient, err := opts.GetAuthClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "get auth client")
+	}
+	rulesClient, err := opts.GetRulesReviewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "get rules review client")
+	}
+	rbac, err := client.CheckResourceAccessFromRules(ctx, rulesClient, grs, opts.Verbs, opts.ConfigFlags.Namespace, opts.ResourceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "review rules")
+	}
+	combined, err := client.CheckResourceAccess(ctx, authClient, grs, opts.Verbs, opts.ConfigFlags.Namespace, opts.ResourceName, maxConcurrency(ctx, opts, authClient), opts.MaxRetries, progressWriter(opts))
+	if err != nil {
+		return nil, errors.Wrap(err, "review access")
+	}
+	return &result.AuthorizerComparison{
+		Combined: combined,
+		RBAC:     rbac,
+	}, nil
+}
 
+// ExplainDenied explains why the verbs which are denied in the access result
+// are denied.
+func ExplainDenied(ctx context.Context, opts *options.RakkessOptions, ra result.ResourceAccess) (result.DenyExplanations, error) {
+	grs, err := client.FetchAvailableGroupResources(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch available group resources")
+	}
 	authClient, err := opts.GetAuthClient()
 	if err != nil {
 		return nil, errors.Wrap(err, "get auth client")
 	}
+	rulesClient, err := opts.GetRulesReviewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "get rules review client")
+	}
+	explanations, err := client.ExplainDenied(ctx, authClient, rulesClient, grs, ra, opts.ConfigFlags.Namespace, opts.ResourceName)
+	return explanations, errors.Wrap(err, "explain denied access")
+}
 
-	ret := client.CheckResourceAccess(ctx, authClient, grs, opts.Verbs, opts.ConfigFlags.Namespace)
-	return ret, nil
+// discoverVerbs replaces the verbs by the union of the verbs which the
+// resources support, if the verbs were given as all.
+func discoverVerbs(opts *options.RakkessOptions, grs []client.GroupResource) {
+	if !opts.DiscoverVerbs {
+		return
+	}
+	opts.Verbs = client.SupportedVerbs(grs)
+	klog.V(2).Infof("Checking the discovered verbs %v", opts.Verbs)
 }
 
-// Subject determines the subjects with access right to the given resource and
-// prints the result as a matrix with verbs in the horizontal and subject names
-// in the vertical direction.
-func Subject(ctx context.Context, opts *options.RakkessOptions, resourceWithOptionalAPIGroup, resourceName string) error {
-	if err := validation.OutputFormat(opts.OutputFormat); err != nil {
+// checkResourceAccess determines the access to the given resources with
+// access reviews, or from a rules review for --no-sar.
+func checkResourceAccess(ctx context.Context, opts *options.RakkessOptions, grs []client.GroupResource, namespace *string) (result.ResourceAccess, error) {
+	if opts.NoSAR {
+		rulesClient, err := opts.GetRulesReviewClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "get rules review client")
+		}
+		ret, err := client.CheckResourceAccessFromRules(ctx, rulesClient, grs, opts.Verbs, namespace, opts.ResourceName)
+		return ret, errors.Wrap(err, "review rules")
+	}
+
+	authClient, err := opts.GetAuthClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "get auth client")
+	}
+
+	ret, err := client.CheckResourceAccess(ctx, authClient, grs, opts.Verbs, namespace, opts.ResourceName, maxConcurrency(ctx, opts, authClient), opts.MaxRetries, progressWriter(opts))
+	return ret, errors.Wrap(err, "review access")
+}
+
+// progressWriter returns where to report the progress of access reviews, or
+// nil if no progress should be shown. The progress is only shown on terminals,
+// so that it does not end up in logs or in captured output.
+func progressWriter(opts *options.RakkessOptions) io.Writer {
+	if opts.NoProgress || opts.OutputFormat == constants.OutputJSON {
+		return nil
+	}
+	if !isTerminal(opts.Streams.Out) || !isTerminal(opts.Streams.ErrOut) {
+		return nil
+	}
+	return opts.Streams.ErrOut
+}
+
+// checkImpersonation fails early if the current user may not impersonate the
+// identity given by --as, --as-group, --as-uid, or --sa.
+func checkImpersonation(ctx context.Context, opts *options.RakkessOptions) error {
+	identity := opts.Impersonation()
+	if identity == "" || opts.NoSAR {
+		return nil
+	}
+	authClient, err := opts.GetAuthClient()
+	if err != nil {
+		return errors.Wrap(err, "get auth client")
+	}
+	return client.CheckImpersonation(ctx, authClient, identity)
+}
+
+// maxConcurrency returns the number of access reviews to send concurrently. With
+// --auto-parallelism, it is calibrated once and then kept for further scans.
+func maxConcurrency(ctx context.Context, opts *options.RakkessOptions, authClient authv1.SelfSubjectAccessReviewInterface) int {
+	if opts.AutoParallelism {
+		opts.MaxConcurrency = client.CalibrateParallelism(ctx, authClient)
+		opts.AutoParallelism = false
+		klog.V(1).Infof("Using max concurrency %d", opts.MaxConcurrency)
+	}
+	return opts.MaxConcurrency
+}
+
+// OnlyAccess returns the access which --only requires of at least one verb of
+// every row, and false if all rows are shown.
+func OnlyAccess(opts *options.RakkessOptions) (result.Access, bool) {
+	switch opts.Only {
+	case constants.OnlyAllowed:
+		return result.Allowed, true
+	case constants.OnlyDenied:
+		return result.Denied, true
+	}
+	return 0, false
+}
+
+func printMastersNote(opts *options.RakkessOptions) {
+	if opts.IgnoreMasters {
+		fmt.Fprintf(noteWriter(opts), "Grants via group %s and ClusterRole %s are excluded, but these subjects still have full access.\n", constants.MastersGroup, constants.ClusterAdminRole)
+	}
+}
+
+// noteWriter returns the stream for notes about the result. Notes go to
+// stderr for outputs which are meant to be processed by other tools.
+func noteWriter(opts *options.RakkessOptions) io.Writer {
+	switch opts.OutputFormat {
+	case constants.OutputLines, constants.OutputJSON, constants.OutputYAML, constants.OutputCSVLong, constants.OutputProtobuf, constants.OutputCSV, constants.OutputMarkdown, constants.OutputHTML:
+		return opts.Streams.ErrOut
+	}
+	return opts.Streams.Out
+}
+
+// UI determines the access matrix like Resource and shows it in a full-screen
+// terminal UI. Opening a resource shows the subjects with access to it, and
+// opening a subject shows the bindings which grant the access.
+func UI(ctx context.Context, opts *options.RakkessOptions) error {
+	in, inOK := opts.Streams.In.(*os.File)
+	out, outOK := opts.Streams.Out.(*os.File)
+	if !inOK || !outOK {
+		return errors.New("the ui needs an interactive terminal")
+	}
+	if err := ui.CheckTerminal(in, out); err != nil {
+		return err
+	}
+
+	kinds, err := result.ParseSubjectKinds(opts.SubjectKinds)
+	if err != nil {
+		return errors.Wrapf(err, "parse --%s", constants.FlagSubjectKind)
+	}
+	opts.SubjectKinds = kinds
+	members, err := loadGroupMembers(opts)
+	if err != nil {
+		return err
+	}
+
+	ra, err := Resource(ctx, opts)
+	if err != nil {
+		return err
+	}
+	table := ra.Table(opts.Verbs)
+	root := &ui.View{
+		Title: "resources",
+		Table: table,
+		Open: func(row int) (*ui.View, error) {
+			return subjectView(ctx, opts, table.Rows[row].Intro[0], members)
+		},
+	}
+	return ui.Run(ctx, in, out, root)
+}
+
+// subjectView shows the subjects with access to the resource of the access
+// matrix, and lets the user open the bindings of each subject.
+func subjectView(ctx context.Context, opts *options.RakkessOptions, name string, members result.GroupMembers) (*ui.View, error) {
+	// without --preferred-only, the name ends with the API version
+	if i := strings.Index(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+	gr, err := resolveGroupResource(opts, name)
+	if err != nil {
+		return nil, err
+	}
+	subjectAccess, err := getSubjectAccess(ctx, opts, gr, "")
+	if err != nil {
+		return nil, err
+	}
+	refineSubjectAccess(opts, subjectAccess, members, nil)
+
+	subjects := subjectAccess.Rows(opts.Verbs)
+	return &ui.View{
+		Title: fmt.Sprintf("subjects with access to %s", gr),
+		Table: subjectAccess.Table(opts.Verbs, result.TableOptions{Wide: true}),
+		Open: func(row int) (*ui.View, error) {
+			s := subjects[row]
+			ref := result.SubjectRef{Name: s.Name, Kind: s.Kind, Namespace: s.Namespace}
+			return &ui.View{
+				Title: fmt.Sprintf("bindings of %s %s", s.Kind, s.Name),
+				Table: subjectAccess.ProvenanceTable(ref, opts.Verbs),
+			}, nil
+		},
+	}, nil
+}
+
+// CompareIdentities determines the access matrix of the current (or
+// impersonated) user and of the other identity, and prints the resources for
+// which their access differs. Verbs which only the other identity is allowed
+// are marked with +, verbs which only the first identity is allowed with -.
+func CompareIdentities(ctx context.Context, opts *options.RakkessOptions, other string, otherGroups []string, showEqual bool) error {
+	if err := validation.Options(opts); err != nil {
+		return err
+	}
+	switch opts.OutputFormat {
+	case constants.OutputIconTable, constants.OutputASCIITable:
+	default:
+		return fmt.Errorf("output format %s is not supported by this command", opts.OutputFormat)
+	}
+	if other == "" && len(otherGroups) == 0 {
+		return fmt.Errorf("--%s or --%s is required", constants.FlagAsOther, constants.FlagAsOtherGroup)
+	}
+
+	grs, err := client.FetchAvailableGroupResources(opts)
+	if err != nil {
+		return errors.Wrap(err, "fetch available group resources")
+	}
+	discoverVerbs(opts, grs)
+
+	if err := checkImpersonation(ctx, opts); err != nil {
+		return err
+	}
+	left, err := checkResourceAccess(ctx, opts, grs, opts.ConfigFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	first, firstGroups := opts.ConfigFlags.Impersonate, opts.ConfigFlags.ImpersonateGroup
+	opts.ConfigFlags.Impersonate, opts.ConfigFlags.ImpersonateGroup = &other, &otherGroups
+	defer func() { opts.ConfigFlags.Impersonate, opts.ConfigFlags.ImpersonateGroup = first, firstGroups }()
+	if err := checkImpersonation(ctx, opts); err != nil {
+		return err
+	}
+	right, err := checkResourceAccess(ctx, opts, grs, opts.ConfigFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := Render(opts, diff.CompareAccess(left, right, opts.Verbs, showEqual)); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Streams.ErrOut, "+ means that only %s is allowed, - means that only the first identity is allowed.\n", describeOther(other, otherGroups))
+	return nil
+}
+
+func describeOther(user string, groups []string) string {
+	if user == "" {
+		return fmt.Sprintf("groups %v", groups)
+	}
+	return user
+}
+
+// Contexts determines the access matrix in each of the given kube-contexts of
+// the kubeconfig. It prints one table per context, followed by the resources
+// and verbs whose access differs between the contexts. Contexts which cannot
+// be reviewed are reported and skipped.
+func Contexts(ctx context.Context, opts *options.RakkessOptions) error {
+	switch opts.OutputFormat {
+	case constants.OutputIconTable, constants.OutputASCIITable, constants.OutputMarkdown, constants.OutputHTML:
+	default:
+		return fmt.Errorf("output format %s is not supported with --%s", opts.OutputFormat, constants.FlagContexts)
+	}
+
+	current := opts.ConfigFlags.Context
+	defer func() { opts.ConfigFlags.Context = current }()
+
+	var reviewed []string
+	var access []result.ResourceAccess
+	var tables []*printer.Table
+	for _, name := range opts.Contexts {
+		name := name
+		opts.ConfigFlags.Context = &name
+		ra, err := Resource(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			klog.Warningf("Skipping context %q: %v", name, err)
+			continue
+		}
+		table, err := ResourceTable(opts, ra)
+		if err != nil {
+			return err
+		}
+		table.Title = fmt.Sprintf("Context %q", name)
+		reviewed = append(reviewed, name)
+		access = append(access, ra)
+		tables = append(tables, table)
+	}
+	if len(reviewed) == 0 {
+		return fmt.Errorf("none of the contexts %v could be reviewed", opts.Contexts)
+	}
+	if len(reviewed) > 1 {
+		table := diff.CompareContexts(reviewed, access, opts.Verbs)
+		table.Title = "Access which differs between the contexts"
+		tables = append(tables, table)
+	}
+	return Render(opts, tables...)
+}
+
+// NonResource determines the access rights of the current (or impersonated)
+// user to the given non-resource URLs, and prints a matrix with verbs in the
+// horizontal and paths in the vertical direction.
+func NonResource(ctx context.Context, opts *options.RakkessOptions, paths []string) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if err := validation.NonResourceVerbs(opts.Verbs); err != nil {
+		return err
+	}
+	if opts.MaxConcurrency < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagMaxConcurrency, opts.MaxConcurrency)
+	}
+	if opts.MaxRetries < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagMaxRetries, opts.MaxRetries)
+	}
+
+	authClient, err := opts.GetAuthClient()
+	if err != nil {
+		return errors.Wrap(err, "get auth client")
+	}
+	if err := checkImpersonation(ctx, opts); err != nil {
+		return err
+	}
+	access, err := client.CheckNonResourceAccess(ctx, authClient, paths, opts.Verbs, opts.MaxConcurrency, opts.MaxRetries)
+	if err != nil {
+		return errors.Wrap(err, "review access")
+	}
+	access.RetainMinVerbs(opts.Verbs, opts.MinVerbs)
+	return Render(opts, access.NonResourceTable(opts.Verbs))
+}
+
+// Plan previews the effect of applying the RBAC manifests in the given
+// directory. It prints the grants which would be added or removed, and fails
+// if the manifests add escalating grants. With a baseline directory, the
+// manifests are compared with the baseline manifests instead of the cluster.
+func Plan(ctx context.Context, opts *options.RakkessOptions, dir, baselineDir string) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+
+	namespace := "default"
+	if ns := opts.ConfigFlags.Namespace; ns != nil && *ns != "" {
+		namespace = *ns
+	}
+	proposed, err := client.LoadRBACManifests(dir, namespace)
+	if err != nil {
+		return err
+	}
+
+	var changes result.GrantChanges
+	if baselineDir != "" {
+		baseline, err := client.LoadRBACManifests(baselineDir, namespace)
+		if err != nil {
+			return errors.Wrap(err, "load baseline")
+		}
+		changes = result.DiffGrants(baseline.Grants(), proposed.Grants())
+	} else {
+		current, err := client.FetchRBAC(ctx, opts)
+		if err != nil {
+			return errors.Wrap(err, "fetch current RBAC objects")
+		}
+		changes = result.DiffGrants(current.Grants(), current.Overlay(proposed).Grants())
+	}
+
+	if len(changes.Added) == 0 && len(changes.Removed) == 0 {
+		fmt.Fprintf(opts.Streams.Out, "The manifests do not change any access.\n")
+		return nil
+	}
+	if opts.OutputFormat == constants.OutputGitHubComment {
+		err = RenderText(opts, changes.GitHubComment())
+	} else {
+		err = Render(opts, changes.Table())
+	}
+	if err != nil {
+		return err
+	}
+
+	if escalations := changes.Escalations(); len(escalations) > 0 {
+		return fmt.Errorf("the manifests add %d escalating grants with wildcards or any of the verbs %s", len(escalations), strings.Join(constants.SpecialVerbs, ", "))
+	}
+	return nil
+}
+
+// PreviewSubject determines the access which a new subject would have, if it
+// were bound to the given roles, and prints the result as a matrix with verbs
+// in the horizontal and resource names in the vertical direction. Without
+// resource, all available resources are shown.
+func PreviewSubject(ctx context.Context, opts *options.RakkessOptions, bindTo []string, resourceWithOptionalAPIGroup string) error {
+	if err := validation.Options(opts); err != nil {
+		return err
+	}
+
+	var refs []result.RoleRef
+	for _, b := range bindTo {
+		ref, err := result.ParseRoleRef(b)
+		if err != nil {
+			return errors.Wrapf(err, "parse --%s", constants.FlagBindTo)
+		}
+		refs = append(refs, ref)
+	}
+
+	grs, err := client.FetchAvailableGroupResources(opts)
+	if err != nil {
+		return errors.Wrap(err, "fetch available group resources")
+	}
+	if resourceWithOptionalAPIGroup != "" {
+		gr, err := resolveGroupResource(opts, resourceWithOptionalAPIGroup)
+		if err != nil {
+			return err
+		}
+		var filtered []client.GroupResource
+		for _, r := range grs {
+			if r.APIGroup == gr.Group && r.APIResource.Name == gr.Resource {
+				filtered = append(filtered, r)
+			}
+		}
+		grs = filtered
+	}
+
+	var namespace string
+	if ns := opts.ConfigFlags.Namespace; ns != nil {
+		namespace = *ns
+	}
+	ra, err := client.PreviewBindings(ctx, opts, refs, grs, opts.Verbs, namespace)
+	if err != nil {
+		return err
+	}
+	return Render(opts, ra.Table(opts.Verbs))
+}
+
+// CompareNamespaces determines the subjects with access to the given resource
+// in both namespaces, and prints only the differences. Verbs which are only
+// granted in namespaceB are marked as allowed, and verbs which are only granted
+// in namespaceA as denied.
+func CompareNamespaces(ctx context.Context, opts *options.RakkessOptions, resourceWithOptionalAPIGroup, namespaceA, namespaceB string) error {
+	if err := validation.Options(opts); err != nil {
+		return err
+	}
+
+	gr, err := resolveGroupResource(opts, resourceWithOptionalAPIGroup)
+	if err != nil {
+		return err
+	}
+
+	var accesses []*result.SubjectAccess
+	for _, ns := range []string{namespaceA, namespaceB} {
+		ns := ns
+		opts.ConfigFlags.Namespace = &ns
+		subjectAccess, err := client.GetSubjectAccess(ctx, opts, gr, "")
+		if err != nil {
+			return errors.Wrapf(err, "get subject access in namespace %s", ns)
+		}
+		if opts.IgnoreMasters {
+			subjectAccess.ExcludeMasters()
+		}
+		accesses = append(accesses, subjectAccess)
+	}
+
+	if err := Render(opts, diff.SubjectDiff(accesses[0], accesses[1], opts.Verbs)); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Streams.Out, "Access marked as allowed is only granted in namespace %s, access marked as denied only in namespace %s.\n", namespaceB, namespaceA)
+	printMastersNote(opts)
+	return nil
+}
+
+// SecretReaders determines the subjects which can read secrets, or the named
+// secret, in any namespace and prints them with the namespace of the grant.
+// Readers which are not maintained by kubernetes are reported in a warning.
+func SecretReaders(ctx context.Context, opts *options.RakkessOptions, secretName string) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if opts.OutputFormat == constants.OutputSQLite {
+		return fmt.Errorf("output format %s is only supported by the resource subcommand", constants.OutputSQLite)
+	}
+	verbs := []string{"get", "list"}
+
+	access, err := client.GetSubjectAccessAllNamespaces(ctx, opts, schema.GroupResource{Resource: "secrets"}, secretName)
+	if err != nil {
+		return errors.Wrap(err, "get subject access")
+	}
+	retainNamespace(opts, access)
+	if opts.IgnoreMasters {
+		access.ExcludeMasters()
+	}
+
+	if err := Render(opts, access.Table(verbs)); err != nil {
+		return err
+	}
+	printMastersNote(opts)
+	if readers := access.NonSystemSubjects(verbs); len(readers) > 0 {
+		fmt.Fprintf(opts.Streams.Out, "WARNING: %d non-system subjects can read secrets: %s\n", len(readers), strings.Join(readers, ", "))
+	}
+	return nil
+}
+
+// TopSubjects ranks the subjects by the weighted verbs which they are granted
+// on the given resources, or on topSubjectsResources by default, and prints
+// the top most privileged ones. RoleBindings of all namespaces are considered,
+// or only those of the namespace given by --namespace.
+func TopSubjects(ctx context.Context, opts *options.RakkessOptions, resourcesWithOptionalAPIGroup []string, top int) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	switch opts.OutputFormat {
+	case constants.OutputIconTable, constants.OutputASCIITable, constants.OutputMarkdown, constants.OutputHTML, constants.OutputJSON, constants.OutputYAML, constants.OutputCSV:
+	default:
+		return fmt.Errorf("output format %s is not supported by top-subjects", opts.OutputFormat)
+	}
+	if top < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagTop, top)
+	}
+
+	grs := topSubjectsResources
+	if len(resourcesWithOptionalAPIGroup) > 0 {
+		grs = nil
+		for _, r := range resourcesWithOptionalAPIGroup {
+			gr, err := resolveGroupResource(opts, r)
+			if err != nil {
+				return err
+			}
+			grs = append(grs, gr)
+		}
+	}
+
+	var accesses []*result.SubjectAccess
+	for _, gr := range grs {
+		access, err := client.GetSubjectAccessAllNamespaces(ctx, opts, gr, "")
+		if err != nil {
+			return errors.Wrapf(err, "get subject access for %s", gr)
+		}
+		retainNamespace(opts, access)
+		merged := access.Merge()
+		if opts.IgnoreMasters {
+			merged.ExcludeMasters()
+		}
+		accesses = append(accesses, merged)
+	}
+
+	scores := result.ScoreSubjects(accesses)
+	if top > 0 && len(scores) > top {
+		scores = scores[:top]
+	}
+	switch opts.OutputFormat {
+	case constants.OutputJSON, constants.OutputYAML:
+		return RenderStructured(opts, scores)
+	case constants.OutputCSV:
+		return RenderCSV(opts, result.SubjectScoresCSVHeader, result.SubjectScoresCSVRecords(scores))
+	}
+	if err := Render(opts, result.SubjectScoresTable(scores)); err != nil {
+		return err
+	}
+	printMastersNote(opts)
+	return nil
+}
+
+// topSubjectsResources are ranked by top-subjects by default. Besides the
+// sensitive resources, they cover the objects on which bind, escalate, and
+// impersonate grant the most privilege.
+var topSubjectsResources = []schema.GroupResource{
+	{Resource: "secrets"},
+	{Resource: "configmaps"},
+	{Resource: "pods"},
+	{Resource: "pods/exec"},
+	{Resource: "serviceaccounts"},
+	{Resource: "users"},
+	{Resource: "groups"},
+	{Group: "apps", Resource: "deployments"},
+	{Group: rbacv1.GroupName, Resource: "roles"},
+	{Group: rbacv1.GroupName, Resource: "rolebindings"},
+	{Group: rbacv1.GroupName, Resource: "clusterroles"},
+	{Group: rbacv1.GroupName, Resource: "clusterrolebindings"},
+}
+
+// retainNamespace drops the access which is granted in other namespaces than
+// the one given by --namespace. Cluster-wide access is always kept.
+func retainNamespace(opts *options.RakkessOptions, access result.NamespacedSubjectAccess) {
+	namespace := opts.ConfigFlags.Namespace
+	if namespace == nil || *namespace == "" {
+		return
+	}
+	for ns := range access {
+		if ns != "" && ns != *namespace {
+			delete(access, ns)
+		}
+	}
+}
+
+// defaultServiceAccountResources are the sensitive resources which the
+// default service-accounts should not be able to access.
+var defaultServiceAccountResources = []schema.GroupResource{
+	{Resource: "secrets"},
+	{Resource: "configmaps"},
+	{Resource: "pods"},
+	{Resource: "pods/exec"},
+	{Resource: "serviceaccounts"},
+	{Group: "apps", Resource: "deployments"},
+	{Group: rbacv1.GroupName, Resource: "roles"},
+	{Group: rbacv1.GroupName, Resource: "rolebindings"},
+}
+
+// DefaultServiceAccounts determines the access of the default service-account
+// of every namespace to sensitive resources. Pods which do not name a
+// service-account run as the default service-account, so it should have no
+// access. Findings with write access are highlighted and reported in a warning.
+func DefaultServiceAccounts(ctx context.Context, opts *options.RakkessOptions) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if opts.OutputFormat != constants.OutputIconTable && opts.OutputFormat != constants.OutputASCIITable {
+		return fmt.Errorf("output format %s is not supported for --%s=%s", opts.OutputFormat, constants.FlagPreset, constants.PresetDefaultSA)
+	}
+
+	var accesses []*result.SubjectAccess
+	for _, gr := range defaultServiceAccountResources {
+		access, err := client.GetSubjectAccessAllNamespaces(ctx, opts, gr, "")
+		if err != nil {
+			return errors.Wrapf(err, "get subject access for %s", gr)
+		}
+		accesses = append(accesses, access.Merge())
+	}
+	findings := result.FindDefaultServiceAccounts(accesses, opts.Verbs)
+	if namespace := opts.ConfigFlags.Namespace; namespace != nil && *namespace != "" {
+		var inNamespace result.DefaultServiceAccountFindings
+		for _, f := range findings {
+			if f.Namespace == *namespace {
+				inNamespace = append(inNamespace, f)
+			}
+		}
+		findings = inNamespace
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintf(opts.Streams.Out, "No default service-account has access to sensitive resources.\n")
+		return nil
+	}
+	if err := Render(opts, findings.Table(opts.Verbs)); err != nil {
+		return err
+	}
+	if namespaces := findings.WriteNamespaces(); len(namespaces) > 0 {
+		fmt.Fprintf(opts.Streams.Out, "WARNING: the default service-account has write access in %d namespaces: %s\n", len(namespaces), strings.Join(namespaces, ", "))
+	}
+	return nil
+}
+
+// rbacAdminResources are the RBAC resources which control the authorization,
+// and the verbs which modify them.
+var rbacAdminResources = []struct {
+	resource string
+	verbs    []string
+}{
+	{"clusterroles", []string{"create", "update", "patch", "delete", "bind", "escalate"}},
+	{"clusterrolebindings", []string{"create", "update", "patch", "delete"}},
+	{"roles", []string{"create", "update", "patch", "delete", "bind", "escalate"}},
+	{"rolebindings", []string{"create", "update", "patch", "delete"}},
+}
+
+// RBACAdmins determines the subjects which can modify RBAC objects, and prints
+// one table per RBAC resource. Every row shows the namespace in which the
+// access is granted. Non-system subjects are highlighted and reported in a
+// warning, because they control the authorization of the whole cluster.
+func RBACAdmins(ctx context.Context, opts *options.RakkessOptions) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if opts.OutputFormat != constants.OutputIconTable && opts.OutputFormat != constants.OutputASCIITable {
+		return fmt.Errorf("output format %s is not supported for RBAC admins", opts.OutputFormat)
+	}
+
+	var tables []*printer.Table
+	seen := sets.NewString()
+	var admins []string
+	for _, r := range rbacAdminResources {
+		gr := schema.GroupResource{Group: rbacv1.GroupName, Resource: r.resource}
+		access, err := client.GetSubjectAccessAllNamespaces(ctx, opts, gr, "")
+		if err != nil {
+			return errors.Wrapf(err, "get subject access for %s", gr)
+		}
+		retainNamespace(opts, access)
+		if opts.IgnoreMasters {
+			access.ExcludeMasters()
+		}
+
+		table := access.HighlightedTable(r.verbs)
+		table.Title = gr.String()
+		tables = append(tables, table)
+		for _, s := range access.NonSystemSubjects(r.verbs) {
+			if !seen.Has(s) {
+				seen.Insert(s)
+				admins = append(admins, s)
+			}
+		}
+	}
+
+	if err := Render(opts, tables...); err != nil {
 		return err
 	}
+	fmt.Fprintf(opts.Streams.Out, "The namespace '*' stands for cluster-wide access. Access in a namespace only applies to Roles and RoleBindings in that namespace.\n")
+	printMastersNote(opts)
+	if len(admins) > 0 {
+		fmt.Fprintf(opts.Streams.Out, "WARNING: %d non-system subjects can modify RBAC: %s\n", len(admins), strings.Join(admins, ", "))
+	}
+	return nil
+}
+
+// resolveGroupResource completes the API group of the given resource with the
+// REST mapper. Short names and singular forms are expanded like kubectl does,
+// e.g. deploy resolves to deployments.apps.
+func resolveGroupResource(opts *options.RakkessOptions, resourceWithOptionalAPIGroup string) (schema.GroupResource, error) {
+	mapper, err := opts.ConfigFlags.ToRESTMapper()
+	if err != nil {
+		return schema.GroupResource{}, errors.Wrap(err, "cannot create k8s REST mapper")
+	}
+	return groupResourceFor(mapper, resourceWithOptionalAPIGroup)
+}
 
+// IsResource tells whether the cluster serves a resource type of the given
+// name, such as deploy or deployments.apps. Ambiguous names are resource types
+// as well.
+func IsResource(opts *options.RakkessOptions, resourceWithOptionalAPIGroup string) bool {
 	mapper, err := opts.ConfigFlags.ToRESTMapper()
 	if err != nil {
-		return errors.Wrap(err, "cannot create k8s REST mapper")
+		return false
 	}
+	return isResourceFor(mapper, resourceWithOptionalAPIGroup)
+}
+
+func isResourceFor(mapper meta.RESTMapper, resourceWithOptionalAPIGroup string) bool {
+	gr := schema.ParseGroupResource(resourceWithOptionalAPIGroup)
+	_, err := mapper.ResourceFor(schema.GroupVersionResource{Resource: gr.Resource, Group: gr.Group})
+	_, ambiguous := err.(*meta.AmbiguousResourceError)
+	return err == nil || ambiguous
+}
 
+// groupResourceFor maps the given resource in the form resource[.group] to a
+// unique GroupResource. Ambiguous resources are reported with the
+// fully-qualified forms to choose from.
+func groupResourceFor(mapper meta.RESTMapper, resourceWithOptionalAPIGroup string) (schema.GroupResource, error) {
 	// the apiGroup might be unspecified in the query, but will be populated in the response if there were only one such resource
 	gr := schema.ParseGroupResource(resourceWithOptionalAPIGroup)
 	versionedResource, err := mapper.ResourceFor(schema.GroupVersionResource{Resource: gr.Resource, Group: gr.Group})
+	if ambiguous, ok := err.(*meta.AmbiguousResourceError); ok {
+		candidates := sets.NewString()
+		for _, r := range ambiguous.MatchingResources {
+			candidates.Insert(r.GroupResource().String())
+		}
+		return schema.GroupResource{}, fmt.Errorf("resource %q is ambiguous, qualify it with its API group as one of: %s", resourceWithOptionalAPIGroup, strings.Join(candidates.List(), ", "))
+	}
+	if meta.IsNoMatchError(err) {
+		return schema.GroupResource{}, fmt.Errorf("the server doesn't have a resource type %q", resourceWithOptionalAPIGroup)
+	}
+	if err != nil {
+		return schema.GroupResource{}, errors.Wrap(err, "determine requested resource")
+	}
+	return versionedResource.GroupResource(), nil
+}
+
+// reviewerPermissions are meta-permissions which allow to probe or assume the
+// access rights of other subjects.
+var reviewerPermissions = []struct {
+	header string
+	gr     schema.GroupResource
+	verb   string
+}{
+	{"CREATE-SAR", schema.GroupResource{Group: "authorization.k8s.io", Resource: "subjectaccessreviews"}, "create"},
+	{"CREATE-LOCAL-SAR", schema.GroupResource{Group: "authorization.k8s.io", Resource: "localsubjectaccessreviews"}, "create"},
+	{"IMPERSONATE-USERS", schema.GroupResource{Resource: "users"}, "impersonate"},
+	{"IMPERSONATE-GROUPS", schema.GroupResource{Resource: "groups"}, "impersonate"},
+	{"IMPERSONATE-SA", schema.GroupResource{Resource: "serviceaccounts"}, "impersonate"},
+}
+
+// Reviewers determines the subjects which can create SubjectAccessReviews or
+// impersonate other subjects, and prints the result as a matrix with
+// meta-permissions in the horizontal and subject names in the vertical direction.
+func Reviewers(ctx context.Context, opts *options.RakkessOptions) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if opts.OutputFormat == constants.OutputSQLite {
+		return fmt.Errorf("output format %s is not supported for reviewers", constants.OutputSQLite)
+	}
+
+	var columns []result.MergedColumn
+	for _, p := range reviewerPermissions {
+		subjectAccess, err := client.GetSubjectAccess(ctx, opts, p.gr, "")
+		if err != nil {
+			return errors.Wrapf(err, "get subject access for %s", p.gr)
+		}
+		if opts.IgnoreMasters {
+			subjectAccess.ExcludeMasters()
+		}
+		columns = append(columns, result.MergedColumn{Header: p.header, Access: subjectAccess, Verb: p.verb})
+	}
+
+	if err := Render(opts, result.MergedTable(columns)); err != nil {
+		return err
+	}
+
+	if namespace := opts.ConfigFlags.Namespace; namespace == nil || *namespace == "" {
+		fmt.Fprintf(opts.Streams.Out, "Only ClusterRoleBindings are considered, because no namespace is given.\n")
+	}
+	printMastersNote(opts)
+	return nil
+}
+
+// Lint reports rules with wildcards which are bound to non-system subjects,
+// broadest first.
+func Lint(ctx context.Context, opts *options.RakkessOptions) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if opts.OutputFormat == constants.OutputSQLite {
+		return fmt.Errorf("output format %s is not supported by lint", constants.OutputSQLite)
+	}
+
+	findings, err := client.GetWildcardFindings(ctx, opts)
 	if err != nil {
-		return errors.Wrap(err, "determine requested resource")
+		return errors.Wrap(err, "get wildcard findings")
+	}
+	var tables []*printer.Table
+	if len(findings) == 0 {
+		fmt.Fprintf(opts.Streams.Out, "No wildcard grants to non-system subjects found.\n")
+	} else {
+		tables = append(tables, findings.Table())
+	}
+
+	if opts.ReportOrphanSubjects {
+		orphans, err := client.GetOrphanSubjects(ctx, opts)
+		if err != nil {
+			return errors.Wrap(err, "get orphan subjects")
+		}
+		if len(orphans) == 0 {
+			fmt.Fprintf(opts.Streams.Out, "No bindings to missing service-accounts found.\n")
+		} else {
+			tables = append(tables, orphans.Table())
+		}
+	}
+
+	if len(tables) == 0 {
+		return nil
+	}
+	if err := Render(opts, tables...); err != nil {
+		return err
+	}
+	if namespace := opts.ConfigFlags.Namespace; len(findings) > 0 && (namespace == nil || *namespace == "") {
+		fmt.Fprintf(opts.Streams.Out, "Only ClusterRoleBindings are considered for wildcard grants, because no namespace is given.\n")
+	}
+	return nil
+}
+
+// CanScan reviews whether the current (or impersonated) user has the
+// permissions rakkess needs, and prints the result as a readiness report.
+// It returns an error if any permission is missing.
+func CanScan(ctx context.Context, opts *options.RakkessOptions) error {
+	if err := validation.Output(opts); err != nil {
+		return err
+	}
+	if opts.OutputFormat == constants.OutputSQLite {
+		return fmt.Errorf("output format %s is only supported by the resource subcommand", constants.OutputSQLite)
 	}
 
-	subjectAccess, err := client.GetSubjectAccess(ctx, opts, versionedResource.GroupResource(), resourceName)
+	authClient, err := opts.GetAuthClient()
 	if err != nil {
-		return errors.Wrap(err, "get subject access")
+		return errors.Wrap(err, "get auth client")
+	}
+
+	var ns string
+	if namespace := opts.ConfigFlags.Namespace; namespace != nil {
+		ns = *namespace
+	}
+
+	checks := client.CheckCapabilities(ctx, authClient, ns)
+
+	p := printer.TableWithHeaders([]string{"VERB", "RESOURCE", "NEEDED BY", "ALLOWED"})
+	incomplete := sets.NewString()
+	for _, c := range checks {
+		o := printer.Up
+		switch c.Access {
+		case result.Denied:
+			o = printer.Down
+		case result.RequestErr:
+			o = printer.Err
+		}
+		if c.Access != result.Allowed {
+			incomplete.Insert(c.NeededBy)
+		}
+		p.AddRow([]string{c.Verb, c.Resource.String(), c.NeededBy}, o)
+	}
+	if err := Render(opts, p); err != nil {
+		return err
+	}
+
+	if ns == "" {
+		fmt.Fprintf(opts.Streams.Out, "Namespaced permissions were not checked, because no namespace is given.\n")
+	}
+	for _, c := range checks {
+		if c.Access != result.Allowed && c.Reason != "" {
+			fmt.Fprintf(opts.Streams.Out, "%s %s: %s\n", c.Verb, c.Resource, c.Reason)
+		}
 	}
+	if incomplete.Len() > 0 {
+		return fmt.Errorf("missing permissions, results will be incomplete for: %s", strings.Join(incomplete.List(), ", "))
+	}
+	fmt.Fprintf(opts.Streams.Out, "All checks passed.\n")
+	return nil
+}
 
-	if subjectAccess.Empty() {
-		klog.Warningf("No subjects with access found. This most likely means that you have insufficient rights to review authorization.")
+// Assert evaluates the --require-allowed and --fail-if-allowed assertions
+// against the access matrix. For the junit output format, every assertion is
+// written as test case to the output file. It fails if any assertion fails.
+func Assert(opts *options.RakkessOptions, ra result.ResourceAccess) error {
+	assertions, err := result.ParseAssertions(constants.FlagRequireAllowed, opts.RequireAllowed, constants.FlagFailIfAllowed, opts.FailIfAllowed)
+	if err != nil {
+		return err
+	}
+	if len(assertions) == 0 {
 		return nil
 	}
 
-	t := subjectAccess.Table(opts.Verbs)
-	t.Render(opts.Streams.Out, opts.OutputFormat)
+	suite := &printer.JUnitSuite{Name: constants.CommandName}
+	var failed []string
+	for _, r := range ra.Evaluate(assertions) {
+		suite.Add(r.Name(), r.Message)
+		if !r.Passed {
+			failed = append(failed, r.Message)
+		}
+	}
 
-	namespace := opts.ConfigFlags.Namespace
-	if namespace == nil || *namespace == "" {
-		fmt.Fprintf(opts.Streams.Out, "Only ClusterRoleBindings are considered, because no namespace is given.\n")
+	if opts.OutputFormat == constants.OutputJUnit {
+		out, err := outputWriter(opts)
+		if err != nil {
+			return err
+		}
+		if err := suite.Render(out); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return errors.Wrap(err, "close output")
+		}
 	}
 
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d assertions failed: %s", len(failed), len(assertions), strings.Join(failed, "; "))
+	}
 	return nil
 }
+
+// CheckRestricted fails for --exit-code if any of the verbs is denied on a
+// resource, or if an access review failed. The cells are given in the form
+// returned by the Restricted methods of the access results.
+func CheckRestricted(opts *options.RakkessOptions, cells []string) error {
+	if !opts.ExitCode || len(cells) == 0 {
+		return nil
+	}
+	shown := cells
+	if len(shown) > maxRestrictedCells {
+		shown = shown[:maxRestrictedCells]
+	}
+	msg := strings.Join(shown, ", ")
+	if more := len(cells) - len(shown); more > 0 {
+		msg += fmt.Sprintf(", and %d more", more)
+	}
+	return fmt.Errorf("%d checked verbs are denied or could not be reviewed: %s", len(cells), msg)
+}
+
+// maxRestrictedCells is the number of restricted cells which --exit-code lists.
+const maxRestrictedCells = 10
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGroupResourceFor(t *testing.T) {
+	apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+	extensions := schema.GroupVersion{Group: "extensions", Version: "v1beta1"}
+	core := schema.GroupVersion{Version: "v1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{core, apps, extensions})
+	mapper.Add(core.WithKind("Pod"), meta.RESTScopeNamespace)
+	mapper.Add(apps.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(extensions.WithKind("Deployment"), meta.RESTScopeNamespace)
+
+	tests := []struct {
+		name        string
+		resource    string
+		expected    schema.GroupResource
+		expectedErr string
+	}{
+		{
+			name:     "core resource",
+			resource: "pods",
+			expected: schema.GroupResource{Resource: "pods"},
+		},
+		{
+			name:     "singular form",
+			resource: "pod",
+			expected: schema.GroupResource{Resource: "pods"},
+		},
+		{
+			name:     "fully-qualified resource",
+			resource: "deployments.apps",
+			expected: schema.GroupResource{Group: "apps", Resource: "deployments"},
+		},
+		{
+			name:        "ambiguous resource",
+			resource:    "deployments",
+			expectedErr: `resource "deployments" is ambiguous, qualify it with its API group as one of: deployments.apps, deployments.extensions`,
+		},
+		{
+			name:        "unknown resource",
+			resource:    "foos",
+			expectedErr: `the server doesn't have a resource type "foos"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gr, err := groupResourceFor(mapper, test.resource)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, gr)
+		})
+	}
+}
+
+func TestIsResourceFor(t *testing.T) {
+	apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+	extensions := schema.GroupVersion{Group: "extensions", Version: "v1beta1"}
+	core := schema.GroupVersion{Version: "v1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{core, apps, extensions})
+	mapper.Add(core.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(apps.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(extensions.WithKind("Deployment"), meta.RESTScopeNamespace)
+
+	assert.True(t, isResourceFor(mapper, "secrets"))
+	assert.True(t, isResourceFor(mapper, "secret"))
+	assert.True(t, isResourceFor(mapper, "deployments.apps"))
+	assert.True(t, isResourceFor(mapper, "deployments"), "ambiguous resources are resources")
+	assert.False(t, isResourceFor(mapper, "config-map-name"))
+}
+
+func TestRetainNamespace(t *testing.T) {
+	newAccess := func() result.NamespacedSubjectAccess {
+		return result.NamespacedSubjectAccess{"": nil, "prod": nil, "dev": nil}
+	}
+
+	opts := options.NewRakkessOptions()
+	access := newAccess()
+	retainNamespace(opts, access)
+	assert.Len(t, access, 3, "all namespaces without --namespace")
+
+	prod := "prod"
+	opts.ConfigFlags.Namespace = &prod
+	access = newAccess()
+	retainNamespace(opts, access)
+	assert.Equal(t, result.NamespacedSubjectAccess{"": nil, "prod": nil}, access)
+}
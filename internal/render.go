@@ -0,0 +1,274 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client"
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Render prints the tables in the configured output format. The tables go to
+// the output file, if one is given, and to the standard output otherwise.
+// Several tables are separated by an empty line.
+func Render(opts *options.RakkessOptions, tables ...*printer.Table) error {
+	switch opts.OutputFormat {
+	case constants.OutputJSON, constants.OutputYAML, constants.OutputSQLite, constants.OutputTree, constants.OutputJUnit, constants.OutputDigest, constants.OutputLines, constants.OutputGitHubComment, constants.OutputCSVLong, constants.OutputProtobuf, constants.OutputCSV, constants.OutputList:
+		return fmt.Errorf("output format %s is not supported by this command", opts.OutputFormat)
+	}
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	return renderTables(opts, out, opts.OutputFormat, tables...)
+}
+
+// RenderJUnitMatrix prints the tables as icon table to the standard output.
+// The junit output format writes its report to the output file, so the access
+// matrix is shown on the terminal instead.
+func RenderJUnitMatrix(opts *options.RakkessOptions, tables ...*printer.Table) error {
+	return renderTables(opts, stdoutWriter(opts), constants.OutputIconTable, tables...)
+}
+
+// renderTables prints the tables in the given format to out, and closes it.
+func renderTables(opts *options.RakkessOptions, out io.WriteCloser, format string, tables ...*printer.Table) error {
+	if format == constants.OutputHTML {
+		if err := printer.RenderHTML(out, htmlReport(opts), tables...); err != nil {
+			out.Close()
+			return err
+		}
+		return errors.Wrap(out.Close(), "close output")
+	}
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		t.Render(unwrap(out), format)
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// htmlReport describes the kubeconfig context and the namespace of the
+// review for the header of the HTML report.
+func htmlReport(opts *options.RakkessOptions) printer.HTMLReport {
+	report := printer.HTMLReport{Generated: time.Now()}
+	if len(opts.Contexts) > 0 {
+		report.Context = strings.Join(opts.Contexts, ", ")
+	} else if c := opts.ConfigFlags.Context; c != nil && *c != "" {
+		report.Context = *c
+	} else if raw, err := opts.ConfigFlags.ToRawKubeConfigLoader().RawConfig(); err == nil {
+		report.Context = raw.CurrentContext
+	}
+	switch ns := opts.ConfigFlags.Namespace; {
+	case opts.AllNamespaces:
+		report.Namespace = "all namespaces"
+	case ns != nil && *ns != "":
+		report.Namespace = *ns
+	default:
+		report.Namespace = "none"
+	}
+	return report
+}
+
+// RenderDigest prints the digest of the access matrix, followed by the inputs
+// which determine the matrix. Monitoring jobs can compare the digest with a
+// previous run, and do a full capture when it changes.
+func RenderDigest(opts *options.RakkessOptions, ra result.ResourceAccess) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	verbs := append([]string(nil), opts.Verbs...)
+	sort.Strings(verbs)
+	scope := "cluster"
+	if ns := opts.ConfigFlags.Namespace; ns != nil && *ns != "" {
+		scope = "namespace " + *ns
+	}
+	fmt.Fprintf(out, "%s\n", ra.Digest(opts.Verbs))
+	fmt.Fprintf(out, "scope: %s\n", scope)
+	if as := opts.ConfigFlags.Impersonate; as != nil && *as != "" {
+		fmt.Fprintf(out, "as: %s\n", *as)
+	}
+	fmt.Fprintf(out, "verbs: %s\n", strings.Join(verbs, ","))
+	if opts.MinVerbs > 0 {
+		fmt.Fprintf(out, "min-verbs: %d\n", opts.MinVerbs)
+	}
+	fmt.Fprintf(out, "resources: %d\n", len(ra))
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderText prints the text to the output file, if one is given, and to the
+// standard output otherwise.
+func RenderText(opts *options.RakkessOptions, text string) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, text)
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderLines prints the sorted lines to the output file, if one is given, and
+// to the standard output otherwise.
+func RenderLines(opts *options.RakkessOptions, lines []string) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	sorted := append([]string(nil), lines...)
+	sort.Strings(sorted)
+	for _, l := range sorted {
+		fmt.Fprintln(out, l)
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderTree prints the trees to the output file, if one is given, and to the
+// standard output otherwise.
+func RenderTree(opts *options.RakkessOptions, trees ...*printer.Tree) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	for _, t := range trees {
+		t.Render(unwrap(out))
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderCSV writes the header and records as CSV to the output file, if one is
+// given, and to the standard output otherwise.
+func RenderCSV(opts *options.RakkessOptions, header []string, records [][]string) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(out)
+	if err := w.Write(header); err != nil {
+		out.Close()
+		return errors.Wrap(err, "write csv")
+	}
+	if err := w.WriteAll(records); err != nil {
+		out.Close()
+		return errors.Wrap(err, "write csv")
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderResourceCSV prints the access matrix as CSV with one row per resource
+// and one column per verb.
+func RenderResourceCSV(opts *options.RakkessOptions, ra result.ResourceAccess) error {
+	return RenderCSV(opts, result.CSVHeader("resource", opts.Verbs), ra.CSVRecords(opts.Verbs, opts.SortBy))
+}
+
+// RenderStructured writes v as YAML for the yaml output format, and as JSON
+// otherwise.
+func RenderStructured(opts *options.RakkessOptions, v interface{}) error {
+	if opts.OutputFormat == constants.OutputYAML {
+		return RenderYAML(opts, v)
+	}
+	return RenderJSON(opts, v)
+}
+
+// RenderProtobuf writes length-delimited protobuf messages with write to the
+// output file, if one is given, and to the standard output otherwise.
+func RenderProtobuf(opts *options.RakkessOptions, write func(io.Writer) error) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	if err := write(out); err != nil {
+		out.Close()
+		return err
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderYAML writes v as YAML to the output file, if one is given, and to the
+// standard output otherwise. Like with JSON, map keys are sorted.
+func RenderYAML(opts *options.RakkessOptions, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "encode yaml")
+	}
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return errors.Wrap(err, "write yaml")
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// RenderJSON writes v as indented JSON to the output file, if one is given,
+// and to the standard output otherwise.
+func RenderJSON(opts *options.RakkessOptions, v interface{}) error {
+	out, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		out.Close()
+		return errors.Wrap(err, "encode json")
+	}
+	return errors.Wrap(out.Close(), "close output")
+}
+
+// PrintReportSummary writes the totals of the access matrix and the elapsed
+// time as a single JSON line to the standard error stream, independent of the
+// output format.
+func PrintReportSummary(opts *options.RakkessOptions, counts result.AccessCounts, elapsed time.Duration) {
+	_ = json.NewEncoder(opts.Streams.ErrOut).Encode(struct {
+		result.AccessCounts
+		DurationSeconds float64 `json:"durationSeconds"`
+	}{counts, elapsed.Seconds()})
+}
+
+// PrintStats writes the scan duration and API call counts to the standard
+// error stream. The stats are printed as JSON for the json output format.
+func PrintStats(opts *options.RakkessOptions, elapsed time.Duration) {
+	stats := client.Stats()
+	if opts.OutputFormat == constants.OutputJSON {
+		enc := json.NewEncoder(opts.Streams.ErrOut)
+		_ = enc.Encode(struct {
+			DurationSeconds float64 `json:"durationSeconds"`
+			client.ScanStats
+		}{elapsed.Seconds(), stats})
+		return
+	}
+	fmt.Fprintf(opts.Streams.ErrOut, "Scan took %s with %d access reviews, %d list calls, and %d cache hits.\n",
+		elapsed.Round(time.Millisecond), stats.AccessReviews, stats.Lists, stats.CacheHits)
+	if stats.Parallelism > 0 {
+		fmt.Fprintf(opts.Streams.ErrOut, "Calibrated parallelism is %d.\n", stats.Parallelism)
+	}
+}
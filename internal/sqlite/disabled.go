@@ -0,0 +1,31 @@
+//go:build !sqlite
+// +build !sqlite
+
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/pkg/errors"
+)
+
+// WriteSubjectAccess always fails, because the SQLite driver is only compiled
+// in with the sqlite build tag.
+func WriteSubjectAccess(string, *result.SubjectAccess, []string, string) error {
+	return errors.New("this binary was built without sqlite support, rebuild with `-tags sqlite`")
+}
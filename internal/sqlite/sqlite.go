@@ -0,0 +1,146 @@
+//go:build sqlite
+// +build sqlite
+
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// schemaStatements are applied to every database before writing. All statements must be
+// idempotent, so that several captures can be appended to the same file.
+var schemaStatements = []string{
+	`CREATE TABLE IF NOT EXISTS captures (
+		id            INTEGER PRIMARY KEY AUTOINCREMENT,
+		created_at    TEXT NOT NULL,
+		resource      TEXT NOT NULL,
+		resource_name TEXT NOT NULL,
+		namespace     TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS subjects (
+		id        INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind      TEXT NOT NULL,
+		name      TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		UNIQUE (kind, name, namespace)
+	)`,
+	`CREATE TABLE IF NOT EXISTS grants (
+		capture_id   INTEGER NOT NULL REFERENCES captures (id),
+		subject_id   INTEGER NOT NULL REFERENCES subjects (id),
+		verb         TEXT NOT NULL,
+		resource     TEXT NOT NULL,
+		namespace    TEXT NOT NULL,
+		binding_kind TEXT NOT NULL,
+		binding      TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS grants_capture ON grants (capture_id)`,
+}
+
+// WriteSubjectAccess appends the subject access as a new capture to the SQLite
+// database at path. The database is created if it does not exist. Each
+// (subject, verb, binding) combination is stored as one row in the grants
+// table, restricted to the given verbs.
+func WriteSubjectAccess(path string, sa *result.SubjectAccess, verbs []string, namespace string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return errors.Wrapf(err, "open database %s", path)
+	}
+	defer db.Close()
+
+	for _, stmt := range schemaStatements {
+		if _, err := db.Exec(stmt); err != nil {
+			return errors.Wrap(err, "create schema")
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "begin transaction")
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after a successful commit
+
+	res, err := tx.Exec(`INSERT INTO captures (created_at, resource, resource_name, namespace) VALUES (?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), sa.GroupResource.String(), sa.ResourceName, namespace)
+	if err != nil {
+		return errors.Wrap(err, "insert capture")
+	}
+	captureID, err := res.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "insert capture")
+	}
+
+	resource := sa.GroupResource.String()
+	for _, s := range sa.Subjects() {
+		bindings := sa.Bindings(s)
+		var subjectID int64
+		for _, b := range sortedBindings(bindings) {
+			for _, v := range verbs {
+				if !bindings[b].Has(v) {
+					continue
+				}
+				if subjectID == 0 {
+					if subjectID, err = upsertSubject(tx, s); err != nil {
+						return errors.Wrapf(err, "insert subject %s", s.Name)
+					}
+				}
+				if _, err := tx.Exec(`INSERT INTO grants (capture_id, subject_id, verb, resource, namespace, binding_kind, binding) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+					captureID, subjectID, v, resource, b.Namespace, b.Kind, b.Name); err != nil {
+					return errors.Wrap(err, "insert grant")
+				}
+			}
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "commit")
+}
+
+// upsertSubject returns the id of the given subject and creates it, if it is not yet known.
+func upsertSubject(tx *sql.Tx, s result.SubjectRef) (int64, error) {
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO subjects (kind, name, namespace) VALUES (?, ?, ?)`, s.Kind, s.Name, s.Namespace); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM subjects WHERE kind = ? AND name = ? AND namespace = ?`, s.Kind, s.Name, s.Namespace).Scan(&id)
+	return id, err
+}
+
+func sortedBindings(bindings map[result.BindingRef]sets.String) []result.BindingRef {
+	refs := make([]result.BindingRef, 0, len(bindings))
+	for b := range bindings {
+		refs = append(refs, b)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		x, y := refs[i], refs[j]
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		if x.Namespace != y.Namespace {
+			return x.Namespace < y.Namespace
+		}
+		return x.Name < y.Name
+	})
+	return refs
+}
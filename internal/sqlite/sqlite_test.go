@@ -0,0 +1,66 @@
+//go:build sqlite
+// +build sqlite
+
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWriteSubjectAccess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.db")
+
+	sa := result.NewSubjectAccess(schema.GroupResource{Group: "apps", Resource: "deployments"}, "")
+	role := result.RoleRef{Name: "editor", Kind: "ClusterRole"}
+	sa.MatchRules(role, v1.PolicyRule{
+		APIGroups: []string{"apps"},
+		Resources: []string{"deployments"},
+		Verbs:     []string{"get", "delete"},
+	})
+	sa.ResolveRoleRef(role, result.BindingRef{Name: "crb", Kind: "ClusterRoleBinding"}, []v1.Subject{{Kind: "User", Name: "alice"}})
+	sa.ResolveRoleRef(role, result.BindingRef{Name: "rb", Kind: "RoleBinding", Namespace: "ns"}, []v1.Subject{{Kind: "User", Name: "alice"}, {Kind: "Group", Name: "devs"}})
+
+	// two captures end up in the same database
+	require.NoError(t, WriteSubjectAccess(path, sa, []string{"get", "delete"}, "ns"))
+	require.NoError(t, WriteSubjectAccess(path, sa, []string{"delete"}, "ns"))
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	count := func(query string, args ...interface{}) int {
+		var n int
+		require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 2, count(`SELECT COUNT(*) FROM captures`))
+	assert.Equal(t, 2, count(`SELECT COUNT(*) FROM subjects`))
+	assert.Equal(t, 6, count(`SELECT COUNT(*) FROM grants WHERE capture_id = 1`))
+	assert.Equal(t, 3, count(`SELECT COUNT(*) FROM grants WHERE capture_id = 2`))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM grants g JOIN subjects s ON s.id = g.subject_id
+		WHERE g.capture_id = 2 AND s.name = 'alice' AND g.binding_kind = 'RoleBinding' AND g.namespace = 'ns' AND g.verb = 'delete'`))
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/corneliusweig/rakkess/internal/client"
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/corneliusweig/rakkess/internal/protobuf"
+	"github.com/corneliusweig/rakkess/internal/sqlite"
+	"github.com/corneliusweig/rakkess/internal/validation"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// Subject determines the subjects with access right to the given resource and
+// prints the result as a matrix with verbs in the horizontal and subject names
+// in the vertical direction.
+func Subject(ctx context.Context, opts *options.RakkessOptions, resourceWithOptionalAPIGroup, resourceName string) error {
+	subjectFilters, err := parseSubjectOptions(opts)
+	if err != nil {
+		return err
+	}
+	var baselineRole result.RoleRef
+	if opts.Exceeds != "" {
+		if opts.Intersect {
+			return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagExceeds, constants.FlagIntersect)
+		}
+		if baselineRole, err = result.ParseRoleRef(opts.Exceeds); err != nil {
+			return errors.Wrapf(err, "parse --%s", constants.FlagExceeds)
+		}
+	}
+	if opts.Intersect {
+		if opts.Union {
+			return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagIntersect, constants.FlagUnion)
+		}
+		if len(subjectFilters) < 2 {
+			return fmt.Errorf("--%s requires at least two --%s", constants.FlagIntersect, constants.FlagSubject)
+		}
+		if opts.OutputFormat != constants.OutputIconTable && opts.OutputFormat != constants.OutputASCIITable {
+			return fmt.Errorf("output format %s is not supported with --%s", opts.OutputFormat, constants.FlagIntersect)
+		}
+	}
+
+	gr, err := resolveGroupResource(opts, resourceWithOptionalAPIGroup)
+	if err != nil {
+		return err
+	}
+
+	members, err := loadGroupMembers(opts)
+	if err != nil {
+		return err
+	}
+
+	subjectAccess, err := getSubjectAccess(ctx, opts, gr, resourceName)
+	if err != nil {
+		return err
+	}
+
+	if subjectAccess.Empty() {
+		klog.Warningf("No subjects with access found. This most likely means that you have insufficient rights to review authorization.")
+		return nil
+	}
+
+	refineSubjectAccess(opts, subjectAccess, members, subjectFilters)
+
+	var baseline sets.String
+	if opts.Exceeds != "" {
+		if baseline, err = client.GetBaselineVerbs(ctx, opts, gr, resourceName, baselineRole); err != nil {
+			return err
+		}
+		subjectAccess.RetainExceeding(baseline, opts.Verbs)
+	}
+
+	var ns string
+	if namespace := opts.ConfigFlags.Namespace; namespace != nil {
+		ns = *namespace
+	}
+
+	if opts.Intersect {
+		if err := Render(opts, subjectAccess.IntersectionTable(subjectFilters, opts.Verbs)); err != nil {
+			return err
+		}
+	} else if opts.OutputFormat == constants.OutputSQLite {
+		if err := sqlite.WriteSubjectAccess(opts.OutputFile, subjectAccess, opts.Verbs, ns); err != nil {
+			return errors.Wrap(err, "write sqlite")
+		}
+	} else if opts.OutputFormat == constants.OutputTree {
+		if err := RenderTree(opts, subjectAccess.Tree(opts.Verbs)); err != nil {
+			return err
+		}
+	} else if opts.OutputFormat == constants.OutputLines {
+		if err := RenderLines(opts, subjectAccess.Lines(opts.Verbs)); err != nil {
+			return err
+		}
+	} else if opts.OutputFormat == constants.OutputCSVLong {
+		if err := RenderCSV(opts, result.CSVLongHeader, subjectAccess.LongRecords(opts.Verbs)); err != nil {
+			return err
+		}
+	} else if opts.OutputFormat == constants.OutputCSV {
+		if err := RenderCSV(opts, result.CSVHeader("subject", opts.Verbs), subjectAccess.CSVRecords(opts.Verbs)); err != nil {
+			return err
+		}
+	} else if opts.OutputFormat == constants.OutputJSON || opts.OutputFormat == constants.OutputYAML || opts.OutputFormat == constants.OutputProtobuf {
+		rows := subjectAccess.Rows(opts.Verbs)
+		if baseline != nil {
+			for i, row := range rows {
+				rows[i].Exceeds = subjectAccess.Exceeding(result.SubjectRef{Name: row.Name, Kind: row.Kind, Namespace: row.Namespace}, baseline, opts.Verbs)
+			}
+		}
+		if opts.OutputFormat == constants.OutputProtobuf {
+			err = RenderProtobuf(opts, func(w io.Writer) error { return protobuf.WriteSubjectRows(w, rows) })
+		} else {
+			err = RenderStructured(opts, rows)
+		}
+		if err != nil {
+			return err
+		}
+	} else if opts.Describe {
+		descriptions, err := client.LoadAccessDescriptions(opts)
+		if err != nil {
+			return err
+		}
+		if err := Render(opts, subjectAccess.DescriptionTable(opts.Verbs, descriptions)); err != nil {
+			return err
+		}
+	} else if err := Render(opts, subjectAccess.Table(opts.Verbs, result.TableOptions{Wide: opts.OutputFormat == constants.OutputWide, SubjectPrefix: opts.SubjectPrefix, Sources: opts.EffectiveIdentity, Scope: opts.AllNamespaces, SortBy: opts.SortBy, Roles: opts.ShowRoles, Baseline: baseline})); err != nil {
+		return err
+	}
+
+	if ns == "" && !opts.AllNamespaces {
+		fmt.Fprintf(noteWriter(opts), "Only ClusterRoleBindings are considered, because no namespace is given.\n")
+	}
+	printMastersNote(opts)
+
+	return nil
+}
+
+// Subjects determines the subjects with access right to several resources. By
+// default, the result is merged into one matrix with a column per resource and
+// verb. With --separate-tables, one titled matrix per resource is printed.
+func Subjects(ctx context.Context, opts *options.RakkessOptions, resourcesWithOptionalAPIGroup []string) error {
+	subjectFilters, err := parseSubjectOptions(opts)
+	if err != nil {
+		return err
+	}
+	if opts.OutputFormat != constants.OutputIconTable && opts.OutputFormat != constants.OutputASCIITable && opts.OutputFormat != constants.OutputMarkdown && opts.OutputFormat != constants.OutputHTML && !(opts.SeparateTables && opts.OutputFormat == constants.OutputWide) {
+		return fmt.Errorf("output format %s is not supported for several resources", opts.OutputFormat)
+	}
+	if opts.Intersect {
+		return fmt.Errorf("--%s is not supported for several resources", constants.FlagIntersect)
+	}
+	if opts.Exceeds != "" {
+		return fmt.Errorf("--%s is not supported for several resources", constants.FlagExceeds)
+	}
+	members, err := loadGroupMembers(opts)
+	if err != nil {
+		return err
+	}
+
+	var tables []*printer.Table
+	var columns []result.MergedColumn
+	for _, resource := range resourcesWithOptionalAPIGroup {
+		gr, err := resolveGroupResource(opts, resource)
+		if err != nil {
+			return err
+		}
+		subjectAccess, err := getSubjectAccess(ctx, opts, gr, "")
+		if err != nil {
+			return errors.Wrapf(err, "resource %s", gr)
+		}
+		refineSubjectAccess(opts, subjectAccess, members, subjectFilters)
+
+		if !opts.SeparateTables {
+			for _, verb := range opts.Verbs {
+				columns = append(columns, result.MergedColumn{Header: strings.ToUpper(verb + "-" + gr.String()), Access: subjectAccess, Verb: verb})
+			}
+			continue
+		}
+		table := subjectAccess.Table(opts.Verbs, result.TableOptions{Wide: opts.OutputFormat == constants.OutputWide, SubjectPrefix: opts.SubjectPrefix, Sources: opts.EffectiveIdentity, Scope: opts.AllNamespaces, SortBy: opts.SortBy, Roles: opts.ShowRoles})
+		table.Title = gr.String()
+		tables = append(tables, table)
+	}
+	if !opts.SeparateTables {
+		tables = append(tables, result.MergedTable(columns))
+	}
+	if err := Render(opts, tables...); err != nil {
+		return err
+	}
+
+	if ns := opts.ConfigFlags.Namespace; (ns == nil || *ns == "") && !opts.AllNamespaces {
+		fmt.Fprintf(noteWriter(opts), "Only ClusterRoleBindings are considered, because no namespace is given.\n")
+	}
+	printMastersNote(opts)
+	return nil
+}
+
+// parseSubjectOptions validates the options which all subject commands share,
+// and parses the filters of --subject. The kinds of --subject-kind are
+// normalized in place.
+func parseSubjectOptions(opts *options.RakkessOptions) ([]result.SubjectFilter, error) {
+	if err := validation.Output(opts); err != nil {
+		return nil, err
+	}
+	if err := validation.VerbFilters(opts); err != nil {
+		return nil, err
+	}
+	if opts.SubjectPrefix != "" {
+		if err := validation.SubjectPrefix(opts.SubjectPrefix); err != nil {
+			return nil, err
+		}
+	}
+	if err := validation.SortBy(opts.SortBy, constants.SubjectSortOrders); err != nil {
+		return nil, err
+	}
+	if err := validation.Only(opts.Only); err != nil {
+		return nil, err
+	}
+	var filters []result.SubjectFilter
+	for _, subject := range opts.Subject {
+		f, err := result.ParseSubjectFilter(subject)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse --%s", constants.FlagSubject)
+		}
+		filters = append(filters, f)
+	}
+	kinds, err := result.ParseSubjectKinds(opts.SubjectKinds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse --%s", constants.FlagSubjectKind)
+	}
+	opts.SubjectKinds = kinds
+	return filters, nil
+}
+
+// loadGroupMembers reads the file given by --group-members, if any.
+func loadGroupMembers(opts *options.RakkessOptions) (result.GroupMembers, error) {
+	if opts.EffectiveIdentity && opts.GroupMembersFile == "" {
+		return nil, fmt.Errorf("--%s requires --%s", constants.FlagEffectiveIdentity, constants.FlagGroupMembers)
+	}
+	if opts.GroupMembersFile == "" {
+		return nil, nil
+	}
+	return client.LoadGroupMembers(opts.GroupMembersFile)
+}
+
+// getSubjectAccess retrieves the subject access to the given resource, either
+// in the configured namespace or, with --all-namespaces, merged across all
+// namespaces.
+func getSubjectAccess(ctx context.Context, opts *options.RakkessOptions, gr schema.GroupResource, resourceName string) (*result.SubjectAccess, error) {
+	if ns := opts.ConfigFlags.Namespace; opts.AllNamespaces && ns != nil && *ns != "" {
+		return nil, fmt.Errorf("--%s cannot be combined with --namespace", constants.FlagAllNamespaces)
+	}
+	if opts.AllNamespaces {
+		nsa, err := client.GetSubjectAccessAllNamespaces(ctx, opts, gr, resourceName)
+		if err != nil {
+			return nil, errors.Wrap(err, "get subject access")
+		}
+		return nsa.Merge(), nil
+	}
+	subjectAccess, err := client.GetSubjectAccess(ctx, opts, gr, resourceName)
+	return subjectAccess, errors.Wrap(err, "get subject access")
+}
+
+// WatchSubject prints the subjects with access to the given resource, like
+// Subject, and prints the matrix again whenever a change of an RBAC object
+// changes it. The matrix is maintained incrementally, so that large clusters
+// need not be evaluated in full on every change. On a terminal, the screen is
+// cleared before every matrix.
+func WatchSubject(ctx context.Context, opts *options.RakkessOptions, resourceWithOptionalAPIGroup, resourceName string) error {
+	subjectFilters, err := parseSubjectOptions(opts)
+	if err != nil {
+		return err
+	}
+	switch opts.OutputFormat {
+	case constants.OutputIconTable, constants.OutputASCIITable, constants.OutputWide:
+	default:
+		return fmt.Errorf("output format %s is not supported with --%s", opts.OutputFormat, constants.FlagWatch)
+	}
+	if opts.AllNamespaces || opts.ResourceVersion != "" || opts.ChangedSince != 0 || opts.Intersect {
+		return fmt.Errorf("--%s cannot be combined with --%s, --%s, --%s, or --%s", constants.FlagWatch, constants.FlagAllNamespaces, constants.FlagResourceVersion, constants.FlagChangedSince, constants.FlagIntersect)
+	}
+	if opts.Exceeds != "" {
+		return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagExceeds, constants.FlagWatch)
+	}
+	if opts.SubjectLabelSelector != "" {
+		// the index only watches RBAC objects, not the labels of service-accounts
+		return fmt.Errorf("--%s cannot be combined with --%s", constants.FlagSubjectLabels, constants.FlagWatch)
+	}
+
+	gr, err := resolveGroupResource(opts, resourceWithOptionalAPIGroup)
+	if err != nil {
+		return err
+	}
+	members, err := loadGroupMembers(opts)
+	if err != nil {
+		return err
+	}
+
+	// on a terminal, every matrix replaces the previous one
+	redraw := opts.OutputFile == "" && isTerminal(opts.Streams.Out)
+	var last string
+	err = client.WatchSubjectAccess(ctx, opts, gr, resourceName, func(subjectAccess *result.SubjectAccess) error {
+		refineSubjectAccess(opts, subjectAccess, members, subjectFilters)
+		table := subjectAccess.Table(opts.Verbs, result.TableOptions{Wide: opts.OutputFormat == constants.OutputWide, SubjectPrefix: opts.SubjectPrefix, Sources: opts.EffectiveIdentity, SortBy: opts.SortBy, Roles: opts.ShowRoles})
+
+		// events which do not affect the resource leave the matrix unchanged
+		var buf bytes.Buffer
+		table.Render(&buf, constants.OutputASCIITable)
+		if buf.String() == last {
+			return nil
+		}
+		switch {
+		case redraw:
+			printer.ClearScreen(opts.Streams.Out)
+		case last != "" && opts.OutputFile == "":
+			fmt.Fprintln(opts.Streams.Out)
+		}
+		last = buf.String()
+		table.Title = time.Now().Format(time.RFC3339)
+		return Render(opts, table)
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return errors.Wrap(err, "watch subject access")
+}
+
+// refineSubjectAccess applies group members and the subject filters.
+func refineSubjectAccess(opts *options.RakkessOptions, subjectAccess *result.SubjectAccess, members result.GroupMembers, filters []result.SubjectFilter) {
+	if opts.EffectiveIdentity {
+		subjectAccess.EffectiveIdentities(members)
+	} else if members != nil {
+		subjectAccess.ExpandGroups(members)
+	}
+	if opts.IgnoreMasters {
+		subjectAccess.ExcludeMasters()
+	}
+	if len(filters) > 0 {
+		subjectAccess.RetainSubjects(filters...)
+	}
+	if len(opts.SubjectKinds) > 0 {
+		subjectAccess.RetainKinds(opts.SubjectKinds)
+	}
+	subjectAccess.RetainMinVerbs(opts.Verbs, opts.MinVerbs)
+	if len(opts.VerbsAllOf) > 0 {
+		subjectAccess.RetainAllOf(opts.VerbsAllOf)
+	}
+	if len(opts.VerbsAnyOf) > 0 {
+		subjectAccess.RetainAnyOf(opts.VerbsAnyOf)
+	}
+	if a, ok := OnlyAccess(opts); ok {
+		subjectAccess.RetainOnly(opts.Verbs, a)
+	}
+}
+
+// NonResourceSubject determines the subjects with access to the given
+// non-resource URLs, and prints one matrix per URL with verbs in the horizontal
+// and subject names in the vertical direction.
+func NonResourceSubject(ctx context.Context, opts *options.RakkessOptions, paths []string) error {
+	subjectFilters, err := parseSubjectOptions(opts)
+	if err != nil {
+		return err
+	}
+	if opts.OutputFormat == constants.OutputSQLite {
+		return fmt.Errorf("output format %s is not supported for non-resource URLs", constants.OutputSQLite)
+	}
+	members, err := loadGroupMembers(opts)
+	if err != nil {
+		return err
+	}
+
+	// all paths are rendered at once, so that they are paged together
+	var lines []string
+	var records [][]string
+	var trees []*printer.Tree
+	var tables []*printer.Table
+	for _, path := range paths {
+		subjectAccess, err := client.GetNonResourceSubjectAccess(ctx, opts, path)
+		if err != nil {
+			return errors.Wrapf(err, "get subject access for %s", path)
+		}
+		refineSubjectAccess(opts, subjectAccess, members, subjectFilters)
+
+		if opts.OutputFormat == constants.OutputLines {
+			lines = append(lines, subjectAccess.Lines(opts.Verbs)...)
+			continue
+		}
+		if opts.OutputFormat == constants.OutputCSVLong {
+			records = append(records, subjectAccess.LongRecords(opts.Verbs)...)
+			continue
+		}
+		if opts.OutputFormat == constants.OutputTree {
+			trees = append(trees, subjectAccess.Tree(opts.Verbs))
+			continue
+		}
+		table := subjectAccess.Table(opts.Verbs, result.TableOptions{Wide: opts.OutputFormat == constants.OutputWide, SubjectPrefix: opts.SubjectPrefix, SortBy: opts.SortBy, Roles: opts.ShowRoles})
+		table.Title = path
+		tables = append(tables, table)
+	}
+	switch opts.OutputFormat {
+	case constants.OutputLines:
+		err = RenderLines(opts, lines)
+	case constants.OutputCSVLong:
+		err = RenderCSV(opts, result.CSVLongHeader, records)
+	case constants.OutputTree:
+		err = RenderTree(opts, trees...)
+	default:
+		err = Render(opts, tables...)
+	}
+	if err != nil {
+		return err
+	}
+	printMastersNote(opts)
+	return nil
+}
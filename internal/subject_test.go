@@ -0,0 +1,52 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubjectOptions(t *testing.T) {
+	opts, _, _, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = constants.OutputIconTable
+	opts.Subject = []string{"user:alice", "sa:ci:deployer"}
+	opts.SubjectKinds = []string{"serviceaccount"}
+
+	filters, err := parseSubjectOptions(opts)
+	require.NoError(t, err)
+	assert.Len(t, filters, 2)
+	assert.Equal(t, []string{"ServiceAccount"}, opts.SubjectKinds)
+
+	opts.SortBy = "cassowary"
+	_, err = parseSubjectOptions(opts)
+	assert.Error(t, err)
+}
+
+func TestWatchSubject_subjectLabels(t *testing.T) {
+	opts, _, _, _ := options.NewTestRakkessOptions()
+	opts.OutputFormat = constants.OutputIconTable
+	opts.SubjectLabelSelector = "team=platform"
+
+	err := WatchSubject(context.Background(), opts, "secrets", "")
+	assert.EqualError(t, err, "--subject-labels cannot be combined with --watch")
+}
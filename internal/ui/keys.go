@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import "unicode/utf8"
+
+// key is a single key press. Printable keys are the typed character, other
+// keys have a name in angle brackets.
+type key string
+
+const (
+	keyUp        key = "<up>"
+	keyDown      key = "<down>"
+	keyLeft      key = "<left>"
+	keyRight     key = "<right>"
+	keyPageUp    key = "<pgup>"
+	keyPageDown  key = "<pgdown>"
+	keyHome      key = "<home>"
+	keyEnd       key = "<end>"
+	keyEnter     key = "<enter>"
+	keyEsc       key = "<esc>"
+	keyBackspace key = "<backspace>"
+	keyCtrlC     key = "<ctrl-c>"
+)
+
+// csiKeys maps the final byte of cursor key sequences such as ESC [ A.
+var csiKeys = map[byte]key{
+	'A': keyUp,
+	'B': keyDown,
+	'C': keyRight,
+	'D': keyLeft,
+	'H': keyHome,
+	'F': keyEnd,
+}
+
+// tildeKeys maps the parameter of sequences such as ESC [ 5 ~.
+var tildeKeys = map[string]key{
+	"1": keyHome,
+	"4": keyEnd,
+	"5": keyPageUp,
+	"6": keyPageDown,
+	"7": keyHome,
+	"8": keyEnd,
+}
+
+// parseKeys splits the input which was read from a terminal in raw mode into
+// key presses. Unknown escape sequences and control characters are dropped.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == 0x1b:
+			if i+1 >= len(b) || (b[i+1] != '[' && b[i+1] != 'O') {
+				keys = append(keys, keyEsc)
+				i++
+				continue
+			}
+			// the sequence ends with a byte in the range @ to ~
+			j := i + 2
+			for j < len(b) && (b[j] < 0x40 || b[j] > 0x7e) {
+				j++
+			}
+			if j == len(b) {
+				return keys
+			}
+			if b[j] == '~' {
+				if k, ok := tildeKeys[string(b[i+2:j])]; ok {
+					keys = append(keys, k)
+				}
+			} else if k, ok := csiKeys[b[j]]; ok {
+				keys = append(keys, k)
+			}
+			i = j + 1
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+			i++
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyBackspace)
+			i++
+		case c == 0x03:
+			keys = append(keys, keyCtrlC)
+			i++
+		case c < 0x20:
+			i++
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r != utf8.RuneError {
+				keys = append(keys, key(string(r)))
+			}
+			i += size
+		}
+	}
+	return keys
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []key
+	}{
+		{name: "printable", input: "q/ä", expected: []key{"q", "/", "ä"}},
+		{name: "cursor keys", input: "\033[A\033[B\033OC\033[D", expected: []key{keyUp, keyDown, keyRight, keyLeft}},
+		{name: "page keys", input: "\033[5~\033[6~\033[1~\033[F", expected: []key{keyPageUp, keyPageDown, keyHome, keyEnd}},
+		{name: "lone escape", input: "\033", expected: []key{keyEsc}},
+		{name: "escape before key", input: "\033x", expected: []key{keyEsc, "x"}},
+		{name: "control keys", input: "\r\x7f\x03\t", expected: []key{keyEnter, keyBackspace, keyCtrlC}},
+		{name: "unknown and incomplete sequences", input: "\033[3~a\033[1;5", expected: []key{"a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseKeys([]byte(test.input)))
+		})
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// View is a table which is shown by the UI.
+type View struct {
+	Title string
+	Table *printer.Table
+	// Open returns the view which details the row with the given index of
+	// Table. If Open is nil, the rows cannot be opened.
+	Open func(row int) (*View, error)
+}
+
+// scrollStep is the number of columns which left and right scroll by.
+const scrollStep = 8
+
+const reset = "\033[0m"
+
+const help = "↑↓ move  ←→ scroll  enter open  / filter  esc back  q quit"
+
+// frame is a view on the stack of opened views, together with its navigation state.
+type frame struct {
+	view   *View
+	header string
+	// lines are the rendered rows of the table.
+	lines []string
+	// visible are the indices of the rows which match the filter.
+	visible []int
+	filter  string
+	// cursor is the index of the selected row in visible.
+	cursor int
+	// top is the index of the first row on the screen in visible.
+	top int
+}
+
+func newFrame(v *View) *frame {
+	lines := v.Table.Lines()
+	f := &frame{view: v}
+	if len(v.Table.Headers) > 0 {
+		f.header, lines = lines[0], lines[1:]
+	}
+	f.lines = lines[:len(v.Table.Rows)]
+	f.setFilter("")
+	return f
+}
+
+// setFilter only keeps the rows whose columns contain the filter, ignoring case.
+func (f *frame) setFilter(filter string) {
+	f.filter = filter
+	f.visible = f.visible[:0]
+	needle := strings.ToLower(filter)
+	for i, row := range f.view.Table.Rows {
+		text := strings.ToLower(strings.Join(append(append([]string{}, row.Intro...), row.Outro...), " "))
+		if strings.Contains(text, needle) {
+			f.visible = append(f.visible, i)
+		}
+	}
+	f.cursor, f.top = 0, 0
+}
+
+func (f *frame) move(delta int) {
+	f.cursor += delta
+	if f.cursor >= len(f.visible) {
+		f.cursor = len(f.visible) - 1
+	}
+	if f.cursor < 0 {
+		f.cursor = 0
+	}
+}
+
+// model is the state of the UI, independent of the terminal.
+type model struct {
+	stack   []*frame
+	editing bool
+	status  string
+	// offset is the number of columns which are scrolled out to the left.
+	offset int
+	// page is the number of rows on the screen, as of the last render.
+	page int
+}
+
+func newModel(root *View) *model {
+	return &model{stack: []*frame{newFrame(root)}, page: 10}
+}
+
+func (m *model) current() *frame {
+	return m.stack[len(m.stack)-1]
+}
+
+// opens tells whether the key opens the selected row, which may take a while.
+func (m *model) opens(k key) bool {
+	f := m.current()
+	return k == keyEnter && !m.editing && f.view.Open != nil && len(f.visible) > 0
+}
+
+// handle applies the key press and tells whether the UI should quit.
+func (m *model) handle(k key) bool {
+	m.status = ""
+	f := m.current()
+
+	if m.editing {
+		switch k {
+		case keyCtrlC:
+			return true
+		case keyEnter:
+			m.editing = false
+		case keyEsc:
+			m.editing = false
+			f.setFilter("")
+		case keyBackspace:
+			if r := []rune(f.filter); len(r) > 0 {
+				f.setFilter(string(r[:len(r)-1]))
+			}
+		default:
+			if len([]rune(string(k))) == 1 {
+				f.setFilter(f.filter + string(k))
+			}
+		}
+		return false
+	}
+
+	switch k {
+	case "q", keyCtrlC:
+		return true
+	case keyUp, "k":
+		f.move(-1)
+	case keyDown, "j":
+		f.move(1)
+	case keyPageUp:
+		f.move(-m.page)
+	case keyPageDown:
+		f.move(m.page)
+	case keyHome, "g":
+		f.move(-len(f.visible))
+	case keyEnd, "G":
+		f.move(len(f.visible))
+	case keyLeft, "h":
+		m.offset -= scrollStep
+		if m.offset < 0 {
+			m.offset = 0
+		}
+	case keyRight, "l":
+		m.offset += scrollStep
+	case "/":
+		m.editing = true
+	case keyEnter:
+		m.open()
+	case keyEsc, keyBackspace:
+		if len(m.stack) > 1 {
+			m.stack = m.stack[:len(m.stack)-1]
+			m.offset = 0
+		} else if f.filter != "" {
+			f.setFilter("")
+		}
+	}
+	return false
+}
+
+func (m *model) open() {
+	if !m.opens(keyEnter) {
+		return
+	}
+	f := m.current()
+	v, err := f.view.Open(f.visible[f.cursor])
+	if err != nil {
+		m.status = fmt.Sprintf("error: %v", err)
+		return
+	}
+	if v == nil {
+		return
+	}
+	m.stack = append(m.stack, newFrame(v))
+	m.offset = 0
+}
+
+// render lays out the screen with the given size. The first line shows the
+// titles of the opened views, the second the table headers, and the last one
+// the filter, the status, or the key bindings.
+func (m *model) render(width, height int) []string {
+	f := m.current()
+
+	titles := make([]string, 0, len(m.stack))
+	for _, fr := range m.stack {
+		titles = append(titles, fr.view.Title)
+	}
+	title := fmt.Sprintf("%s (%d/%d)", strings.Join(titles, " > "), len(f.visible), len(f.lines))
+
+	body := height - 3
+	if body < 1 {
+		body = 1
+	}
+	m.page = body
+	if f.cursor < f.top {
+		f.top = f.cursor
+	}
+	if f.cursor >= f.top+body {
+		f.top = f.cursor - body + 1
+	}
+
+	screen := []string{clip(title, 0, width), "  " + clip(f.header, m.offset, width-2)}
+	for i := f.top; i < f.top+body; i++ {
+		switch {
+		case i < len(f.visible):
+			prefix := "  "
+			if i == f.cursor {
+				prefix = "> "
+			}
+			screen = append(screen, prefix+clip(f.lines[f.visible[i]], m.offset, width-2))
+		case i == 0:
+			screen = append(screen, "  no matching rows")
+		default:
+			screen = append(screen, "")
+		}
+	}
+
+	footer := help
+	switch {
+	case m.editing:
+		footer = "/" + f.filter + "_"
+	case m.status != "":
+		footer = m.status
+	case f.filter != "":
+		footer = fmt.Sprintf("filter: %s  (esc clears)  %s", f.filter, help)
+	}
+	return append(screen, clip(footer, 0, width))
+}
+
+// clip cuts the line to the columns from offset to offset+width. ANSI escape
+// sequences do not take up columns and are always kept, so that colors stay
+// intact. A color which is still set at the end of the line is reset.
+func clip(line string, offset, width int) string {
+	var b strings.Builder
+	col, colored := 0, false
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\033' && i+1 < len(runes) && runes[i+1] == '[' {
+			j := i + 2
+			for j < len(runes) && (runes[j] < 0x40 || runes[j] > 0x7e) {
+				j++
+			}
+			if j == len(runes) {
+				break
+			}
+			seq := string(runes[i : j+1])
+			b.WriteString(seq)
+			colored = seq != reset
+			i = j
+			continue
+		}
+		if col >= offset && col < offset+width {
+			b.WriteRune(r)
+		}
+		col++
+	}
+	if colored {
+		b.WriteString(reset)
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"github.com/stretchr/testify/assert"
+)
+
+func resourceView(opened *[]int) *View {
+	return &View{
+		Title: "resources",
+		Table: &printer.Table{
+			Headers: []string{"NAME", "GET"},
+			Rows: []printer.Row{
+				{Intro: []string{"configmaps"}, Entries: []printer.Outcome{printer.Up}},
+				{Intro: []string{"pods"}, Entries: []printer.Outcome{printer.Up}},
+				{Intro: []string{"secrets"}, Entries: []printer.Outcome{printer.Down}},
+			},
+		},
+		Open: func(row int) (*View, error) {
+			*opened = append(*opened, row)
+			if row == 0 {
+				return nil, fmt.Errorf("forbidden")
+			}
+			return &View{Title: "subjects", Table: &printer.Table{Headers: []string{"NAME"}}}, nil
+		},
+	}
+}
+
+func press(m *model, keys ...key) {
+	for _, k := range keys {
+		m.handle(k)
+	}
+}
+
+func TestModel_navigation(t *testing.T) {
+	var opened []int
+	m := newModel(resourceView(&opened))
+
+	press(m, keyDown, keyDown, keyDown)
+	assert.Equal(t, 2, m.current().cursor)
+	press(m, "k")
+	assert.Equal(t, 1, m.current().cursor)
+	press(m, "G")
+	assert.Equal(t, 2, m.current().cursor)
+	press(m, keyHome, keyUp)
+	assert.Equal(t, 0, m.current().cursor)
+
+	press(m, keyRight, keyRight, keyLeft)
+	assert.Equal(t, scrollStep, m.offset)
+
+	assert.True(t, m.handle("q"))
+}
+
+func TestModel_filter(t *testing.T) {
+	var opened []int
+	m := newModel(resourceView(&opened))
+
+	press(m, keyDown, "/", "S", "e")
+	assert.True(t, m.editing)
+	assert.Equal(t, []int{2}, m.current().visible)
+	assert.Equal(t, 0, m.current().cursor)
+	assert.False(t, m.handle("q"), "q is typed into the filter")
+	assert.Empty(t, m.current().visible)
+
+	press(m, keyBackspace, keyBackspace, keyEnter)
+	assert.False(t, m.editing)
+	assert.Equal(t, "S", m.current().filter)
+	assert.Equal(t, []int{0, 1, 2}, m.current().visible)
+
+	press(m, "/", "o", keyEsc)
+	assert.Equal(t, "", m.current().filter)
+
+	press(m, "/", "p", keyEnter, keyEsc)
+	assert.Equal(t, "", m.current().filter, "esc clears the filter of the root view")
+}
+
+func TestModel_open(t *testing.T) {
+	var opened []int
+	m := newModel(resourceView(&opened))
+
+	press(m, keyEnter)
+	assert.Len(t, m.stack, 1)
+	assert.Equal(t, "error: forbidden", m.status)
+
+	press(m, "/", "o", "d", keyEnter)
+	assert.True(t, m.opens(keyEnter))
+	press(m, keyEnter)
+	assert.Equal(t, []int{0, 1}, opened)
+	assert.Len(t, m.stack, 2)
+	assert.Equal(t, "", m.status)
+	assert.False(t, m.opens(keyEnter), "the subjects cannot be opened")
+
+	press(m, keyEsc)
+	assert.Len(t, m.stack, 1)
+	assert.Equal(t, "od", m.current().filter, "the filter is kept when going back")
+}
+
+func TestModel_render(t *testing.T) {
+	var opened []int
+	m := newModel(resourceView(&opened))
+	press(m, keyDown, keyDown)
+
+	assert.Equal(t, []string{
+		"resources (3/3)",
+		"  NAME        GET",
+		"  pods        \033[32m✔\033[0m",
+		"> secrets     \033[31m✖\033[0m",
+		help,
+	}, m.render(80, 5))
+	assert.Equal(t, 2, m.page)
+
+	press(m, "/", "x")
+	assert.Equal(t, []string{
+		"resources (0/3)",
+		"  NAME        GET",
+		"  no matching rows",
+		"",
+		"/x_",
+	}, m.render(80, 5))
+}
+
+func TestClip(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          string
+		offset, width int
+		expected      string
+	}{
+		{name: "fits", line: "pods", width: 10, expected: "pods"},
+		{name: "cut", line: "configmaps", width: 6, expected: "config"},
+		{name: "scrolled", line: "configmaps", offset: 6, width: 6, expected: "maps"},
+		{name: "colors are kept", line: "a  \033[32m✔\033[0m  b", offset: 1, width: 3, expected: "  \033[32m✔\033[0m"},
+		{name: "scrolled past colors", line: "\033[31m✖\033[0m  b", offset: 3, width: 3, expected: "\033[31m\033[0mb"},
+		{name: "color cut off", line: "\033[32myes\033[0m", width: 1, expected: "\033[32my\033[0m"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, clip(test.line, test.offset, test.width))
+		})
+	}
+}
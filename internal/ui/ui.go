@@ -0,0 +1,113 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+	"k8s.io/klog/v2"
+)
+
+const (
+	enterAltScreen = "\033[?1049h\033[?25l"
+	leaveAltScreen = "\033[?25h\033[?1049l"
+)
+
+// CheckTerminal fails unless both input and output are an interactive terminal.
+func CheckTerminal(in, out *os.File) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return errors.New("the ui needs an interactive terminal")
+	}
+	return nil
+}
+
+// Run shows the view full-screen on the terminal, until the user quits or the
+// context is cancelled.
+func Run(ctx context.Context, in, out *os.File, root *View) error {
+	if err := CheckTerminal(in, out); err != nil {
+		return err
+	}
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return errors.Wrap(err, "enable raw terminal mode")
+	}
+	defer func() {
+		if err := term.Restore(inFd, state); err != nil {
+			klog.Warningf("Could not restore the terminal: %v", err)
+		}
+	}()
+	fmt.Fprint(out, enterAltScreen)
+	defer fmt.Fprint(out, leaveAltScreen)
+
+	done := make(chan struct{})
+	defer close(done)
+	keys, errs := make(chan []key), make(chan error, 1)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case keys <- parseKeys(buf[:n]):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	m := newModel(root)
+	for {
+		draw(out, outFd, m)
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return errors.Wrap(err, "read input")
+		case ks := <-keys:
+			for _, k := range ks {
+				if m.opens(k) {
+					m.status = "loading..."
+					draw(out, outFd, m)
+				}
+				if m.handle(k) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// draw renders the model over the previous screen. Every line is cleared to its
+// end, which flickers less than clearing the whole screen.
+func draw(out io.Writer, fd int, m *model) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	lines := m.render(width, height)
+	fmt.Fprint(out, "\033[H"+strings.Join(lines, "\033[K\r\n")+"\033[K\033[J")
+}
@@ -18,7 +18,9 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/corneliusweig/rakkess/internal/client/result"
 	"github.com/corneliusweig/rakkess/internal/constants"
 	"github.com/corneliusweig/rakkess/internal/options"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -26,12 +28,103 @@ import (
 
 // Options validates RakkessOptions. Fields validated:
 // - OutputFormat
-// - Verbs
+// - OutputFile
+// - Compress
+// - Verbs, unless AllowCustomVerbs is set
+// - RequireAllowed
+// - FailIfAllowed
+// - NamespaceColumnPosition
+// - MaxConcurrency
+// - SortBy
+// - Only
+// - AllowedOnly
 func Options(opts *options.RakkessOptions) error {
-	if err := verbs(opts.Verbs); err != nil {
+	if !opts.AllowCustomVerbs {
+		if err := verbs(opts.Verbs); err != nil {
+			return fmt.Errorf("%v, use --%s to check custom verbs", err, constants.FlagAllowCustomVerbs)
+		}
+	}
+	if p := opts.NamespaceColumnPosition; p != "" && p != constants.NamespaceColumnFirst && p != constants.NamespaceColumnLast {
+		return fmt.Errorf("unexpected namespace column position: %s", p)
+	}
+	if opts.MaxConcurrency < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagMaxConcurrency, opts.MaxConcurrency)
+	}
+	if opts.MaxRetries < 0 {
+		return fmt.Errorf("--%s must not be negative, got %d", constants.FlagMaxRetries, opts.MaxRetries)
+	}
+	if opts.MaxRetries > constants.MaxRetriesLimit {
+		return fmt.Errorf("--%s must be at most %d, got %d", constants.FlagMaxRetries, constants.MaxRetriesLimit, opts.MaxRetries)
+	}
+	if err := SortBy(opts.SortBy, constants.ResourceSortOrders); err != nil {
+		return err
+	}
+	if err := Only(opts.Only); err != nil {
+		return err
+	}
+	if opts.AllowedOnly && opts.OutputFormat != constants.OutputJSON && opts.OutputFormat != constants.OutputYAML && opts.OutputFormat != constants.OutputProtobuf {
+		return fmt.Errorf("--%s is only supported by the output formats %s, %s, and %s", constants.FlagAllowedOnly, constants.OutputJSON, constants.OutputYAML, constants.OutputProtobuf)
+	}
+	if err := assertions(opts); err != nil {
 		return err
 	}
-	return OutputFormat(opts.OutputFormat)
+	return Output(opts)
+}
+
+// Output validates the output settings of RakkessOptions. Fields validated:
+// - OutputFormat
+// - OutputFile
+// - Compress
+// - Pager
+func Output(opts *options.RakkessOptions) error {
+	if err := OutputFormat(opts.OutputFormat); err != nil {
+		return err
+	}
+	if (opts.OutputFormat == constants.OutputSQLite || opts.OutputFormat == constants.OutputJUnit) && opts.OutputFile == "" {
+		return fmt.Errorf("output format %s requires --%s", opts.OutputFormat, constants.FlagOutputFile)
+	}
+	if opts.Compress != "" && opts.Compress != constants.CompressGzip {
+		return fmt.Errorf("unexpected compression: %s", opts.Compress)
+	}
+	if opts.OutputFormat == constants.OutputSQLite && (opts.Compress != "" || strings.HasSuffix(opts.OutputFile, ".gz")) {
+		return fmt.Errorf("output format %s does not support compression", constants.OutputSQLite)
+	}
+	if opts.Pager && (opts.NoPager || opts.Watch || opts.OutputFile != "" || opts.Compress != "") {
+		return fmt.Errorf("--%s cannot be combined with --%s, --%s, --%s, or --%s", constants.FlagPager, constants.FlagNoPager, constants.FlagWatch, constants.FlagOutputFile, constants.FlagCompress)
+	}
+	return nil
+}
+
+// SubjectPrefix validates how the kind of subjects is shown.
+func SubjectPrefix(prefix string) error {
+	for _, p := range constants.SubjectPrefixes {
+		if p == prefix {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected subject prefix: %s", prefix)
+}
+
+// SortBy validates the order of the matrix rows against the valid orders. The
+// empty order stands for the default order.
+func SortBy(sortBy string, valid []string) error {
+	if sortBy == "" {
+		return nil
+	}
+	for _, o := range valid {
+		if o == sortBy {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected --%s %s, valid values are (%s)", constants.FlagSortBy, sortBy, strings.Join(valid, ", "))
+}
+
+// Only validates the filter of the matrix rows. The empty filter keeps all rows.
+func Only(only string) error {
+	if only != "" && only != constants.OnlyAllowed && only != constants.OnlyDenied {
+		return fmt.Errorf("unexpected --%s %s, valid values are (%s, %s)", constants.FlagOnly, only, constants.OnlyAllowed, constants.OnlyDenied)
+	}
+	return nil
 }
 
 func OutputFormat(format string) error {
@@ -43,6 +136,45 @@ func OutputFormat(format string) error {
 	return fmt.Errorf("unexpected output format: %s", format)
 }
 
+func assertions(opts *options.RakkessOptions) error {
+	parsed, err := result.ParseAssertions(constants.FlagRequireAllowed, opts.RequireAllowed, constants.FlagFailIfAllowed, opts.FailIfAllowed)
+	if err != nil {
+		return err
+	}
+	if opts.OutputFormat == constants.OutputJUnit && len(parsed) == 0 {
+		return fmt.Errorf("output format %s requires --%s or --%s", constants.OutputJUnit, constants.FlagRequireAllowed, constants.FlagFailIfAllowed)
+	}
+	checked := sets.NewString(opts.Verbs...)
+	for _, a := range parsed {
+		if !checked.Has(a.Verb) {
+			return fmt.Errorf("assertion %q uses verb %s which is not part of --%s", a.Name(), a.Verb, constants.FlagVerbs)
+		}
+	}
+	return nil
+}
+
+// NonResourceVerbs validates verbs for non-resource URLs.
+func NonResourceVerbs(verbs []string) error {
+	difference := sets.NewString(verbs...).Difference(sets.NewString(constants.NonResourceVerbs...))
+	if difference.Len() > 0 {
+		return fmt.Errorf("unexpected verbs for non-resource URLs: %s", difference.List())
+	}
+	return nil
+}
+
+// VerbFilters validates that the verbs of --verbs-all-of and --verbs-any-of
+// are part of --verbs, so that the matrix shows why a subject is kept.
+func VerbFilters(opts *options.RakkessOptions) error {
+	checked := sets.NewString(opts.Verbs...)
+	if missing := sets.NewString(opts.VerbsAllOf...).Difference(checked); missing.Len() > 0 {
+		return fmt.Errorf("--%s uses verbs which are not part of --%s: %s", constants.FlagVerbsAllOf, constants.FlagVerbs, strings.Join(missing.List(), ", "))
+	}
+	if missing := sets.NewString(opts.VerbsAnyOf...).Difference(checked); missing.Len() > 0 {
+		return fmt.Errorf("--%s uses verbs which are not part of --%s: %s", constants.FlagVerbsAnyOf, constants.FlagVerbs, strings.Join(missing.List(), ", "))
+	}
+	return nil
+}
+
 func verbs(verbs []string) error {
 	valid := sets.NewString(constants.ValidVerbs...)
 	given := sets.NewString(verbs...)
@@ -19,6 +19,7 @@ package validation
 import (
 	"testing"
 
+	"github.com/corneliusweig/rakkess/internal/options"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,6 +52,232 @@ func TestOutputFormat(t *testing.T) {
 	}
 }
 
+func TestOptions_namespaceColumnPosition(t *testing.T) {
+	for _, position := range []string{"", "first", "last"} {
+		opts := &options.RakkessOptions{OutputFormat: "icon-table", NamespaceColumnPosition: position}
+		assert.NoError(t, Options(opts))
+	}
+	opts := &options.RakkessOptions{OutputFormat: "icon-table", NamespaceColumnPosition: "middle"}
+	assert.EqualError(t, Options(opts), "unexpected namespace column position: middle")
+}
+
+func TestOptions_maxConcurrency(t *testing.T) {
+	opts := &options.RakkessOptions{OutputFormat: "icon-table", MaxConcurrency: 8}
+	assert.NoError(t, Options(opts))
+	opts = &options.RakkessOptions{OutputFormat: "icon-table", MaxConcurrency: -1}
+	assert.EqualError(t, Options(opts), "--max-concurrency must not be negative, got -1")
+}
+
+func TestOptions_maxRetries(t *testing.T) {
+	opts := &options.RakkessOptions{OutputFormat: "icon-table", MaxRetries: 0}
+	assert.NoError(t, Options(opts))
+	opts.MaxRetries = -2
+	assert.EqualError(t, Options(opts), "--max-retries must not be negative, got -2")
+	opts.MaxRetries = 50
+	assert.EqualError(t, Options(opts), "--max-retries must be at most 10, got 50")
+}
+
+func TestOptions_allowedOnly(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "protobuf"} {
+		opts := &options.RakkessOptions{OutputFormat: format, AllowedOnly: true}
+		assert.NoError(t, Options(opts))
+	}
+	opts := &options.RakkessOptions{OutputFormat: "icon-table", AllowedOnly: true}
+	assert.EqualError(t, Options(opts), "--allowed-only is only supported by the output formats json, yaml, and protobuf")
+}
+
+func TestOptions_allowCustomVerbs(t *testing.T) {
+	opts := &options.RakkessOptions{OutputFormat: "icon-table", Verbs: []string{"get", "approve"}}
+	assert.EqualError(t, Options(opts), "unexpected verbs: [approve], use --allow-custom-verbs to check custom verbs")
+	opts.AllowCustomVerbs = true
+	assert.NoError(t, Options(opts))
+}
+
+func TestSortBy(t *testing.T) {
+	for _, sortBy := range []string{"", "group", "name", "access"} {
+		opts := &options.RakkessOptions{OutputFormat: "icon-table", SortBy: sortBy}
+		assert.NoError(t, Options(opts))
+	}
+	opts := &options.RakkessOptions{OutputFormat: "icon-table", SortBy: "verbs"}
+	assert.EqualError(t, Options(opts), "unexpected --sort-by verbs, valid values are (group, name, access)")
+	assert.EqualError(t, SortBy("group", []string{"name", "access"}), "unexpected --sort-by group, valid values are (name, access)")
+}
+
+func TestOnly(t *testing.T) {
+	for _, only := range []string{"", "allowed", "denied"} {
+		assert.NoError(t, Only(only))
+	}
+	assert.EqualError(t, Only("error"), "unexpected --only error, valid values are (allowed, denied)")
+}
+
+func TestNonResourceVerbs(t *testing.T) {
+	assert.NoError(t, NonResourceVerbs([]string{"get", "post"}))
+	assert.EqualError(t, NonResourceVerbs([]string{"get", "list"}), "unexpected verbs for non-resource URLs: [list]")
+}
+
+func TestVerbFilters(t *testing.T) {
+	opts := &options.RakkessOptions{Verbs: []string{"get", "list", "delete"}, VerbsAllOf: []string{"get", "delete"}, VerbsAnyOf: []string{"list"}}
+	assert.NoError(t, VerbFilters(opts))
+	opts.VerbsAllOf = []string{"get", "patch", "escalate"}
+	assert.EqualError(t, VerbFilters(opts), "--verbs-all-of uses verbs which are not part of --verbs: escalate, patch")
+	opts.VerbsAllOf = nil
+	opts.VerbsAnyOf = []string{"create"}
+	assert.EqualError(t, VerbFilters(opts), "--verbs-any-of uses verbs which are not part of --verbs: create")
+}
+
+func TestSubjectPrefix(t *testing.T) {
+	for _, prefix := range []string{"column", "abbrev", "emoji"} {
+		assert.NoError(t, SubjectPrefix(prefix))
+	}
+	assert.EqualError(t, SubjectPrefix("icon"), "unexpected subject prefix: icon")
+}
+
+func TestOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		file     string
+		compress string
+		pager    bool
+		noPager  bool
+		expected string
+	}{
+		{
+			name:   "table to stdout",
+			format: "icon-table",
+		},
+		{
+			name:   "table to file",
+			format: "ascii-table",
+			file:   "out.txt",
+		},
+		{
+			name:   "sqlite to file",
+			format: "sqlite",
+			file:   "audit.db",
+		},
+		{
+			name:     "sqlite without file",
+			format:   "sqlite",
+			expected: "output format sqlite requires --output-file",
+		},
+		{
+			name:     "junit without file",
+			format:   "junit",
+			expected: "output format junit requires --output-file",
+		},
+		{
+			name:     "invalid format",
+			format:   "cassowary",
+			expected: "unexpected output format: cassowary",
+		},
+		{
+			name:     "gzip compressed table",
+			format:   "icon-table",
+			compress: "gzip",
+		},
+		{
+			name:     "invalid compression",
+			format:   "icon-table",
+			compress: "zip",
+			expected: "unexpected compression: zip",
+		},
+		{
+			name:     "compressed sqlite",
+			format:   "sqlite",
+			file:     "audit.db",
+			compress: "gzip",
+			expected: "output format sqlite does not support compression",
+		},
+		{
+			name:     "sqlite to gz file",
+			format:   "sqlite",
+			file:     "audit.db.gz",
+			expected: "output format sqlite does not support compression",
+		},
+		{
+			name:   "forced pager",
+			format: "icon-table",
+			pager:  true,
+		},
+		{
+			name:     "forced pager to file",
+			format:   "icon-table",
+			file:     "out.txt",
+			pager:    true,
+			expected: "--pager cannot be combined with --no-pager, --watch, --output-file, or --compress",
+		},
+		{
+			name:     "pager and no pager",
+			format:   "icon-table",
+			pager:    true,
+			noPager:  true,
+			expected: "--pager cannot be combined with --no-pager, --watch, --output-file, or --compress",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &options.RakkessOptions{
+				OutputFormat: test.format,
+				OutputFile:   test.file,
+				Compress:     test.compress,
+				Pager:        test.pager,
+				NoPager:      test.noPager,
+			}
+			actual := Output(opts)
+			if test.expected != "" {
+				assert.EqualError(t, actual, test.expected)
+			} else {
+				assert.NoError(t, actual)
+			}
+		})
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		require  []string
+		failIf   []string
+		expected string
+	}{
+		{
+			name:    "checked verbs",
+			require: []string{"list:pods"},
+			failIf:  []string{"delete:pods"},
+		},
+		{
+			name:     "unchecked verb",
+			require:  []string{"get:pods"},
+			expected: `assertion "require-allowed get:pods" uses verb get which is not part of --verbs`,
+		},
+		{
+			name:     "junit without assertions",
+			format:   "junit",
+			expected: "output format junit requires --require-allowed or --fail-if-allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &options.RakkessOptions{
+				Verbs:          []string{"list", "delete"},
+				OutputFormat:   test.format,
+				RequireAllowed: test.require,
+				FailIfAllowed:  test.failIf,
+			}
+			actual := assertions(opts)
+			if test.expected != "" {
+				assert.EqualError(t, actual, test.expected)
+			} else {
+				assert.NoError(t, actual)
+			}
+		})
+	}
+}
+
 func TestVerbs(t *testing.T) {
 	tests := []struct {
 		name     string
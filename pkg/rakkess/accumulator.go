@@ -0,0 +1,54 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rakkess
+
+import "github.com/corneliusweig/rakkess/internal/client/result"
+
+// ResultAccumulator collects the outcomes of access reviews into a
+// ResourceAccess, for programs which send the reviews themselves, e.g. with
+// their own parallelism or rate limits.
+//
+// All methods are safe for concurrent use by multiple goroutines. A zero
+// ResultAccumulator is ready to use.
+type ResultAccumulator struct {
+	acc result.ResultAccumulator
+}
+
+// NewResultAccumulator creates an empty ResultAccumulator.
+func NewResultAccumulator() *ResultAccumulator {
+	return &ResultAccumulator{}
+}
+
+// Add records the access for a single resource and verb. The resource is
+// qualified with its API group as in ResourceAccess, e.g. deployments.apps.
+// Recording the same resource and verb again overwrites the previous outcome.
+func (a *ResultAccumulator) Add(resource, verb string, access Access) {
+	a.acc.Add(resource, verb, result.Access(access))
+}
+
+// AddResource records the access for several verbs of the given resource.
+// Verbs which are not contained in access keep their previous outcome.
+func (a *ResultAccumulator) AddResource(resource string, access map[string]Access) {
+	a.acc.AddResource(resource, toVerbAccess(access))
+}
+
+// Result returns a copy of all outcomes recorded so far. It may be called
+// while outcomes are still added, and later additions do not affect the
+// returned ResourceAccess.
+func (a *ResultAccumulator) Result() ResourceAccess {
+	return fromResourceAccess(a.acc.Result())
+}
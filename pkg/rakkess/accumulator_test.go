@@ -0,0 +1,47 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rakkess
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultAccumulator(t *testing.T) {
+	var acc ResultAccumulator
+
+	var wg sync.WaitGroup
+	for _, verb := range []string{"get", "list", "delete"} {
+		wg.Add(1)
+		go func(verb string) {
+			defer wg.Done()
+			acc.Add("pods", verb, Allowed)
+		}(verb)
+	}
+	wg.Wait()
+	acc.AddResource("deployments.apps", map[string]Access{"get": Denied, "list": RequestErr})
+	snapshot := acc.Result()
+	acc.Add("pods", "delete", Denied)
+
+	assert.Equal(t, ResourceAccess{
+		"pods":             {"get": Allowed, "list": Allowed, "delete": Allowed},
+		"deployments.apps": {"get": Denied, "list": RequestErr},
+	}, snapshot)
+	assert.Equal(t, Denied, acc.Result()["pods"]["delete"])
+}
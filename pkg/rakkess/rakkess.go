@@ -0,0 +1,98 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rakkess computes access matrices for use in other Go programs. It
+// is the stable API of what the rakkess command line shows, and takes a
+// rest.Config instead of reading the kubeconfig.
+//
+// Resources reviews the access of the configured identity to all resources,
+// and Subjects determines which subjects have access to one resource.
+// ResultAccumulator collects the outcomes of access reviews which are sent by
+// the caller.
+package rakkess
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	cli "github.com/corneliusweig/rakkess/internal"
+	"github.com/corneliusweig/rakkess/internal/client"
+	"github.com/corneliusweig/rakkess/internal/constants"
+	"github.com/corneliusweig/rakkess/internal/options"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+// DefaultVerbs are reviewed if Options.Verbs is empty.
+var DefaultVerbs = []string{"list", "create", "update", "delete"}
+
+// Options configures a review. The zero value reviews DefaultVerbs at
+// cluster scope.
+type Options struct {
+	// Verbs are the verbs to review, out of get, list, watch, create, update,
+	// patch, delete, and deletecollection.
+	Verbs []string
+	// Namespace restricts the review to the namespaced resources in this
+	// namespace. If empty, the review is at cluster scope.
+	Namespace string
+	// ResourceName reviews the access to the objects with this name, which
+	// matters for RBAC rules with resourceNames.
+	ResourceName string
+	// MaxConcurrency limits the access reviews which are sent at once. Zero
+	// uses the default of the command line.
+	MaxConcurrency int
+}
+
+// Resources reviews the access of the identity of config to every resource
+// which the API server serves. Impersonation is configured in config.
+func Resources(ctx context.Context, config *rest.Config, o Options) (ResourceAccess, error) {
+	ra, err := cli.Resource(ctx, o.rakkessOptions(config))
+	return fromResourceAccess(ra), err
+}
+
+// Subjects determines the subjects which RBAC grants access to the resource,
+// by evaluating all (Cluster)Roles and their bindings. The identity of config
+// needs to list them. Unlike Resources, it does not depend on the verbs.
+func Subjects(ctx context.Context, config *rest.Config, gr schema.GroupResource, o Options) (*SubjectAccess, error) {
+	sa, err := client.GetSubjectAccess(ctx, o.rakkessOptions(config), gr, o.ResourceName)
+	return fromSubjectAccess(sa), errors.Wrap(err, "get subject access")
+}
+
+func (o Options) rakkessOptions(config *rest.Config) *options.RakkessOptions {
+	opts := options.NewRakkessOptions()
+	opts.RESTConfig = config
+	opts.ConfigFlags.Namespace = &o.Namespace
+	opts.Verbs = o.Verbs
+	if len(opts.Verbs) == 0 {
+		opts.Verbs = append([]string(nil), DefaultVerbs...)
+	}
+	opts.ResourceName = o.ResourceName
+	opts.MaxConcurrency = o.MaxConcurrency
+	if opts.MaxConcurrency == 0 {
+		opts.MaxConcurrency = constants.DefaultMaxConcurrency
+	}
+	opts.MaxRetries = constants.DefaultMaxRetries
+	opts.PreferredOnly = true
+	// the matrix is returned instead of printed
+	opts.OutputFormat = constants.OutputIconTable
+	opts.NoProgress = true
+	opts.NoPager = true
+	opts.Streams = &genericclioptions.IOStreams{In: strings.NewReader(""), Out: io.Discard, ErrOut: io.Discard}
+	return opts
+}
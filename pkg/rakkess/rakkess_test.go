@@ -0,0 +1,84 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rakkess
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// fakeAPIServer serves the core API with pods and allows only to list them.
+func fakeAPIServer(t *testing.T) *httptest.Server {
+	respond := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, metav1.APIVersions{TypeMeta: metav1.TypeMeta{Kind: "APIVersions"}, Versions: []string{"v1"}})
+	})
+	mux.HandleFunc("/apis", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, metav1.APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}})
+	})
+	mux.HandleFunc("/api/v1", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"list", "delete"}}},
+		})
+	})
+	mux.HandleFunc("/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", func(w http.ResponseWriter, r *http.Request) {
+		var review authv1.SelfSubjectAccessReview
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		review.Status.Allowed = review.Spec.ResourceAttributes.Verb == "list"
+		respond(w, review)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestResources(t *testing.T) {
+	server := fakeAPIServer(t)
+	defer server.Close()
+
+	ra, err := Resources(context.Background(), &rest.Config{Host: server.URL}, Options{Verbs: []string{"list", "delete", "watch"}, Namespace: "default"})
+	require.NoError(t, err)
+	assert.Equal(t, ResourceAccess{
+		"pods": {"list": Allowed, "delete": Denied, "watch": NotApplicable},
+	}, ra)
+}
+
+func TestOptions_rakkessOptions(t *testing.T) {
+	opts := Options{}.rakkessOptions(&rest.Config{Host: "https://example.com"})
+	assert.Equal(t, DefaultVerbs, opts.Verbs)
+	assert.Equal(t, "", *opts.ConfigFlags.Namespace)
+	assert.Positive(t, opts.MaxConcurrency)
+	assert.True(t, opts.NoProgress)
+	assert.True(t, opts.NoPager)
+
+	config, err := opts.ToRESTConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", config.Host)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rakkess
+
+import (
+	"sort"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Access is the outcome of an access review for one resource and verb.
+type Access uint8
+
+// The possible outcomes of an access review.
+const (
+	Denied Access = iota
+	Allowed
+	NotApplicable
+	RequestErr
+)
+
+// String returns a human-readable name of the outcome, e.g. "allowed".
+func (a Access) String() string {
+	return result.Access(a).String()
+}
+
+// ResourceAccess maps the resources, qualified with their API group such as
+// deployments.apps, to the access for every reviewed verb.
+type ResourceAccess map[string]map[string]Access
+
+// SubjectRef identifies a user, group, or service-account. Namespace is only
+// set for service-accounts.
+type SubjectRef struct {
+	Name      string
+	Kind      string
+	Namespace string
+}
+
+// SubjectAccess holds the verbs which every subject is granted on a resource.
+type SubjectAccess struct {
+	// Resource is the resource which was evaluated.
+	Resource schema.GroupResource
+	// ResourceName is the object name which was evaluated, if any.
+	ResourceName string
+	// Verbs maps every subject with access to the sorted verbs it is granted.
+	Verbs map[SubjectRef][]string
+}
+
+// Subjects lists the subjects with access, sorted by name, kind, and
+// namespace.
+func (sa *SubjectAccess) Subjects() []SubjectRef {
+	subjects := make([]SubjectRef, 0, len(sa.Verbs))
+	for s := range sa.Verbs {
+		subjects = append(subjects, s)
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		x, y := subjects[i], subjects[j]
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		return x.Namespace < y.Namespace
+	})
+	return subjects
+}
+
+// The internal result types may change, so they are converted at the
+// boundary of this package.
+
+func fromResourceAccess(ra result.ResourceAccess) ResourceAccess {
+	if ra == nil {
+		return nil
+	}
+	converted := make(ResourceAccess, len(ra))
+	for resource, verbs := range ra {
+		converted[resource] = fromVerbAccess(verbs)
+	}
+	return converted
+}
+
+func fromVerbAccess(access map[string]result.Access) map[string]Access {
+	converted := make(map[string]Access, len(access))
+	for verb, a := range access {
+		converted[verb] = Access(a)
+	}
+	return converted
+}
+
+func toVerbAccess(access map[string]Access) map[string]result.Access {
+	converted := make(map[string]result.Access, len(access))
+	for verb, a := range access {
+		converted[verb] = result.Access(a)
+	}
+	return converted
+}
+
+func fromSubjectAccess(sa *result.SubjectAccess) *SubjectAccess {
+	if sa == nil {
+		return nil
+	}
+	converted := &SubjectAccess{
+		Resource:     sa.GroupResource,
+		ResourceName: sa.ResourceName,
+		Verbs:        make(map[SubjectRef][]string, len(sa.Get())),
+	}
+	for s, verbs := range sa.Get() {
+		converted.Verbs[SubjectRef(s)] = verbs.List()
+	}
+	return converted
+}
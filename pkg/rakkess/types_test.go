@@ -0,0 +1,55 @@
+/*
+Copyright 2026 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rakkess
+
+import (
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/client/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccess_matchesResult(t *testing.T) {
+	for access, internal := range map[Access]result.Access{
+		Denied:        result.Denied,
+		Allowed:       result.Allowed,
+		NotApplicable: result.NotApplicable,
+		RequestErr:    result.RequestErr,
+	} {
+		assert.Equal(t, uint8(internal), uint8(access))
+		assert.Equal(t, internal.String(), access.String())
+	}
+}
+
+func TestFromResourceAccess(t *testing.T) {
+	ra := result.ResourceAccess{"pods": {"list": result.Allowed, "delete": result.RequestErr}}
+	assert.Equal(t, ResourceAccess{"pods": {"list": Allowed, "delete": RequestErr}}, fromResourceAccess(ra))
+	assert.Nil(t, fromResourceAccess(nil))
+}
+
+func TestSubjectAccess_Subjects(t *testing.T) {
+	sa := &SubjectAccess{Verbs: map[SubjectRef][]string{
+		{Name: "b", Kind: "User"}:                           {"get"},
+		{Name: "a", Kind: "ServiceAccount", Namespace: "x"}: {"list"},
+		{Name: "a", Kind: "Group"}:                          {"get"},
+	}}
+	assert.Equal(t, []SubjectRef{
+		{Name: "a", Kind: "Group"},
+		{Name: "a", Kind: "ServiceAccount", Namespace: "x"},
+		{Name: "b", Kind: "User"},
+	}, sa.Subjects())
+}